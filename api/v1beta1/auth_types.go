@@ -48,6 +48,15 @@ type Auth struct {
 	// +optional
 	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
 
+	// HostKey is the expected SSH public key of the host in the authorized_keys format.
+	// +optional
+	HostKey string `yaml:"hostKey,omitempty" json:"hostKey,omitempty"`
+
+	// HostKeyFingerprint is the expected SHA256 (e.g. SHA256:xxx) or MD5 fingerprint of the SSH host key.
+	// The connection is aborted when the host key does not match.
+	// +optional
+	HostKeyFingerprint string `yaml:"hostKeyFingerprint,omitempty" json:"hostKeyFingerprint,omitempty"`
+
 	// Timeout is the timeout for establish an SSH connection.
 	// +optional
 	Timeout *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
@@ -22,6 +22,10 @@ type Addon struct {
 	Sources   Sources `yaml:"sources" json:"sources,omitempty"`
 	Retries   int     `yaml:"retries" json:"retries,omitempty"`
 	Delay     int     `yaml:"delay" json:"delay,omitempty"`
+	// DependsOn are the names of the addons which must be installed and ready before this addon.
+	DependsOn []string `yaml:"dependsOn" json:"dependsOn,omitempty"`
+	// Timeout is the seconds to wait for the resources of the addon to be ready. The default is 300.
+	Timeout int `yaml:"timeout" json:"timeout,omitempty"`
 }
 
 type Sources struct {
@@ -30,7 +34,11 @@ type Sources struct {
 }
 
 type Chart struct {
-	Name       string   `yaml:"name" json:"name,omitempty"`
+	// Name is the name of the chart, or the reference of the chart in an OCI registry, such as
+	// oci://harbor.example.com/library/nginx.
+	Name string `yaml:"name" json:"name,omitempty"`
+	// Repo is the url of the chart repository, or the OCI registry path of the chart, such as
+	// oci://harbor.example.com/library.
 	Repo       string   `yaml:"repo" json:"repo,omitempty"`
 	Path       string   `yaml:"path" json:"path,omitempty"`
 	Version    string   `yaml:"version" json:"version,omitempty"`
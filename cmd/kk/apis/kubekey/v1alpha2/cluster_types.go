@@ -27,7 +27,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
 )
 
@@ -45,6 +44,7 @@ type ClusterSpec struct {
 	Registry             RegistryConfig       `yaml:"registry" json:"registry,omitempty"`
 	Addons               []Addon              `yaml:"addons" json:"addons,omitempty"`
 	KubeSphere           KubeSphere           `json:"kubesphere,omitempty"`
+	DownloadMirrors      []DownloadMirror     `yaml:"downloadMirrors" json:"downloadMirrors,omitempty"`
 }
 
 type Cluster struct {
@@ -67,6 +67,14 @@ type HostCfg struct {
 	Arch            string `yaml:"arch,omitempty" json:"arch,omitempty"`
 	Timeout         *int64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 
+	// HostKey is the expected SSH public key of the host in the authorized_keys format.
+	HostKey string `yaml:"hostKey,omitempty" json:"hostKey,omitempty"`
+	// HostKeyFingerprint is the expected SHA256 (e.g. SHA256:xxx) or MD5 fingerprint of the SSH host key.
+	HostKeyFingerprint string `yaml:"hostKeyFingerprint,omitempty" json:"hostKeyFingerprint,omitempty"`
+
+	// InternalIPv6Address is the IPv6 address of the host, it is required by the nodes of a dual-stack cluster.
+	InternalIPv6Address string `yaml:"internalIPv6Address,omitempty" json:"internalIPv6Address,omitempty"`
+
 	// Labels defines the kubernetes labels for the node.
 	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
 }
@@ -92,6 +100,26 @@ type CustomScripts struct {
 	Materials []string `yaml:"materials" json:"materials,omitempty"`
 }
 
+// Hook defines the custom shell script which runs on the selected nodes before or after a module of the pipelines.
+type Hook struct {
+	Name      string   `yaml:"name" json:"name,omitempty"`
+	Bash      string   `yaml:"bash" json:"bash,omitempty"`
+	Materials []string `yaml:"materials" json:"materials,omitempty"`
+	// Before and After are the module which the hook runs before or after. The module is referred by the name of its
+	// type, with or without the package name, such as JoinNodesModule or kubernetes.JoinNodesModule. Only one of them
+	// can be specified.
+	Before string `yaml:"before" json:"before,omitempty"`
+	After  string `yaml:"after" json:"after,omitempty"`
+	// Roles and Labels select the nodes which the hook runs on. A node is selected if it has any of the roles and all
+	// the labels, and all the nodes are selected if both of them are empty.
+	Roles  []string          `yaml:"roles" json:"roles,omitempty"`
+	Labels map[string]string `yaml:"labels" json:"labels,omitempty"`
+	// Timeout is the seconds to wait for the hook to finish on the selected nodes.
+	Timeout int `yaml:"timeout" json:"timeout,omitempty"`
+	// FailurePolicy is fail or ignore, the pipeline goes on if the hook fails with ignore. [Default: fail]
+	FailurePolicy string `yaml:"failurePolicy" json:"failurePolicy,omitempty"`
+}
+
 // System defines the system config for each node in cluster.
 type System struct {
 	NtpServers      []string        `yaml:"ntpServers" json:"ntpServers,omitempty"`
@@ -101,6 +129,35 @@ type System struct {
 	PreInstall      []CustomScripts `yaml:"preInstall" json:"preInstall,omitempty"`
 	PostInstall     []CustomScripts `yaml:"postInstall" json:"postInstall,omitempty"`
 	SkipConfigureOS bool            `yaml:"skipConfigureOS" json:"skipConfigureOS,omitempty"`
+	// Hooks are the custom shell scripts which run before or after the modules of the pipelines.
+	Hooks []Hook `yaml:"hooks" json:"hooks,omitempty"`
+}
+
+// ValidateHooks checks the hooks of the system.
+func (s *System) ValidateHooks() error {
+	names := make(map[string]struct{}, len(s.Hooks))
+	for _, hook := range s.Hooks {
+		// the hooks are recorded by their names in the checkpoint journal
+		if _, ok := names[hook.Name]; ok {
+			return errors.Errorf("the name of hook %s is duplicated", hook.Name)
+		}
+		names[hook.Name] = struct{}{}
+		if hook.Bash == "" {
+			return errors.Errorf("the bash of hook %s is empty", hook.Name)
+		}
+		if (hook.Before == "") == (hook.After == "") {
+			return errors.Errorf("hook %s must specify either before or after", hook.Name)
+		}
+		if hook.Timeout < 0 {
+			return errors.Errorf("the timeout of hook %s must not be negative", hook.Name)
+		}
+		switch hook.FailurePolicy {
+		case "", HookFailurePolicyFail, HookFailurePolicyIgnore:
+		default:
+			return errors.Errorf("the failurePolicy of hook %s must be %s or %s", hook.Name, HookFailurePolicyFail, HookFailurePolicyIgnore)
+		}
+	}
+	return nil
 }
 
 // RegistryConfig defines the configuration information of the image's repository.
@@ -143,9 +200,17 @@ func (cfg *ClusterSpec) GenerateCertSANs() []string {
 		if host.InternalAddress != host.Address && host.InternalAddress != cfg.ControlPlaneEndpoint.Address {
 			extraCertSANs = append(extraCertSANs, host.InternalAddress)
 		}
+		if host.InternalIPv6Address != "" && host.InternalIPv6Address != host.Address {
+			extraCertSANs = append(extraCertSANs, host.InternalIPv6Address)
+		}
 	}
 
-	extraCertSANs = append(extraCertSANs, util.ParseIp(cfg.Network.KubeServiceCIDR)[0])
+	// the kube-apiserver service has an address in each family of a dual-stack cluster
+	for _, cidr := range strings.Split(cfg.Network.KubeServiceCIDR, ",") {
+		if ip, err := util.GetIndexedIP(cidr, 1); err == nil {
+			extraCertSANs = append(extraCertSANs, ip)
+		}
+	}
 
 	defaultCertSANs = append(defaultCertSANs, extraCertSANs...)
 
@@ -156,25 +221,28 @@ func (cfg *ClusterSpec) GenerateCertSANs() []string {
 	return defaultCertSANs
 }
 
-// GroupHosts is used to group hosts according to the configuration file.s
-func (cfg *ClusterSpec) GroupHosts() map[string][]*KubeHost {
+// GroupHosts is used to group hosts according to the configuration file.
+func (cfg *ClusterSpec) GroupHosts() (map[string][]*KubeHost, error) {
 	hostMap := make(map[string]*KubeHost)
 	for _, hostCfg := range cfg.Hosts {
 		host := toHosts(hostCfg)
 		hostMap[host.Name] = host
 	}
 
-	roleGroups := cfg.ParseRolesList(hostMap)
+	roleGroups, err := cfg.ParseRolesList(hostMap)
+	if err != nil {
+		return nil, err
+	}
 
 	//Check that the parameters under roleGroups are incorrect
 	if len(roleGroups[Master]) == 0 && len(roleGroups[ControlPlane]) == 0 {
-		logger.Log.Fatal(errors.New("The number of master/control-plane cannot be 0"))
+		return nil, errors.New("The number of master/control-plane cannot be 0")
 	}
 	if len(roleGroups[Etcd]) == 0 && cfg.Etcd.Type == KubeKey {
-		logger.Log.Fatal(errors.New("The number of etcd cannot be 0"))
+		return nil, errors.New("The number of etcd cannot be 0")
 	}
 	if len(roleGroups[Registry]) > 1 {
-		logger.Log.Fatal(errors.New("The number of registry node cannot be greater than 1."))
+		return nil, errors.New("The number of registry node cannot be greater than 1.")
 	}
 
 	for _, host := range roleGroups[ControlPlane] {
@@ -182,7 +250,7 @@ func (cfg *ClusterSpec) GroupHosts() map[string][]*KubeHost {
 		roleGroups[Master] = append(roleGroups[Master], host)
 	}
 
-	return roleGroups
+	return roleGroups, nil
 }
 
 // +kubebuilder:object:generate=false
@@ -196,11 +264,14 @@ func toHosts(cfg HostCfg) *KubeHost {
 	host.Name = cfg.Name
 	host.Address = cfg.Address
 	host.InternalAddress = cfg.InternalAddress
+	host.InternalIPv6Address = cfg.InternalIPv6Address
 	host.Port = cfg.Port
 	host.User = cfg.User
 	host.Password = cfg.Password
 	host.PrivateKey = cfg.PrivateKey
 	host.PrivateKeyPath = cfg.PrivateKeyPath
+	host.HostKey = cfg.HostKey
+	host.HostKeyFingerprint = cfg.HostKeyFingerprint
 	host.Arch = cfg.Arch
 	host.Timeout = *cfg.Timeout
 
@@ -211,14 +282,18 @@ func toHosts(cfg HostCfg) *KubeHost {
 	return kubeHost
 }
 
-// ClusterIP is used to get the kube-apiserver service address inside the cluster.
+// ClusterIP is used to get the kube-apiserver service address inside the cluster. The first service CIDR is used
+// in a dual-stack cluster.
 func (cfg *ClusterSpec) ClusterIP() string {
-	return util.ParseIp(cfg.Network.KubeServiceCIDR)[0]
+	ip, _ := util.GetIndexedIP(strings.Split(cfg.Network.KubeServiceCIDR, ",")[0], 1)
+	return ip
 }
 
-// CorednsClusterIP is used to get the coredns service address inside the cluster.
+// CorednsClusterIP is used to get the coredns service address inside the cluster. The first service CIDR is used
+// in a dual-stack cluster.
 func (cfg *ClusterSpec) CorednsClusterIP() string {
-	return util.ParseIp(cfg.Network.KubeServiceCIDR)[2]
+	ip, _ := util.GetIndexedIP(strings.Split(cfg.Network.KubeServiceCIDR, ",")[0], 3)
+	return ip
 }
 
 // ClusterDNS is used to get the dns server address inside the cluster.
@@ -231,18 +306,21 @@ func (cfg *ClusterSpec) ClusterDNS() string {
 }
 
 // ParseRolesList is used to parse the host grouping list.
-func (cfg *ClusterSpec) ParseRolesList(hostMap map[string]*KubeHost) map[string][]*KubeHost {
+func (cfg *ClusterSpec) ParseRolesList(hostMap map[string]*KubeHost) (map[string][]*KubeHost, error) {
 	roleGroupLists := make(map[string][]*KubeHost)
 	for role, hosts := range cfg.RoleGroups {
 		roleGroup := make([]string, 0)
 		for _, host := range hosts {
 			h := make([]string, 0)
 			if strings.Contains(host, "[") && strings.Contains(host, "]") && strings.Contains(host, ":") {
-				rangeHosts := getHostsRange(host, hostMap, role)
+				rangeHosts, err := getHostsRange(host, hostMap, role)
+				if err != nil {
+					return nil, err
+				}
 				h = append(h, rangeHosts...)
 			} else {
 				if err := hostVerify(hostMap, host, role); err != nil {
-					logger.Log.Fatal(err)
+					return nil, err
 				}
 				h = append(h, host)
 			}
@@ -252,13 +330,13 @@ func (cfg *ClusterSpec) ParseRolesList(hostMap map[string]*KubeHost) map[string]
 				if h, ok := hostMap[hostName]; ok {
 					roleGroupAppend(roleGroupLists, role, h)
 				} else {
-					logger.Log.Fatal(fmt.Errorf("incorrect nodeName under roleGroups/%s in the configuration file", role))
+					return nil, fmt.Errorf("incorrect nodeName under roleGroups/%s in the configuration file", role)
 				}
 			}
 		}
 	}
 
-	return roleGroupLists
+	return roleGroupLists, nil
 }
 
 func roleGroupAppend(roleGroupLists map[string][]*KubeHost, role string, host *KubeHost) {
@@ -268,20 +346,23 @@ func roleGroupAppend(roleGroupLists map[string][]*KubeHost, role string, host *K
 	roleGroupLists[role] = r
 }
 
-func getHostsRange(rangeStr string, hostMap map[string]*KubeHost, group string) []string {
+func getHostsRange(rangeStr string, hostMap map[string]*KubeHost, group string) ([]string, error) {
 	hostRangeList := make([]string, 0)
 	r := regexp.MustCompile(`\[(\d+)\:(\d+)\]`)
 	nameSuffix := r.FindStringSubmatch(rangeStr)
+	if nameSuffix == nil {
+		return nil, fmt.Errorf("incorrect host range %s under roleGroups/%s in the configuration file", rangeStr, group)
+	}
 	namePrefix := strings.Split(rangeStr, nameSuffix[0])[0]
 	nameSuffixStart, _ := strconv.Atoi(nameSuffix[1])
 	nameSuffixEnd, _ := strconv.Atoi(nameSuffix[2])
 	for i := nameSuffixStart; i <= nameSuffixEnd; i++ {
 		if err := hostVerify(hostMap, fmt.Sprintf("%s%d", namePrefix, i), group); err != nil {
-			logger.Log.Fatal(err)
+			return nil, err
 		}
 		hostRangeList = append(hostRangeList, fmt.Sprintf("%s%d", namePrefix, i))
 	}
-	return hostRangeList
+	return hostRangeList, nil
 }
 
 func hostVerify(hostMap map[string]*KubeHost, hostName string, group string) error {
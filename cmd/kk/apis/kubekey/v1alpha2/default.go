@@ -61,6 +61,7 @@ const (
 	DefaultMaxPods                 = 110
 	DefaultPodPidsLimit            = 10000
 	DefaultNodeCidrMaskSize        = 24
+	DefaultNodeCidrMaskSizeIPv6    = 64
 	DefaultIPIPMode                = "Always"
 	DefaultVXLANMode               = "Never"
 	DefaultVethMTU                 = 0
@@ -99,18 +100,24 @@ const (
 	Crio       = "crio"
 	Isula      = "isula"
 
+	HookFailurePolicyFail   = "fail"
+	HookFailurePolicyIgnore = "ignore"
+
 	Haproxy            = "haproxy"
 	Kubevip            = "kube-vip"
 	DefaultKubeVipMode = "ARP"
 )
 
-func (cfg *ClusterSpec) SetDefaultClusterSpec() (*ClusterSpec, map[string][]*KubeHost) {
+func (cfg *ClusterSpec) SetDefaultClusterSpec() (*ClusterSpec, map[string][]*KubeHost, error) {
 	clusterCfg := ClusterSpec{}
 
 	clusterCfg.Hosts = SetDefaultHostsCfg(cfg)
 	clusterCfg.RoleGroups = cfg.RoleGroups
 	clusterCfg.Etcd = SetDefaultEtcdCfg(cfg)
-	roleGroups := clusterCfg.GroupHosts()
+	roleGroups, err := clusterCfg.GroupHosts()
+	if err != nil {
+		return nil, nil, err
+	}
 	clusterCfg.ControlPlaneEndpoint = SetDefaultLBCfg(cfg, roleGroups[Master])
 	clusterCfg.Network = SetDefaultNetworkCfg(cfg)
 	clusterCfg.Storage = SetDefaultStorageCfg(cfg)
@@ -120,6 +127,7 @@ func (cfg *ClusterSpec) SetDefaultClusterSpec() (*ClusterSpec, map[string][]*Kub
 	clusterCfg.Registry = cfg.Registry
 	clusterCfg.Addons = cfg.Addons
 	clusterCfg.KubeSphere = cfg.KubeSphere
+	clusterCfg.DownloadMirrors = cfg.DownloadMirrors
 
 	if cfg.Kubernetes.ClusterName == "" {
 		clusterCfg.Kubernetes.ClusterName = DefaultClusterName
@@ -136,10 +144,13 @@ func (cfg *ClusterSpec) SetDefaultClusterSpec() (*ClusterSpec, map[string][]*Kub
 	if cfg.Kubernetes.NodeCidrMaskSize == 0 {
 		clusterCfg.Kubernetes.NodeCidrMaskSize = DefaultNodeCidrMaskSize
 	}
+	if cfg.Kubernetes.NodeCidrMaskSizeIPv6 == 0 {
+		clusterCfg.Kubernetes.NodeCidrMaskSizeIPv6 = DefaultNodeCidrMaskSizeIPv6
+	}
 	if cfg.Kubernetes.ProxyMode == "" {
 		clusterCfg.Kubernetes.ProxyMode = DefaultProxyMode
 	}
-	return &clusterCfg, roleGroups
+	return &clusterCfg, roleGroups, nil
 }
 
 func SetDefaultHostsCfg(cfg *ClusterSpec) []HostCfg {
@@ -0,0 +1,60 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// DownloadMirror is a mirror of the binary files. The mirrors are tried in order, and the default download source
+// is the last fallback.
+type DownloadMirror struct {
+	Name string `yaml:"name" json:"name,omitempty"`
+	// URLs are the URL templates of the components, the key is the component id, e.g. kubeadm, etcd, containerd
+	// and helm. The fields {{ .ID }}, {{ .Version }}, {{ .Arch }}, {{ .ArchAlias }} and {{ .FileName }} are
+	// available in the template.
+	URLs map[string]string `yaml:"urls" json:"urls,omitempty"`
+}
+
+// DownloadMirrorData is the data to render the URL templates of a DownloadMirror.
+type DownloadMirrorData struct {
+	ID        string
+	Version   string
+	Arch      string
+	ArchAlias string
+	FileName  string
+}
+
+// URL renders the URL of the component, it returns false if the mirror does not provide the component.
+func (m DownloadMirror) URL(data DownloadMirrorData) (string, bool, error) {
+	text, ok := m.URLs[data.ID]
+	if !ok || text == "" {
+		return "", false, nil
+	}
+	tmpl, err := template.New(data.ID).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "parse the URL template of %s in the download mirror %s failed", data.ID, m.Name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", false, errors.Wrapf(err, "render the URL of %s in the download mirror %s failed", data.ID, m.Name)
+	}
+	return buf.String(), true, nil
+}
@@ -42,6 +42,8 @@ type EtcdCluster struct {
 	MaxSnapshots            *int         `yaml:"maxSnapshots" json:"maxSnapshots,omitempty"`
 	MaxWals                 *int         `yaml:"maxWals" json:"maxWals,omitempty"`
 	LogLevel                *string      `yaml:"logLevel" json:"logLevel"`
+	// BackupTarget is the remote storage where the snapshots taken by "kk backup etcd" are uploaded to.
+	BackupTarget *EtcdBackupTarget `yaml:"backupTarget" json:"backupTarget,omitempty"`
 }
 
 // ExternalEtcd describes how to connect to an external etcd cluster
@@ -57,3 +59,38 @@ type ExternalEtcd struct {
 	// KeyFile is an SSL key file used to secure etcd communication.
 	KeyFile string `yaml:"keyFile" json:"keyFile,omitempty"`
 }
+
+// EtcdBackupTarget describes the remote storage of the etcd snapshots, the snapshots beyond KeepBackupNumber
+// are pruned from it. S3 and SFTP are mutually exclusive
+type EtcdBackupTarget struct {
+	S3   *S3BackupTarget   `yaml:"s3" json:"s3,omitempty"`
+	SFTP *SFTPBackupTarget `yaml:"sftp" json:"sftp,omitempty"`
+}
+
+// S3BackupTarget is an S3-compatible object storage, e.g. AWS S3 or MinIO.
+type S3BackupTarget struct {
+	// Endpoint of the object storage, the AWS endpoint of the region is used if it is empty.
+	Endpoint string `yaml:"endpoint" json:"endpoint,omitempty"`
+	Region   string `yaml:"region" json:"region,omitempty"`
+	Bucket   string `yaml:"bucket" json:"bucket,omitempty"`
+	// Prefix is prepended to the object keys of the snapshots, e.g. "etcd/cluster-1/".
+	Prefix          string `yaml:"prefix" json:"prefix,omitempty"`
+	AccessKeyID     string `yaml:"accessKeyID" json:"accessKeyID,omitempty"`
+	SecretAccessKey string `yaml:"secretAccessKey" json:"secretAccessKey,omitempty"`
+	// ForcePathStyle uses the path-style addressing of the buckets, it is required by most S3-compatible storages.
+	ForcePathStyle bool `yaml:"forcePathStyle" json:"forcePathStyle,omitempty"`
+	// Insecure uses HTTP instead of HTTPS.
+	Insecure bool `yaml:"insecure" json:"insecure,omitempty"`
+}
+
+// SFTPBackupTarget is a directory on an SFTP server.
+type SFTPBackupTarget struct {
+	// Address of the SFTP server, e.g. 192.168.0.10:22.
+	Address        string `yaml:"address" json:"address,omitempty"`
+	User           string `yaml:"user" json:"user,omitempty"`
+	Password       string `yaml:"password" json:"password,omitempty"`
+	PrivateKeyPath string `yaml:"privateKeyPath" json:"privateKeyPath,omitempty"`
+	// HostKey pins the public key of the SFTP server in the authorized_keys format.
+	HostKey string `yaml:"hostKey" json:"hostKey,omitempty"`
+	Dir     string `yaml:"dir" json:"dir,omitempty"`
+}
@@ -47,6 +47,15 @@ type Kubernetes struct {
 	KubeletConfiguration     runtime.RawExtension `yaml:"kubeletConfiguration" json:"kubeletConfiguration,omitempty"`
 	KubeProxyConfiguration   runtime.RawExtension `yaml:"kubeProxyConfiguration" json:"kubeProxyConfiguration,omitempty"`
 	Audit                    Audit                `yaml:"audit" json:"audit,omitempty"`
+	// SkipPhases are the phases skipped by kubeadm init and kubeadm join, such as addon/kube-proxy. The phases which
+	// only exist in kubeadm init are not skipped by kubeadm join.
+	SkipPhases []string `yaml:"skipPhases" json:"skipPhases,omitempty"`
+	// KubeadmPatches are the kubeadm patches of the control plane components and the kubelet, the key is the file
+	// name of the patch, such as kube-apiserver0+strategic.yaml, and the value is the content. They are only used
+	// with Kubernetes v1.22 and later.
+	KubeadmPatches map[string]string `yaml:"kubeadmPatches" json:"kubeadmPatches,omitempty"`
+	// NodeCidrMaskSizeIPv6 is the mask size of the IPv6 pod CIDR of each node in a dual-stack cluster.
+	NodeCidrMaskSizeIPv6 int `yaml:"nodeCidrMaskSizeIPv6" json:"nodeCidrMaskSizeIPv6,omitempty"`
 }
 
 // Kata contains the configuration for the kata in cluster
@@ -109,6 +109,12 @@ type ManifestSpec struct {
 	Components              Components               `yaml:"components" json:"components"`
 	Images                  []string                 `yaml:"images" json:"images"`
 	ManifestRegistry        ManifestRegistry         `yaml:"registry" json:"registry"`
+	DownloadMirrors         []DownloadMirror         `yaml:"downloadMirrors" json:"downloadMirrors,omitempty"`
+	// Charts are the charts of the addons pulled into the artifact, so that the addons can be installed offline.
+	Charts []Chart `yaml:"charts" json:"charts,omitempty"`
+	// SignaturePolicy is the path of the containers-policy.json which the images are verified with when they are
+	// pulled into the artifact, it is carried in the artifact and enforced again when the images are pushed.
+	SignaturePolicy string `yaml:"signaturePolicy" json:"signaturePolicy,omitempty"`
 }
 
 // Manifest is the Schema for the manifests API
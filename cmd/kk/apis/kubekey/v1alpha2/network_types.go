@@ -16,6 +16,12 @@
 
 package v1alpha2
 
+import (
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+)
+
 type NetworkConfig struct {
 	Plugin          string       `yaml:"plugin" json:"plugin,omitempty"`
 	KubePodsCIDR    string       `yaml:"kubePodsCIDR" json:"kubePodsCIDR,omitempty"`
@@ -167,6 +173,88 @@ func (n *NetworkConfig) EnableMultusCNI() bool {
 	return *n.MultusCNI.Enabled
 }
 
+// EnableDualStack is used to determine whether the pods and the services have both IPv4 and IPv6 addresses. The
+// kubePodsCIDR and the kubeServiceCIDR of a dual-stack cluster are an IPv4 CIDR and an IPv6 CIDR separated by a comma.
+func (n *NetworkConfig) EnableDualStack() bool {
+	ipv4, ipv6 := util.SplitCIDRs(n.KubePodsCIDR)
+	return ipv4 != "" && ipv6 != ""
+}
+
+// KubePodsIPv4CIDR returns the IPv4 CIDR of the pods, it is empty in an IPv6 single-stack cluster.
+func (n *NetworkConfig) KubePodsIPv4CIDR() string {
+	ipv4, _ := util.SplitCIDRs(n.KubePodsCIDR)
+	return ipv4
+}
+
+// KubePodsIPv6CIDR returns the IPv6 CIDR of the pods, it is empty in an IPv4 single-stack cluster.
+func (n *NetworkConfig) KubePodsIPv6CIDR() string {
+	_, ipv6 := util.SplitCIDRs(n.KubePodsCIDR)
+	return ipv6
+}
+
+// ValidateNetwork checks the CIDRs of the pods and the services. Both of them have a single CIDR, or an IPv4 CIDR and
+// an IPv6 CIDR in a dual-stack cluster, and they must be in the same IP families.
+func (cfg *ClusterSpec) ValidateNetwork() error {
+	pods, err := cidrFamilies("kubePodsCIDR", cfg.Network.KubePodsCIDR)
+	if err != nil {
+		return err
+	}
+	services, err := cidrFamilies("kubeServiceCIDR", cfg.Network.KubeServiceCIDR)
+	if err != nil {
+		return err
+	}
+	if len(pods) != len(services) {
+		return errors.Errorf("kubePodsCIDR %s has %d IP families but kubeServiceCIDR %s has %d, they must be the same",
+			cfg.Network.KubePodsCIDR, len(pods), cfg.Network.KubeServiceCIDR, len(services))
+	}
+	if len(pods) == 1 && pods[0] != services[0] {
+		return errors.Errorf("kubePodsCIDR %s and kubeServiceCIDR %s must be in the same IP family",
+			cfg.Network.KubePodsCIDR, cfg.Network.KubeServiceCIDR)
+	}
+
+	if len(pods) == 2 && cfg.Network.Plugin == "flannel" {
+		return errors.New("the network plugin flannel does not support dual-stack")
+	}
+
+	// kube-apiserver refuses the IPv6 service CIDR whose mask is less than 108
+	_, serviceIPv6 := util.SplitCIDRs(cfg.Network.KubeServiceCIDR)
+	if ipNets, err := util.ParseCIDRs(serviceIPv6); err == nil {
+		if ones, _ := ipNets[0].Mask.Size(); ones < 108 {
+			return errors.Errorf("the IPv6 kubeServiceCIDR %s is too large, the mask must be at least 108", serviceIPv6)
+		}
+	}
+
+	// kube-controller-manager allocates the pod CIDR of each node from the cluster CIDR, the node mask must be larger
+	// than the cluster mask and the difference is at most 16
+	if ipNets, err := util.ParseCIDRs(cfg.Network.KubePodsIPv6CIDR()); err == nil && len(pods) == 2 {
+		ones, _ := ipNets[0].Mask.Size()
+		if size := cfg.Kubernetes.NodeCidrMaskSizeIPv6; size <= ones || size-ones > 16 {
+			return errors.Errorf("nodeCidrMaskSizeIPv6 %d is invalid for the IPv6 kubePodsCIDR %s, it must be larger than %d and at most %d",
+				size, cfg.Network.KubePodsIPv6CIDR(), ones, ones+16)
+		}
+	}
+	return nil
+}
+
+// cidrFamilies returns whether each of the comma-separated CIDRs is IPv6.
+func cidrFamilies(name, cidrs string) ([]bool, error) {
+	ipNets, err := util.ParseCIDRs(cidrs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", name)
+	}
+	if len(ipNets) > 2 {
+		return nil, errors.Errorf("%s %s has more than two CIDRs", name, cidrs)
+	}
+	families := make([]bool, 0, len(ipNets))
+	for _, ipNet := range ipNets {
+		families = append(families, ipNet.IP.To4() == nil)
+	}
+	if len(families) == 2 && families[0] == families[1] {
+		return nil, errors.Errorf("the dual-stack %s %s must have an IPv4 CIDR and an IPv6 CIDR", name, cidrs)
+	}
+	return families, nil
+}
+
 // EnableIPV4POOL_NAT_OUTGOING is used to determine whether to enable CALICO_IPV4POOL_NAT_OUTGOING.
 func (c *CalicoCfg) EnableIPV4POOL_NAT_OUTGOING() bool {
 	if c.Ipv4NatOutgoing == nil {
@@ -0,0 +1,72 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNetwork(t *testing.T) {
+	tests := []struct {
+		name     string
+		pods     string
+		services string
+		plugin   string
+		err      string
+	}{
+		{name: "IPv4", pods: "10.233.64.0/18", services: "10.233.0.0/18"},
+		{name: "dual-stack", pods: "10.233.64.0/18,fd00:10:233::/56", services: "10.233.0.0/18,fd00:10:96::/108"},
+		{name: "dual-stack in the different orders", pods: "fd00:10:233::/56,10.233.64.0/18", services: "10.233.0.0/18,fd00:10:96::/108"},
+		{name: "invalid CIDR", pods: "10.233.64.0", services: "10.233.0.0/18", err: "invalid kubePodsCIDR"},
+		{name: "two IPv4 CIDRs", pods: "10.233.64.0/18,10.234.64.0/18", services: "10.233.0.0/18", err: "must have an IPv4 CIDR and an IPv6 CIDR"},
+		{name: "mismatched families", pods: "10.233.64.0/18,fd00:10:233::/56", services: "10.233.0.0/18", err: "has 2 IP families but kubeServiceCIDR 10.233.0.0/18 has 1"},
+		{name: "different families", pods: "fd00:10:233::/56", services: "10.233.0.0/18", err: "must be in the same IP family"},
+		{name: "large IPv6 services", pods: "10.233.64.0/18,fd00:10:233::/56", services: "10.233.0.0/18,fd00:10:96::/64", err: "the mask must be at least 108"},
+		{name: "small IPv6 pods", pods: "10.233.64.0/18,fd00:10:233::/112", services: "10.233.0.0/18,fd00:10:96::/108", err: "nodeCidrMaskSizeIPv6 64 is invalid"},
+		{name: "flannel", pods: "10.233.64.0/18,fd00:10:233::/56", services: "10.233.0.0/18,fd00:10:96::/108", plugin: "flannel", err: "flannel does not support dual-stack"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ClusterSpec{
+				Network:    NetworkConfig{Plugin: tt.plugin, KubePodsCIDR: tt.pods, KubeServiceCIDR: tt.services},
+				Kubernetes: Kubernetes{NodeCidrMaskSizeIPv6: DefaultNodeCidrMaskSizeIPv6},
+			}
+			err := cfg.ValidateNetwork()
+			if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestClusterIP(t *testing.T) {
+	cfg := &ClusterSpec{Network: NetworkConfig{KubeServiceCIDR: "10.233.0.0/18,fd00:10:96::/108"}}
+	if got := cfg.ClusterIP(); got != "10.233.0.1" {
+		t.Errorf("ClusterIP() = %s, want 10.233.0.1", got)
+	}
+	if got := cfg.CorednsClusterIP(); got != "10.233.0.3" {
+		t.Errorf("CorednsClusterIP() = %s, want 10.233.0.3", got)
+	}
+
+	sans := strings.Join(cfg.GenerateCertSANs(), ",")
+	for _, want := range []string{"10.233.0.1", "fd00:10:96::1"} {
+		if !strings.Contains(sans, want) {
+			t.Errorf("the cert SANs %s do not contain %s", sans, want)
+		}
+	}
+}
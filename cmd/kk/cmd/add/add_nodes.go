@@ -28,11 +28,16 @@ import (
 type AddNodesOptions struct {
 	CommonOptions    *options.CommonOptions
 	ClusterCfgFile   string
+	FromCluster      bool
+	KubeConfig       string
 	SkipPullImages   bool
 	ContainerManager string
 	DownloadCmd      string
 	Artifact         string
 	InstallPackages  bool
+	Resume           bool
+	DryRun           bool
+	NoRollback       bool
 }
 
 func NewAddNodesOptions() *AddNodesOptions {
@@ -54,6 +59,9 @@ func NewCmdAddNodes() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddArtifactFlag(cmd)
+	o.CommonOptions.AddDownloadFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
@@ -68,25 +76,42 @@ func (o *AddNodesOptions) Complete(_ *cobra.Command, _ []string) error {
 func (o *AddNodesOptions) Run() error {
 	arg := common.Argument{
 		FilePath:         o.ClusterCfgFile,
+		FromCluster:      o.FromCluster,
+		KubeConfig:       o.KubeConfig,
 		KsEnable:         false,
 		Debug:            o.CommonOptions.Verbose,
+		DownloadProxy:    o.CommonOptions.DownloadProxy,
+		DownloadCABundle: o.CommonOptions.DownloadCABundle,
+		HostKeyChecking:  o.CommonOptions.HostKeyChecking,
+		Output:           o.CommonOptions.Output,
+		ReportFile:       o.CommonOptions.ReportFile,
+		DryRun:           o.DryRun,
+		NoRollback:       o.NoRollback,
 		IgnoreErr:        o.CommonOptions.IgnoreErr,
 		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
 		SkipPullImages:   o.SkipPullImages,
 		ContainerManager: o.ContainerManager,
 		Artifact:         o.Artifact,
+		ArtifactKey:      o.CommonOptions.ArtifactKey,
+		ArtifactCA:       o.CommonOptions.ArtifactCA,
 		InstallPackages:  o.InstallPackages,
 		Namespace:        o.CommonOptions.Namespace,
+		Resume:           o.Resume,
 	}
 	return pipelines.AddNodes(arg, o.DownloadCmd)
 }
 
 func (o *AddNodesOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().BoolVarP(&o.FromCluster, "from-cluster", "", false, "Load the cluster configuration saved in the cluster, the configuration file specified by -f is merged into it as the delta")
+	cmd.Flags().StringVarP(&o.KubeConfig, "kubeconfig", "", "", "Specify a kubeconfig file to access the cluster with --from-cluster")
 	cmd.Flags().BoolVarP(&o.SkipPullImages, "skip-pull-images", "", false, "Skip pre pull images")
 	cmd.Flags().StringVarP(&o.ContainerManager, "container-manager", "", "docker", "Container manager: docker, crio, containerd and isula.")
-	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
-		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
+	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "",
+		`The user defined command to download the necessary binary files instead of the built-in downloader, e.g. "curl -L -o %s %s". The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
 	cmd.Flags().BoolVarP(&o.InstallPackages, "with-packages", "", false, "install operation system packages by artifact")
+	cmd.Flags().BoolVarP(&o.Resume, "resume", "", false, "Resume from the checkpoint of the last execution, the modules that have been completed with the same configuration are skipped")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the operations that would be executed on each host instead of executing them, the pre-checks still run on the hosts")
+	cmd.Flags().BoolVarP(&o.NoRollback, "no-rollback", "", false, "Do not roll back the failed tasks, the failed hosts are left as they are for debugging")
 }
@@ -0,0 +1,47 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/options"
+)
+
+type AddonsOptions struct {
+	CommonOptions *options.CommonOptions
+}
+
+func NewAddonsOptions() *AddonsOptions {
+	return &AddonsOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdAddons creates a new addons command
+func NewCmdAddons() *cobra.Command {
+	o := NewAddonsOptions()
+	cmd := &cobra.Command{
+		Use:   "addons",
+		Short: "Manage the addons of the cluster",
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+
+	cmd.AddCommand(NewCmdAddonsSync())
+	return cmd
+}
@@ -0,0 +1,75 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/options"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/pipelines"
+)
+
+type AddonsSyncOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	FromCluster    bool
+	KubeConfig     string
+	DryRun         bool
+}
+
+func NewAddonsSyncOptions() *AddonsSyncOptions {
+	return &AddonsSyncOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdAddonsSync creates a new addons sync command
+func NewCmdAddonsSync() *cobra.Command {
+	o := NewAddonsSyncOptions()
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync the addons in the cluster with the configuration and report the drift",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *AddonsSyncOptions) Run() error {
+	arg := common.Argument{
+		FilePath:        o.ClusterCfgFile,
+		FromCluster:     o.FromCluster,
+		KubeConfig:      o.KubeConfig,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		DryRun:          o.DryRun,
+	}
+	return pipelines.SyncAddons(arg)
+}
+
+func (o *AddonsSyncOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().BoolVarP(&o.FromCluster, "from-cluster", "", false, "Load the cluster configuration saved in the cluster, the configuration file specified by -f is merged into it as the delta")
+	cmd.Flags().StringVarP(&o.KubeConfig, "kubeconfig", "", "", "Specify a kubeconfig file to access the cluster with --from-cluster")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Only report the drift of the addons without changing anything in the cluster")
+}
@@ -46,5 +46,7 @@ func NewCmdArtifact() *cobra.Command {
 	cmd.AddCommand(NewCmdArtifactExport())
 	cmd.AddCommand(images.NewCmdArtifactImages())
 	cmd.AddCommand(NewCmdArtifactImport())
+	cmd.AddCommand(NewCmdArtifactVerify())
+	cmd.AddCommand(NewCmdArtifactInspect())
 	return cmd
 }
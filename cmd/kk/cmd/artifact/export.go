@@ -18,6 +18,7 @@ package artifact
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -32,6 +33,10 @@ type ArtifactExportOptions struct {
 
 	ManifestFile string
 	Output       string
+	Base         string
+	SignKey      string
+	SignCert     string
+	SignPolicy   string
 	CriSocket    string
 	DownloadCmd  string
 }
@@ -56,6 +61,7 @@ func NewCmdArtifactExport() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddDownloadFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
@@ -71,16 +77,30 @@ func (o *ArtifactExportOptions) Validate(_ []string) error {
 	if o.ManifestFile == "" {
 		return fmt.Errorf("--manifest can not be an empty string")
 	}
+	if o.SignCert != "" && o.SignKey == "" {
+		return fmt.Errorf("--sign-key is required to sign the artifact with the certificate %s", o.SignCert)
+	}
+	if o.Base != "" {
+		if _, err := os.Stat(o.Base); err != nil {
+			return fmt.Errorf("the base artifact %s is not found: %w", o.Base, err)
+		}
+	}
 	return nil
 }
 
 func (o *ArtifactExportOptions) Run() error {
 	arg := common.ArtifactArgument{
-		ManifestFile: o.ManifestFile,
-		Output:       o.Output,
-		CriSocket:    o.CriSocket,
-		Debug:        o.CommonOptions.Verbose,
-		IgnoreErr:    o.CommonOptions.IgnoreErr,
+		ManifestFile:     o.ManifestFile,
+		Output:           o.Output,
+		Base:             o.Base,
+		SignKey:          o.SignKey,
+		SignCert:         o.SignCert,
+		SignaturePolicy:  o.SignPolicy,
+		CriSocket:        o.CriSocket,
+		Debug:            o.CommonOptions.Verbose,
+		DownloadProxy:    o.CommonOptions.DownloadProxy,
+		DownloadCABundle: o.CommonOptions.DownloadCABundle,
+		IgnoreErr:        o.CommonOptions.IgnoreErr,
 	}
 
 	return pipelines.ArtifactExport(arg, o.DownloadCmd)
@@ -89,6 +109,10 @@ func (o *ArtifactExportOptions) Run() error {
 func (o *ArtifactExportOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ManifestFile, "manifest", "m", "", "Path to a manifest file")
 	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "Path to a output path")
-	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
-		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
+	cmd.Flags().StringVar(&o.Base, "base", "", "Path to a base artifact, only the dependencies not present in it are exported into a delta artifact")
+	cmd.Flags().StringVar(&o.SignKey, "sign-key", "", "Path to a PEM private key or a cosign private key to sign the checksums of the artifact, the password of a cosign key is read from COSIGN_PASSWORD")
+	cmd.Flags().StringVar(&o.SignCert, "sign-cert", "", "Path to the x509 certificate of the sign key, it is archived with the signature")
+	cmd.Flags().StringVar(&o.SignPolicy, "signature-policy", "", "Path to a containers-policy.json which the images are verified with, it overrides the signaturePolicy of the manifest")
+	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "",
+		`The user defined command to download the necessary binary files instead of the built-in downloader, e.g. "curl -L -o %s %s". The first param '%s' is output path, the second param '%s', is the URL`)
 }
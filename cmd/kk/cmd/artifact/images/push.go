@@ -39,6 +39,7 @@ type ArtifactImagesPushOptions struct {
 	ImageDirPath   string
 	Artifact       string
 	ClusterCfgFile string
+	SignPolicy     string
 }
 
 func NewArtifactImagesPushOptions() *ArtifactImagesPushOptions {
@@ -61,6 +62,8 @@ func NewCmdArtifactImagesPush() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddArtifactFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
@@ -89,11 +92,17 @@ func (o *ArtifactImagesPushOptions) Validate(_ []string) error {
 
 func (o *ArtifactImagesPushOptions) Run() error {
 	arg := common.Argument{
-		ImagesDir: o.ImageDirPath,
-		Artifact:  o.Artifact,
-		FilePath:  o.ClusterCfgFile,
-		Debug:     o.CommonOptions.Verbose,
-		IgnoreErr: o.CommonOptions.IgnoreErr,
+		ImagesDir:       o.ImageDirPath,
+		Artifact:        o.Artifact,
+		ArtifactKey:     o.CommonOptions.ArtifactKey,
+		ArtifactCA:      o.CommonOptions.ArtifactCA,
+		SignaturePolicy: o.SignPolicy,
+		FilePath:        o.ClusterCfgFile,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		Output:          o.CommonOptions.Output,
+		ReportFile:      o.CommonOptions.ReportFile,
+		IgnoreErr:       o.CommonOptions.IgnoreErr,
 	}
 	return runPush(arg)
 }
@@ -102,6 +111,7 @@ func (o *ArtifactImagesPushOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ImageDirPath, "images-dir", "", "", "Path to a KubeKey artifact images directory")
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringVar(&o.SignPolicy, "signature-policy", "", "Path to a containers-policy.json which the images are verified with, the policy carried in the artifact is used by default")
 }
 
 func runPush(arg common.Argument) error {
@@ -30,6 +30,7 @@ import (
 type ArtifactImportOptions struct {
 	CommonOptions *options.CommonOptions
 	Artifact      string
+	BaseArtifact  string
 }
 
 func NewArtifactImportOptions() *ArtifactImportOptions {
@@ -51,20 +52,29 @@ func NewCmdArtifactImport() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddArtifactFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
 
 func (o *ArtifactImportOptions) Run() error {
 	arg := common.Argument{
-		Debug:    o.CommonOptions.Verbose,
-		Artifact: o.Artifact,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		Output:          o.CommonOptions.Output,
+		ReportFile:      o.CommonOptions.ReportFile,
+		Artifact:        o.Artifact,
+		ArtifactKey:     o.CommonOptions.ArtifactKey,
+		ArtifactCA:      o.CommonOptions.ArtifactCA,
+		BaseArtifact:    o.BaseArtifact,
 	}
 	return artifact.ArtifactImport(arg)
 }
 
 func (o *ArtifactImportOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a artifact gzip")
+	cmd.Flags().StringVar(&o.BaseArtifact, "base", "", "Path to the base artifact of a delta artifact, the delta is laid onto the unarchived base")
 }
 
 func (o *ArtifactImportOptions) Validate(_ []string) error {
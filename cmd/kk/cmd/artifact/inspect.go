@@ -0,0 +1,160 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/artifact"
+)
+
+type ArtifactInspectOptions struct {
+	Artifact string
+	Diff     string
+	Output   string
+}
+
+func NewArtifactInspectOptions() *ArtifactInspectOptions {
+	return &ArtifactInspectOptions{}
+}
+
+// NewCmdArtifactInspect creates a new artifact inspect command
+func NewCmdArtifactInspect() *cobra.Command {
+	o := NewArtifactInspectOptions()
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "List the contents of a KubeKey offline installation package, or diff them with another one",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Run(cmd))
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *ArtifactInspectOptions) Validate(_ []string) error {
+	if o.Artifact == "" {
+		return errors.New("artifact path can not be empty")
+	}
+	if o.Output != "text" && o.Output != "json" {
+		return errors.Errorf("invalid output format: %s", o.Output)
+	}
+	return nil
+}
+
+func (o *ArtifactInspectOptions) Run(cmd *cobra.Command) error {
+	contents, err := artifact.Inspect(o.Artifact)
+	if err != nil {
+		return err
+	}
+	var result interface{} = contents
+	if o.Diff != "" {
+		other, err := artifact.Inspect(o.Diff)
+		if err != nil {
+			return err
+		}
+		result = contents.Diff(other)
+	}
+
+	w := cmd.OutOrStdout()
+	if o.Output == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if o.Diff == "" {
+		fmt.Fprintf(w, "Artifact: %s\n", o.Artifact)
+		if contents.Delta != nil {
+			fmt.Fprintf(w, "Delta of the base artifact: %s\n", contents.Delta.Base)
+		}
+		fmt.Fprintf(w, "Signed: %t\n", contents.Signed)
+		printContents(w, contents)
+		return nil
+	}
+	diff := result.(*artifact.ContentsDiff)
+	if diff.Added.Empty() && diff.Removed.Empty() {
+		fmt.Fprintf(w, "The contents of %s are the same as %s.\n", o.Artifact, o.Diff)
+		return nil
+	}
+	fmt.Fprintf(w, "Added to %s:\n", o.Artifact)
+	printContents(w, diff.Added)
+	fmt.Fprintf(w, "\nRemoved from %s:\n", o.Diff)
+	printContents(w, diff.Removed)
+	return nil
+}
+
+func (o *ArtifactInspectOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a artifact gzip")
+	cmd.Flags().StringVar(&o.Diff, "diff", "", "Path to another artifact gzip, the contents added and removed compared with it are printed")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "text", "Output format: text or json")
+}
+
+// printContents prints the sections of the contents which are not empty.
+func printContents(w io.Writer, c *artifact.Contents) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(c.Binaries) != 0 {
+		fmt.Fprintln(tw, "\nBinaries:")
+		fmt.Fprintln(tw, "NAME\tVERSION\tARCHES")
+		for _, b := range c.Binaries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", b.Name, b.Version, strings.Join(b.Arches, ","))
+		}
+	}
+	if len(c.OperatingSystems) != 0 {
+		fmt.Fprintln(tw, "\nISO repositories:")
+		fmt.Fprintln(tw, "ID\tVERSION\tARCH\tPATH")
+		for _, sys := range c.OperatingSystems {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", sys.Id, sys.Version, sys.Arch, sys.Repository.Iso.LocalPath)
+		}
+	}
+	tw.Flush()
+
+	if len(c.Images) != 0 {
+		platforms := make([]string, 0, len(c.Images))
+		for platform := range c.Images {
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+		fmt.Fprintln(w, "\nImages:")
+		for _, platform := range platforms {
+			fmt.Fprintf(w, "%s (%d):\n", platform, len(c.Images[platform]))
+			for _, name := range c.Images[platform] {
+				fmt.Fprintf(w, "  %s\n", name)
+			}
+		}
+	}
+	if len(c.Charts) != 0 {
+		fmt.Fprintln(w, "\nCharts:")
+		for _, chart := range c.Charts {
+			fmt.Fprintf(w, "  %s\n", chart)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/artifact"
+)
+
+type ArtifactVerifyOptions struct {
+	Artifact string
+	Key      string
+	CA       string
+}
+
+func NewArtifactVerifyOptions() *ArtifactVerifyOptions {
+	return &ArtifactVerifyOptions{}
+}
+
+// NewCmdArtifactVerify creates a new artifact verify command
+func NewCmdArtifactVerify() *cobra.Command {
+	o := NewArtifactVerifyOptions()
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the checksums and the signature of a KubeKey offline installation package",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Validate(args))
+			util.CheckErr(o.Run(cmd))
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *ArtifactVerifyOptions) Validate(_ []string) error {
+	if o.Artifact == "" {
+		return errors.New("artifact path can not be empty")
+	}
+	return nil
+}
+
+func (o *ArtifactVerifyOptions) Run(cmd *cobra.Command) error {
+	if err := artifact.VerifyArtifact(o.Artifact, artifact.VerifyOptions{Key: o.Key, CA: o.CA}); err != nil {
+		return err
+	}
+	if o.Key == "" && o.CA == "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "The checksums of the artifact %s are verified, the signature is only verified with --key or --ca.\n", o.Artifact)
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "The checksums and the signature of the artifact %s are verified.\n", o.Artifact)
+	return nil
+}
+
+func (o *ArtifactVerifyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a artifact gzip")
+	cmd.Flags().StringVar(&o.Key, "key", "", "Path to the public key or the certificate which the signature is verified with")
+	cmd.Flags().StringVar(&o.CA, "ca", "", "Path to the CA certificates which the signing certificate in the artifact is verified with")
+}
@@ -0,0 +1,69 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backup
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/options"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/pipelines"
+)
+
+type BackupETCDOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+}
+
+func NewBackupETCDOptions() *BackupETCDOptions {
+	return &BackupETCDOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdBackupETCD creates a new backup etcd command
+func NewCmdBackupETCD() *cobra.Command {
+	o := NewBackupETCDOptions()
+	cmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Take a snapshot of the etcd cluster and upload it to the backup target",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *BackupETCDOptions) Run() error {
+	arg := common.Argument{
+		FilePath:        o.ClusterCfgFile,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		Output:          o.CommonOptions.Output,
+		ReportFile:      o.CommonOptions.ReportFile,
+	}
+	return pipelines.BackupETCD(arg)
+}
+
+func (o *BackupETCDOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+}
@@ -48,14 +48,18 @@ func NewCmdCertList() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
 
 func (o *CertListOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
+		FilePath:        o.ClusterCfgFile,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		Output:          o.CommonOptions.Output,
+		ReportFile:      o.CommonOptions.ReportFile,
 	}
 	return pipelines.CheckCerts(arg)
 }
@@ -28,6 +28,10 @@ import (
 type CertRenewOptions struct {
 	CommonOptions  *options.CommonOptions
 	ClusterCfgFile string
+	FromCluster    bool
+	KubeConfig     string
+	DryRun         bool
+	ETCD           bool
 }
 
 func NewCertRenewOptions() *CertRenewOptions {
@@ -48,18 +52,30 @@ func NewCmdCertRenew() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
 
 func (o *CertRenewOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
+		FilePath:        o.ClusterCfgFile,
+		FromCluster:     o.FromCluster,
+		KubeConfig:      o.KubeConfig,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		Output:          o.CommonOptions.Output,
+		ReportFile:      o.CommonOptions.ReportFile,
+		DryRun:          o.DryRun,
+		RenewETCDCerts:  o.ETCD,
 	}
 	return pipelines.RenewCerts(arg)
 }
 
 func (o *CertRenewOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().BoolVarP(&o.FromCluster, "from-cluster", "", false, "Load the cluster configuration saved in the cluster, the configuration file specified by -f is merged into it as the delta")
+	cmd.Flags().StringVarP(&o.KubeConfig, "kubeconfig", "", "", "Specify a kubeconfig file to access the cluster with --from-cluster")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the operations that would be executed on each host instead of executing them, the pre-checks still run on the hosts")
+	cmd.Flags().BoolVarP(&o.ETCD, "etcd", "", false, "Renew the etcd certs too, and restart the etcd members one by one")
 }
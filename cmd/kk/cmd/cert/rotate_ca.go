@@ -0,0 +1,71 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cert
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/options"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/pipelines"
+)
+
+type CertRotateCAOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	Stage          string
+}
+
+func NewCertRotateCAOptions() *CertRotateCAOptions {
+	return &CertRotateCAOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdCertRotateCA creates a new cert rotate-ca command
+func NewCmdCertRotateCA() *cobra.Command {
+	o := NewCertRotateCAOptions()
+	cmd := &cobra.Command{
+		Use:   "rotate-ca",
+		Short: "rotate the CA of a cluster and etcd in stages",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *CertRotateCAOptions) Run() error {
+	arg := common.Argument{
+		FilePath:        o.ClusterCfgFile,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		RotateCAStage:   o.Stage,
+	}
+	return pipelines.RotateCA(arg)
+}
+
+func (o *CertRotateCAOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringVarP(&o.Stage, "stage", "", certs.RotateCAStageAll,
+		"The stage of the rotation: trust, reissue, drop or all. The new CA is trusted beside the old one in the trust stage, the certs are signed by the new CA in the reissue stage, and the old CA is removed in the drop stage")
+}
@@ -45,8 +45,11 @@ type CreateClusterOptions struct {
 	DownloadCmd         string
 	Artifact            string
 	InstallPackages     bool
+	Resume              bool
 
 	localStorageChanged bool
+	DryRun              bool
+	NoRollback          bool
 }
 
 func NewCreateClusterOptions() *CreateClusterOptions {
@@ -69,6 +72,9 @@ func NewCmdCreateCluster() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddArtifactFlag(cmd)
+	o.CommonOptions.AddDownloadFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 
 	if err := completionSetting(cmd); err != nil {
@@ -116,12 +122,22 @@ func (o *CreateClusterOptions) Run() error {
 		SkipPushImages:      o.SkipPushImages,
 		SecurityEnhancement: o.SecurityEnhancement,
 		Debug:               o.CommonOptions.Verbose,
+		DownloadProxy:       o.CommonOptions.DownloadProxy,
+		DownloadCABundle:    o.CommonOptions.DownloadCABundle,
+		HostKeyChecking:     o.CommonOptions.HostKeyChecking,
+		Output:              o.CommonOptions.Output,
+		ReportFile:          o.CommonOptions.ReportFile,
+		DryRun:              o.DryRun,
+		NoRollback:          o.NoRollback,
 		IgnoreErr:           o.CommonOptions.IgnoreErr,
 		SkipConfirmCheck:    o.CommonOptions.SkipConfirmCheck,
 		ContainerManager:    o.ContainerManager,
 		Artifact:            o.Artifact,
+		ArtifactKey:         o.CommonOptions.ArtifactKey,
+		ArtifactCA:          o.CommonOptions.ArtifactCA,
 		InstallPackages:     o.InstallPackages,
 		Namespace:           o.CommonOptions.Namespace,
+		Resume:              o.Resume,
 	}
 
 	if o.localStorageChanged {
@@ -141,10 +157,13 @@ func (o *CreateClusterOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&o.SkipPushImages, "skip-push-images", "", false, "Skip pre push images")
 	cmd.Flags().BoolVarP(&o.SecurityEnhancement, "with-security-enhancement", "", false, "Security enhancement")
 	cmd.Flags().StringVarP(&o.ContainerManager, "container-manager", "", "docker", "Container runtime: docker, crio, containerd and isula.")
-	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
-		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
+	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "",
+		`The user defined command to download the necessary binary files instead of the built-in downloader, e.g. "curl -L -o %s %s". The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
 	cmd.Flags().BoolVarP(&o.InstallPackages, "with-packages", "", false, "install operation system packages by artifact")
+	cmd.Flags().BoolVarP(&o.Resume, "resume", "", false, "Resume from the checkpoint of the last execution, the modules that have been completed with the same configuration are skipped")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the operations that would be executed on each host instead of executing them, the pre-checks still run on the hosts")
+	cmd.Flags().BoolVarP(&o.NoRollback, "no-rollback", "", false, "Do not roll back the failed tasks, the failed hosts are left as they are for debugging")
 }
 
 func completionSetting(cmd *cobra.Command) (err error) {
@@ -35,9 +35,10 @@ import (
 type CreateManifestOptions struct {
 	CommonOptions *options.CommonOptions
 
-	Name       string
-	KubeConfig string
-	FileName   string
+	Name           string
+	KubeConfig     string
+	FileName       string
+	ClusterCfgFile string
 }
 
 func NewCreateManifestOptions() *CreateManifestOptions {
@@ -82,6 +83,14 @@ func (o *CreateManifestOptions) Complete(cmd *cobra.Command, args []string) erro
 }
 
 func (o *CreateManifestOptions) Run() error {
+	if o.ClusterCfgFile != "" {
+		arg := common.Argument{
+			FilePath: o.ClusterCfgFile,
+			Debug:    o.CommonOptions.Verbose,
+		}
+		return artifact.CreateManifestFromConfig(arg, o.Name, o.FileName)
+	}
+
 	arg := common.Argument{
 		FilePath:   o.FileName,
 		KubeConfig: o.KubeConfig,
@@ -92,5 +101,6 @@ func (o *CreateManifestOptions) Run() error {
 func (o *CreateManifestOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Name, "name", "", "sample", "Specify a name of manifest object")
 	cmd.Flags().StringVarP(&o.FileName, "filename", "f", "", "Specify a manifest file path")
+	cmd.Flags().StringVar(&o.ClusterCfgFile, "config", "", "Path to a cluster configuration file, the manifest is computed from it instead of the cluster of the kubeconfig")
 	cmd.Flags().StringVar(&o.KubeConfig, "kubeconfig", "", "Specify a kubeconfig file")
 }
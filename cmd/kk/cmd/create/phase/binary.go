@@ -53,6 +53,8 @@ func NewCmdCreateBinary() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddDownloadFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	if err := k8sCompletionSetting(cmd); err != nil {
 		panic(fmt.Sprintf("Got error with the completion setting"))
@@ -65,6 +67,11 @@ func (o *CreateBinaryOptions) Run() error {
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
 		Debug:             o.CommonOptions.Verbose,
+		DownloadProxy:     o.CommonOptions.DownloadProxy,
+		DownloadCABundle:  o.CommonOptions.DownloadCABundle,
+		HostKeyChecking:   o.CommonOptions.HostKeyChecking,
+		Output:            o.CommonOptions.Output,
+		ReportFile:        o.CommonOptions.ReportFile,
 	}
 	return binary.CreateBinary(arg, o.DownloadCmd)
 }
@@ -72,8 +79,8 @@ func (o *CreateBinaryOptions) Run() error {
 func (o *CreateBinaryOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().StringVarP(&o.Kubernetes, "with-kubernetes", "", "", "Specify a supported version of kubernetes")
-	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
-		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
+	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "",
+		`The user defined command to download the necessary binary files instead of the built-in downloader, e.g. "curl -L -o %s %s". The first param '%s' is output path, the second param '%s', is the URL`)
 
 }
 
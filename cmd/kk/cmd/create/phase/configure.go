@@ -61,6 +61,7 @@ func NewCmdCreateConfigureKubernetes() *cobra.Command {
 		},
 	}
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 
 	if err := k8sCompletionSetting(cmd); err != nil {
@@ -74,6 +75,9 @@ func (o *CreateConfigureKubernetesOptions) Run() error {
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
 		Debug:             o.CommonOptions.Verbose,
+		HostKeyChecking:   o.CommonOptions.HostKeyChecking,
+		Output:            o.CommonOptions.Output,
+		ReportFile:        o.CommonOptions.ReportFile,
 		Namespace:         o.CommonOptions.Namespace,
 	}
 
@@ -52,6 +52,7 @@ func NewCmdCreateImages() *cobra.Command {
 		},
 	}
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 
 	if err := k8sCompletionSetting(cmd); err != nil {
@@ -75,6 +76,9 @@ func (o *CreateImagesOptions) Run() error {
 		KubernetesVersion: o.Kubernetes,
 		ContainerManager:  o.ContainerManager,
 		Debug:             o.CommonOptions.Verbose,
+		HostKeyChecking:   o.CommonOptions.HostKeyChecking,
+		Output:            o.CommonOptions.Output,
+		ReportFile:        o.CommonOptions.ReportFile,
 	}
 	return images.CreateImages(arg)
 }
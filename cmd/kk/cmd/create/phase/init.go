@@ -50,6 +50,7 @@ func NewCmdCreateInitCluster() *cobra.Command {
 		},
 	}
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 
 	if err := k8sCompletionSetting(cmd); err != nil {
@@ -63,6 +64,9 @@ func (o *CreateInitClusterOptions) Run() error {
 		FilePath:          o.ClusterCfgFile,
 		KubernetesVersion: o.Kubernetes,
 		Debug:             o.CommonOptions.Verbose,
+		HostKeyChecking:   o.CommonOptions.HostKeyChecking,
+		Output:            o.CommonOptions.Output,
+		ReportFile:        o.CommonOptions.ReportFile,
 		Namespace:         o.CommonOptions.Namespace,
 	}
 
@@ -49,6 +49,7 @@ func NewCmdConfigOS() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
@@ -57,6 +58,9 @@ func (o *ConfigOSOptions) Run() error {
 	arg := common.Argument{
 		FilePath:        o.ClusterCfgFile,
 		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		Output:          o.CommonOptions.Output,
+		ReportFile:      o.CommonOptions.ReportFile,
 		InstallPackages: o.InstallPackages,
 	}
 	return os.ConfigOS(arg)
@@ -30,6 +30,7 @@ type DeleteClusterOptions struct {
 	ClusterCfgFile string
 	Kubernetes     string
 	DeleteCRI      bool
+	DryRun         bool
 }
 
 func NewDeleteClusterOptions() *DeleteClusterOptions {
@@ -50,6 +51,7 @@ func NewCmdDeleteCluster() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
@@ -58,6 +60,10 @@ func (o *DeleteClusterOptions) Run() error {
 	arg := common.Argument{
 		FilePath:          o.ClusterCfgFile,
 		Debug:             o.CommonOptions.Verbose,
+		HostKeyChecking:   o.CommonOptions.HostKeyChecking,
+		Output:            o.CommonOptions.Output,
+		ReportFile:        o.CommonOptions.ReportFile,
+		DryRun:            o.DryRun,
 		KubernetesVersion: o.Kubernetes,
 		DeleteCRI:         o.DeleteCRI,
 		SkipConfirmCheck:  o.CommonOptions.SkipConfirmCheck,
@@ -69,4 +75,5 @@ func (o *DeleteClusterOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
 	cmd.Flags().StringVarP(&o.Kubernetes, "with-kubernetes", "", "", "Specify a supported version of kubernetes")
 	cmd.Flags().BoolVarP(&o.DeleteCRI, "all", "A", false, "Delete total cri conficutation and data directories")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the operations that would be executed on each host instead of executing them, the pre-checks still run on the hosts")
 }
@@ -32,6 +32,7 @@ type DeleteNodeOptions struct {
 	CommonOptions  *options.CommonOptions
 	ClusterCfgFile string
 	nodeName       string
+	DryRun         bool
 }
 
 func NewDeleteNodeOptions() *DeleteNodeOptions {
@@ -54,6 +55,7 @@ func NewCmdDeleteNode() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
@@ -74,6 +76,10 @@ func (o *DeleteNodeOptions) Run() error {
 	arg := common.Argument{
 		FilePath:         o.ClusterCfgFile,
 		Debug:            o.CommonOptions.Verbose,
+		HostKeyChecking:  o.CommonOptions.HostKeyChecking,
+		Output:           o.CommonOptions.Output,
+		ReportFile:       o.CommonOptions.ReportFile,
+		DryRun:           o.DryRun,
 		NodeName:         o.nodeName,
 		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
 	}
@@ -82,5 +88,6 @@ func (o *DeleteNodeOptions) Run() error {
 
 func (o *DeleteNodeOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the operations that would be executed on each host instead of executing them, the pre-checks still run on the hosts")
 
 }
@@ -49,15 +49,22 @@ func NewCmdInitOs() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddArtifactFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
 
 func (o *InitOsOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
-		Artifact: o.Artifact,
+		FilePath:        o.ClusterCfgFile,
+		Debug:           o.CommonOptions.Verbose,
+		HostKeyChecking: o.CommonOptions.HostKeyChecking,
+		Output:          o.CommonOptions.Output,
+		ReportFile:      o.CommonOptions.ReportFile,
+		Artifact:        o.Artifact,
+		ArtifactKey:     o.CommonOptions.ArtifactKey,
+		ArtifactCA:      o.CommonOptions.ArtifactCA,
 	}
 	return pipelines.InitDependencies(arg)
 }
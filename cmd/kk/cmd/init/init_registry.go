@@ -51,6 +51,9 @@ func NewCmdInitRegistry() *cobra.Command {
 	}
 
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddArtifactFlag(cmd)
+	o.CommonOptions.AddDownloadFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 	return cmd
 }
@@ -61,16 +64,23 @@ func (o *InitRegistryOptions) Complete(_ *cobra.Command, _ []string) error {
 
 func (o *InitRegistryOptions) Run() error {
 	arg := common.Argument{
-		FilePath: o.ClusterCfgFile,
-		Debug:    o.CommonOptions.Verbose,
-		Artifact: o.Artifact,
+		FilePath:         o.ClusterCfgFile,
+		Debug:            o.CommonOptions.Verbose,
+		DownloadProxy:    o.CommonOptions.DownloadProxy,
+		DownloadCABundle: o.CommonOptions.DownloadCABundle,
+		HostKeyChecking:  o.CommonOptions.HostKeyChecking,
+		Output:           o.CommonOptions.Output,
+		ReportFile:       o.CommonOptions.ReportFile,
+		Artifact:         o.Artifact,
+		ArtifactKey:      o.CommonOptions.ArtifactKey,
+		ArtifactCA:       o.CommonOptions.ArtifactCA,
 	}
 	return pipelines.InitRegistry(arg, o.DownloadCmd)
 }
 
 func (o *InitRegistryOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
-	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
-		`The user defined command to download the necessary files. The first param '%s' is output path, the second param '%s', is the URL`)
+	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "",
+		`The user defined command to download the necessary binary files instead of the built-in downloader, e.g. "curl -L -o %s %s". The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
 }
@@ -25,6 +25,13 @@ type CommonOptions struct {
 	SkipConfirmCheck bool
 	IgnoreErr        bool
 	Namespace        string
+	HostKeyChecking  string
+	Output           string
+	ReportFile       string
+	DownloadProxy    string
+	DownloadCABundle string
+	ArtifactKey      string
+	ArtifactCA       string
 }
 
 func NewCommonOptions() *CommonOptions {
@@ -36,4 +43,23 @@ func (o *CommonOptions) AddCommonFlag(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&o.SkipConfirmCheck, "yes", "y", false, "Skip confirm check")
 	cmd.Flags().BoolVar(&o.IgnoreErr, "ignore-err", false, "Ignore the error message, remove the host which reported error and force to continue")
 	cmd.Flags().StringVar(&o.Namespace, "namespace", "kubekey-system", "KubeKey namespace to use")
+	cmd.Flags().StringVar(&o.HostKeyChecking, "host-key-checking", "tofu", "SSH host key checking mode: insecure, tofu (record unknown host keys on first contact) or strict")
+}
+
+// AddOutputFlag adds the flags of the pipeline output, it is only used by the commands which execute a cluster pipeline.
+func (o *CommonOptions) AddOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Output, "output", "text", "Output format: text or json (a JSON line for each pipeline, module and task result event)")
+	cmd.Flags().StringVar(&o.ReportFile, "report", "", "Path to write a JSON summary report of the pipeline at the end of the run")
+}
+
+// AddDownloadFlag adds the flags of the built-in downloader, it is only used by the commands which download the binaries.
+func (o *CommonOptions) AddDownloadFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.DownloadProxy, "download-proxy", "", "The proxy URL used to download the binary files, the environment HTTPS_PROXY and NO_PROXY are used by default")
+	cmd.Flags().StringVar(&o.DownloadCABundle, "download-ca-bundle", "", "Path to a PEM file of the extra CA certificates trusted when downloading the binary files")
+}
+
+// AddArtifactFlag adds the flags of the artifact verification, it is only used by the commands which unarchive an artifact.
+func (o *CommonOptions) AddArtifactFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.ArtifactKey, "artifact-key", "", "Path to the public key or the certificate which the signature of the artifact is verified with")
+	cmd.Flags().StringVar(&o.ArtifactCA, "artifact-ca", "", "Path to the CA certificates which the signing certificate in the artifact is verified with")
 }
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The KubeSphere Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restore
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/options"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	coreutil "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/etcd"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/pipelines"
+)
+
+type RestoreETCDOptions struct {
+	CommonOptions  *options.CommonOptions
+	ClusterCfgFile string
+	Snapshot       string
+}
+
+func NewRestoreETCDOptions() *RestoreETCDOptions {
+	return &RestoreETCDOptions{
+		CommonOptions: options.NewCommonOptions(),
+	}
+}
+
+// NewCmdRestoreETCD creates a new restore etcd command
+func NewCmdRestoreETCD() *cobra.Command {
+	o := NewRestoreETCDOptions()
+	cmd := &cobra.Command{
+		Use:   "etcd",
+		Short: "Restore the etcd cluster from a snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run())
+		},
+	}
+
+	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *RestoreETCDOptions) Validate() error {
+	switch o.Snapshot {
+	case "":
+		return errors.New("the snapshot to restore is required, it should be a snapshot file or latest")
+	case etcd.LatestSnapshot:
+		return nil
+	}
+
+	path, err := filepath.Abs(o.Snapshot)
+	if err != nil {
+		return errors.Wrapf(err, "failed to look up the snapshot %s", o.Snapshot)
+	}
+	if !coreutil.IsExist(path) {
+		return errors.Errorf("the snapshot %s does not exist", path)
+	}
+	o.Snapshot = path
+	return nil
+}
+
+func (o *RestoreETCDOptions) Run() error {
+	arg := common.Argument{
+		FilePath:         o.ClusterCfgFile,
+		Debug:            o.CommonOptions.Verbose,
+		HostKeyChecking:  o.CommonOptions.HostKeyChecking,
+		Output:           o.CommonOptions.Output,
+		ReportFile:       o.CommonOptions.ReportFile,
+		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
+		Snapshot:         o.Snapshot,
+	}
+	return pipelines.RestoreETCD(arg)
+}
+
+func (o *RestoreETCDOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().StringVarP(&o.Snapshot, "snapshot", "", "",
+		"Path to the etcd snapshot file to restore, or 'latest' to restore the latest snapshot backed up on the first etcd node")
+}
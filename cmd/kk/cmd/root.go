@@ -27,8 +27,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/add"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/addons"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/alpha"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/artifact"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/backup"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/cert"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/completion"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/create"
@@ -36,7 +38,10 @@ import (
 	initOs "github.com/kubesphere/kubekey/v3/cmd/kk/cmd/init"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/options"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/plugin"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/restore"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/status"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/upgrade"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/validate"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/version"
 )
 
@@ -114,7 +119,12 @@ func NewKubeKeyCommand(o KubeKeyOptions) *cobra.Command {
 	cmds.AddCommand(add.NewCmdAdd())
 	cmds.AddCommand(upgrade.NewCmdUpgrade())
 	cmds.AddCommand(cert.NewCmdCerts())
+	cmds.AddCommand(backup.NewCmdBackup())
+	cmds.AddCommand(restore.NewCmdRestore())
+	cmds.AddCommand(addons.NewCmdAddons())
 	cmds.AddCommand(artifact.NewCmdArtifact())
+	cmds.AddCommand(status.NewCmdStatus())
+	cmds.AddCommand(validate.NewCmdValidate())
 
 	cmds.AddCommand(plugin.NewCmdPlugin(o.IOStreams))
 
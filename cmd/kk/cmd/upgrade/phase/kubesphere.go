@@ -54,6 +54,7 @@ func NewCmdUpgradeKubeSphere() *cobra.Command {
 		},
 	}
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 
 	if err := ksCompletionSetting(cmd); err != nil {
@@ -80,6 +81,9 @@ func (o *UpgradeKubeSphereOptions) Run() error {
 		KsVersion:        o.KubeSphere,
 		SkipConfirmCheck: o.CommonOptions.SkipConfirmCheck,
 		Debug:            o.CommonOptions.Verbose,
+		HostKeyChecking:  o.CommonOptions.HostKeyChecking,
+		Output:           o.CommonOptions.Output,
+		ReportFile:       o.CommonOptions.ReportFile,
 	}
 	return alpha.UpgradeKubeSphere(arg)
 }
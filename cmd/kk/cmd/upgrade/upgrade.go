@@ -33,12 +33,16 @@ import (
 type UpgradeOptions struct {
 	CommonOptions    *options.CommonOptions
 	ClusterCfgFile   string
+	FromCluster      bool
+	KubeConfig       string
 	Kubernetes       string
 	EnableKubeSphere bool
 	KubeSphere       string
 	SkipPullImages   bool
 	DownloadCmd      string
 	Artifact         string
+	Resume           bool
+	DryRun           bool
 }
 
 func NewUpgradeOptions() *UpgradeOptions {
@@ -59,6 +63,9 @@ func NewCmdUpgrade() *cobra.Command {
 		},
 	}
 	o.CommonOptions.AddCommonFlag(cmd)
+	o.CommonOptions.AddArtifactFlag(cmd)
+	o.CommonOptions.AddDownloadFlag(cmd)
+	o.CommonOptions.AddOutputFlag(cmd)
 	o.AddFlags(cmd)
 
 	if err := completionSetting(cmd); err != nil {
@@ -81,25 +88,40 @@ func (o *UpgradeOptions) Complete(cmd *cobra.Command, args []string) error {
 func (o *UpgradeOptions) Run() error {
 	arg := common.Argument{
 		FilePath:          o.ClusterCfgFile,
+		FromCluster:       o.FromCluster,
+		KubeConfig:        o.KubeConfig,
 		KubernetesVersion: o.Kubernetes,
 		KsEnable:          o.EnableKubeSphere,
 		KsVersion:         o.KubeSphere,
 		SkipPullImages:    o.SkipPullImages,
 		Debug:             o.CommonOptions.Verbose,
+		DownloadProxy:     o.CommonOptions.DownloadProxy,
+		DownloadCABundle:  o.CommonOptions.DownloadCABundle,
+		HostKeyChecking:   o.CommonOptions.HostKeyChecking,
+		Output:            o.CommonOptions.Output,
+		ReportFile:        o.CommonOptions.ReportFile,
+		DryRun:            o.DryRun,
 		SkipConfirmCheck:  o.CommonOptions.SkipConfirmCheck,
 		Artifact:          o.Artifact,
+		ArtifactKey:       o.CommonOptions.ArtifactKey,
+		ArtifactCA:        o.CommonOptions.ArtifactCA,
+		Resume:            o.Resume,
 	}
 	return pipelines.UpgradeCluster(arg, o.DownloadCmd)
 }
 
 func (o *UpgradeOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+	cmd.Flags().BoolVarP(&o.FromCluster, "from-cluster", "", false, "Load the cluster configuration saved in the cluster, the configuration file specified by -f is merged into it as the delta")
+	cmd.Flags().StringVarP(&o.KubeConfig, "kubeconfig", "", "", "Specify a kubeconfig file to access the cluster with --from-cluster")
 	cmd.Flags().StringVarP(&o.Kubernetes, "with-kubernetes", "", "", "Specify a supported version of kubernetes")
 	cmd.Flags().BoolVarP(&o.EnableKubeSphere, "with-kubesphere", "", false, fmt.Sprintf("Deploy a specific version of kubesphere (default %s)", kubesphere.Latest().Version))
 	cmd.Flags().BoolVarP(&o.SkipPullImages, "skip-pull-images", "", false, "Skip pre pull images")
-	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "curl -L -o %s %s",
-		`The user defined command to download the necessary binary files. The first param '%s' is output path, the second param '%s', is the URL`)
+	cmd.Flags().StringVarP(&o.DownloadCmd, "download-cmd", "", "",
+		`The user defined command to download the necessary binary files instead of the built-in downloader, e.g. "curl -L -o %s %s". The first param '%s' is output path, the second param '%s', is the URL`)
 	cmd.Flags().StringVarP(&o.Artifact, "artifact", "a", "", "Path to a KubeKey artifact")
+	cmd.Flags().BoolVarP(&o.Resume, "resume", "", false, "Resume from the checkpoint of the last execution, the modules that have been completed with the same configuration are skipped")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "Print the operations that would be executed on each host instead of executing them, the pre-checks still run on the hosts")
 }
 
 func completionSetting(cmd *cobra.Command) (err error) {
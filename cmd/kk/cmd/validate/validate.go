@@ -0,0 +1,78 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/cmd/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/validation"
+)
+
+type ValidateOptions struct {
+	ClusterCfgFile string
+}
+
+func NewValidateOptions() *ValidateOptions {
+	return &ValidateOptions{}
+}
+
+// NewCmdValidate creates a new validate command
+func NewCmdValidate() *cobra.Command {
+	o := NewValidateOptions()
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the cluster configuration file",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Validate())
+			util.CheckErr(o.Run(cmd))
+		},
+	}
+
+	o.AddFlags(cmd)
+	return cmd
+}
+
+func (o *ValidateOptions) Validate() error {
+	if o.ClusterCfgFile == "" {
+		return errors.New("the configuration file is required, it should be specified by --filename")
+	}
+	return nil
+}
+
+func (o *ValidateOptions) Run(cmd *cobra.Command) error {
+	cluster, err := common.NewLoader(common.File, common.Argument{FilePath: o.ClusterCfgFile}).Load()
+	if err != nil {
+		return err
+	}
+	if err := validation.ToError(append(validation.ValidateCluster(&cluster.Spec), validation.ValidateCredentials(&cluster.Spec)...)); err != nil {
+		return err
+	}
+	for _, warning := range validation.ClusterWarnings(&cluster.Spec) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", warning)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "The cluster configuration %s is valid.\n", o.ClusterCfgFile)
+	return nil
+}
+
+func (o *ValidateOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.ClusterCfgFile, "filename", "f", "", "Path to a configuration file")
+}
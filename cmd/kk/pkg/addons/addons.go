@@ -17,6 +17,7 @@
 package addons
 
 import (
+	"bytes"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -30,38 +31,97 @@ import (
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 )
 
-func InstallAddons(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, kubeConfig string) error {
+func InstallAddons(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, kubeConfig, chartsDir string) error {
 	// install chart
 	if addon.Sources.Chart.Name != "" {
 		_ = os.Setenv("HELM_NAMESPACE", strings.TrimSpace(addon.Namespace))
-		if err := InstallChart(kubeConf, addon, kubeConfig); err != nil {
+		if err := InstallChart(kubeConf, addon, kubeConfig, chartsDir); err != nil {
 			return err
 		}
 	}
 
 	// install yaml
 	if len(addon.Sources.Yaml.Path) != 0 {
-		var settings = cli.New()
-		p := getter.All(settings)
-		for _, yaml := range addon.Sources.Yaml.Path {
-			u, _ := url.Parse(yaml)
-			_, err := p.ByScheme(u.Scheme)
+		paths, err := yamlPaths(addon)
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			if err := InstallYaml([]string{p}, addon.Namespace, kubeConfig, kubeConf.Cluster.Kubernetes.Version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// yamlPaths returns the yaml sources of the addon, the local paths are converted to absolute paths.
+func yamlPaths(addon *kubekeyapiv1alpha2.Addon) ([]string, error) {
+	var settings = cli.New()
+	p := getter.All(settings)
+	paths := make([]string, 0, len(addon.Sources.Yaml.Path))
+	for _, yaml := range addon.Sources.Yaml.Path {
+		u, _ := url.Parse(yaml)
+		if _, err := p.ByScheme(u.Scheme); err == nil {
+			paths = append(paths, yaml)
+			continue
+		}
+		fp, err := filepath.Abs(yaml)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to look up current directory")
+		}
+		paths = append(paths, fp)
+	}
+	return paths, nil
+}
+
+// readManifests reads the content of the yaml sources, the files in a directory are read in the order of names.
+func readManifests(paths []string) ([]byte, error) {
+	var settings = cli.New()
+	p := getter.All(settings)
+	var buf bytes.Buffer
+	add := func(content []byte) {
+		buf.WriteString("\n---\n")
+		buf.Write(content)
+	}
+	for _, path := range paths {
+		u, _ := url.Parse(path)
+		if g, err := p.ByScheme(u.Scheme); err == nil {
+			content, err := g.Get(path)
 			if err != nil {
-				fp, err := filepath.Abs(yaml)
-				if err != nil {
-					return errors.Wrap(err, "Failed to look up current directory")
-				}
-				yamlPaths := []string{fp}
-				if err := InstallYaml(yamlPaths, addon.Namespace, kubeConfig, kubeConf.Cluster.Kubernetes.Version); err != nil {
-					return err
-				}
-			} else {
-				yamlPaths := []string{yaml}
-				if err := InstallYaml(yamlPaths, addon.Namespace, kubeConfig, kubeConf.Cluster.Kubernetes.Version); err != nil {
-					return err
+				return nil, errors.Wrapf(err, "download %s failed", path)
+			}
+			add(content.Bytes())
+			continue
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "stat %s failed", path)
+		}
+		files := []string{path}
+		if fi.IsDir() {
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "read dir %s failed", path)
+			}
+			files = files[:0]
+			for _, e := range entries {
+				switch filepath.Ext(e.Name()) {
+				case ".yaml", ".yml", ".json":
+					if !e.IsDir() {
+						files = append(files, filepath.Join(path, e.Name()))
+					}
 				}
 			}
 		}
+		for _, f := range files {
+			content, err := os.ReadFile(f)
+			if err != nil {
+				return nil, errors.Wrapf(err, "read %s failed", f)
+			}
+			add(content)
+		}
 	}
-	return nil
+	return buf.Bytes(), nil
 }
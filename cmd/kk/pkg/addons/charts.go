@@ -47,22 +47,34 @@ func debug(format string, v ...interface{}) {
 	}
 }
 
-func InstallChart(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, kubeConfig string) error {
-	actionConfig := new(action.Configuration)
-	var settings = cli.New()
-	helmDriver := os.Getenv("HELM_DRIVER")
-	settings.KubeConfig = kubeConfig
-	var namespace string
+// addonNamespace returns the namespace of the addon, it is "default" if not set.
+func addonNamespace(addon *kubekeyapiv1alpha2.Addon) string {
 	if addon.Namespace != "" {
-		namespace = addon.Namespace
-	} else {
-		namespace = "default"
+		return addon.Namespace
 	}
+	return "default"
+}
 
-	if err := actionConfig.Init(settings.RESTClientGetter(), namespace, helmDriver, debug); err != nil {
-		logger.Log.Fatal(err)
+// addonTimeout returns the timeout to wait for the resources of the addon to be ready.
+func addonTimeout(addon *kubekeyapiv1alpha2.Addon) time.Duration {
+	if addon.Timeout > 0 {
+		return time.Duration(addon.Timeout) * time.Second
 	}
+	return 300 * time.Second
+}
+
+func newActionConfig(kubeConfig, namespace string) (*action.Configuration, *cli.EnvSettings, error) {
+	actionConfig := new(action.Configuration)
+	var settings = cli.New()
+	settings.KubeConfig = kubeConfig
+	settings.SetNamespace(namespace)
+	if err := actionConfig.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+		return nil, nil, errors.Wrap(err, "init the helm configuration failed")
+	}
+	return actionConfig, settings, nil
+}
 
+func chartValues(addon *kubekeyapiv1alpha2.Addon) *values.Options {
 	valueOpts := &values.Options{}
 	if len(addon.Sources.Chart.Values) != 0 {
 		valueOpts.Values = addon.Sources.Chart.Values
@@ -70,28 +82,43 @@ func InstallChart(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, ku
 	if len(addon.Sources.Chart.ValuesFile) != 0 {
 		valueOpts.ValueFiles = []string{addon.Sources.Chart.ValuesFile}
 	}
+	return valueOpts
+}
+
+// InstallChart installs or upgrades the chart addon. The chart archive in chartsDir is used if it is found, so that
+// the charts pulled into the artifact can be installed offline.
+func InstallChart(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, kubeConfig, chartsDir string) error {
+	namespace := addonNamespace(addon)
+	actionConfig, settings, err := newActionConfig(kubeConfig, namespace)
+	if err != nil {
+		return err
+	}
+	if actionConfig.RegistryClient, err = newRegistryClient(kubeConf.Cluster.Registry.Auths); err != nil {
+		return err
+	}
+
+	valueOpts := chartValues(addon)
 
 	client := action.NewUpgrade(actionConfig)
 
-	var chartName string
-	if addon.Sources.Chart.Name != "" {
-		if addon.Sources.Chart.Repo == "" && addon.Sources.Chart.Path != "" {
-			fmt.Println(addon.Sources.Chart.Repo)
-			chartName = filepath.Join(addon.Sources.Chart.Path, addon.Sources.Chart.Name)
-		} else {
-			chartName = addon.Sources.Chart.Name
-		}
-	} else {
-		logger.Log.Fatalln("No chart name is specified")
+	if addon.Sources.Chart.Name == "" {
+		return errors.Errorf("no chart name is specified for the addon %s", addon.Name)
+	}
+	chartName, repoURL := chartRef(&addon.Sources.Chart)
+	if local := localChart(chartsDir, &addon.Sources.Chart); local != "" {
+		logger.Log.Infof("Use the chart %s in the artifact", local)
+		chartName, repoURL = local, ""
 	}
 
 	args := []string{addon.Name, chartName}
 
 	client.Install = true
 	client.Namespace = namespace
-	client.Timeout = 300 * time.Second
+	client.Timeout = addonTimeout(addon)
+	// the resources are waited to be ready, so that the addons depending on it can be installed
+	client.Wait = true
 	client.Keyring = defaultKeyring()
-	client.RepoURL = addon.Sources.Chart.Repo
+	client.RepoURL = repoURL
 	client.Version = addon.Sources.Chart.Version
 	//client.Force = true
 
@@ -108,6 +135,7 @@ func InstallChart(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, ku
 			instClient.CreateNamespace = true
 			instClient.Namespace = client.Namespace
 			instClient.Timeout = client.Timeout
+			instClient.Wait = client.Wait
 			instClient.Keyring = client.Keyring
 			instClient.RepoURL = client.RepoURL
 			instClient.Version = client.Version
@@ -44,3 +44,47 @@ func (a *AddonsModule) Init() {
 		install,
 	}
 }
+
+type SyncModule struct {
+	common.KubeModule
+}
+
+// IsCollector is true in the dry-run mode, the addons are only compared with the cluster then, so that the drift
+// is really reported.
+func (s *SyncModule) IsCollector() bool {
+	return s.KubeConf != nil && s.KubeConf.Arg.DryRun
+}
+
+func (s *SyncModule) Init() {
+	s.Name = "AddonsSyncModule"
+	s.Desc = "Sync addons"
+
+	sync := &task.LocalTask{
+		Name:   "SyncAddons",
+		Desc:   "Sync the addons with the configuration",
+		Action: new(SyncAddons),
+	}
+
+	s.Tasks = []task.Interface{
+		sync,
+	}
+}
+
+type ArtifactChartsModule struct {
+	common.ArtifactModule
+}
+
+func (a *ArtifactChartsModule) Init() {
+	a.Name = "ArtifactChartsModule"
+	a.Desc = "Pull the charts of the addons"
+
+	pull := &task.LocalTask{
+		Name:   "PullCharts",
+		Desc:   "Pull the charts of the addons into the artifact",
+		Action: new(PullCharts),
+	}
+
+	a.Tasks = []task.Interface{
+		pull,
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"k8s.io/apimachinery/pkg/runtime"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	kkregistry "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/registry"
+)
+
+// ChartsDir is the directory of the charts in the artifact.
+const ChartsDir = "charts"
+
+// chartRef returns the reference of the chart and the url of its repository. The repository is empty for the
+// charts in an OCI registry and the local charts.
+func chartRef(chart *kubekeyapiv1alpha2.Chart) (string, string) {
+	switch {
+	case registry.IsOCI(chart.Name):
+		return chart.Name, ""
+	case registry.IsOCI(chart.Repo):
+		return strings.TrimSuffix(chart.Repo, "/") + "/" + chart.Name, ""
+	case chart.Repo == "" && chart.Path != "":
+		return filepath.Join(chart.Path, chart.Name), ""
+	default:
+		return chart.Name, chart.Repo
+	}
+}
+
+// localChart returns the chart archive pulled into the artifact, it is empty if not found. The archive is named
+// <name>-<version>.tgz by helm, the latest one is used if the version is not specified.
+func localChart(dir string, chart *kubekeyapiv1alpha2.Chart) string {
+	if dir == "" {
+		return ""
+	}
+	name := path.Base(chart.Name)
+	if chart.Version != "" {
+		for _, v := range []string{chart.Version, strings.TrimPrefix(chart.Version, "v")} {
+			p := filepath.Join(dir, name+"-"+v+".tgz")
+			if _, err := os.Stat(p); err == nil {
+				return p
+			}
+		}
+		return ""
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, name+"-*.tgz"))
+	found := ""
+	var latest *versionutil.Version
+	for _, m := range matches {
+		// the archives of the charts which have the same prefix, such as nginx-ingress-1.0.0.tgz for nginx, are
+		// skipped, since their versions are not semantic versions
+		v, err := versionutil.ParseSemantic(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), name+"-"), ".tgz"))
+		if err != nil {
+			continue
+		}
+		if latest == nil || latest.LessThan(v) {
+			found, latest = m, v
+		}
+	}
+	return found
+}
+
+// newRegistryClient returns the client of the OCI registries, the credentials are taken from the registry auths of
+// the configuration.
+func newRegistryClient(auths runtime.RawExtension) (*registry.Client, error) {
+	opts := []registry.ClientOption{registry.ClientOptWriter(os.Stdout)}
+
+	entries := kkregistry.DockerRegistryAuthEntries(auths)
+	if len(entries) != 0 {
+		content, err := registryCredentials(entries)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.CreateTemp("", "kubekey-registry-*.json")
+		if err != nil {
+			return nil, errors.Wrap(err, "create the registry credentials file failed")
+		}
+		// the credentials are loaded when the client is created
+		defer os.Remove(f.Name())
+		if _, err := f.Write(content); err != nil {
+			_ = f.Close()
+			return nil, errors.Wrap(err, "write the registry credentials file failed")
+		}
+		if err := f.Close(); err != nil {
+			return nil, errors.Wrap(err, "write the registry credentials file failed")
+		}
+		opts = append(opts, registry.ClientOptCredentialsFile(f.Name()))
+	}
+
+	client, err := registry.NewClient(opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "create the registry client failed")
+	}
+	return client, nil
+}
+
+// registryCredentials returns the registry auths in the format of the docker config.json.
+func registryCredentials(entries map[string]*kkregistry.DockerRegistryEntry) ([]byte, error) {
+	type auth struct {
+		Auth string `json:"auth"`
+	}
+	config := struct {
+		Auths map[string]auth `json:"auths"`
+	}{Auths: make(map[string]auth)}
+	for r, entry := range entries {
+		if entry.Username == "" {
+			continue
+		}
+		host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(r, "https://"), "http://"), "/")
+		config.Auths[host] = auth{
+			Auth: base64.StdEncoding.EncodeToString([]byte(entry.Username + ":" + entry.Password)),
+		}
+	}
+	content, err := json.Marshal(config)
+	return content, errors.Wrap(err, "marshal the registry credentials failed")
+}
+
+// PullChart pulls the chart archive into the directory.
+func PullChart(chart *kubekeyapiv1alpha2.Chart, auths runtime.RawExtension, dir string) error {
+	ref, repoURL := chartRef(chart)
+	if chart.Repo == "" && chart.Path != "" {
+		return errors.Errorf("chart %s is a local chart, only the charts in a repository or an OCI registry can be pulled", chart.Name)
+	}
+	client, err := newRegistryClient(auths)
+	if err != nil {
+		return err
+	}
+
+	pull := action.NewPullWithOpts(action.WithConfig(&action.Configuration{RegistryClient: client}))
+	pull.Settings = cli.New()
+	pull.DestDir = dir
+	pull.RepoURL = repoURL
+	pull.Version = chart.Version
+	if _, err := pull.Run(ref); err != nil {
+		return errors.Wrapf(err, "pull chart %s failed", ref)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	kkregistry "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/registry"
+)
+
+func TestChartRef(t *testing.T) {
+	tests := []struct {
+		chart kubekeyapiv1alpha2.Chart
+		ref   string
+		repo  string
+	}{
+		{
+			chart: kubekeyapiv1alpha2.Chart{Name: "nfs-client-provisioner", Repo: "https://charts.kubesphere.io/main"},
+			ref:   "nfs-client-provisioner",
+			repo:  "https://charts.kubesphere.io/main",
+		},
+		{
+			chart: kubekeyapiv1alpha2.Chart{Name: "nginx", Repo: "oci://harbor.example.com/library/"},
+			ref:   "oci://harbor.example.com/library/nginx",
+		},
+		{
+			chart: kubekeyapiv1alpha2.Chart{Name: "oci://harbor.example.com/library/nginx"},
+			ref:   "oci://harbor.example.com/library/nginx",
+		},
+		{
+			chart: kubekeyapiv1alpha2.Chart{Name: "nginx", Path: "/mycluster/charts"},
+			ref:   "/mycluster/charts/nginx",
+		},
+	}
+	for _, tt := range tests {
+		ref, repo := chartRef(&tt.chart)
+		if ref != tt.ref || repo != tt.repo {
+			t.Errorf("chartRef(%+v) = %s, %s, want %s, %s", tt.chart, ref, repo, tt.ref, tt.repo)
+		}
+	}
+}
+
+func TestLocalChart(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"nginx-1.0.0.tgz", "nginx-1.2.0.tgz", "nginx-ingress-4.0.0.tgz",
+		"cilium-1.9.0.tgz", "cilium-1.10.0.tgz"} {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		chart kubekeyapiv1alpha2.Chart
+		want  string
+	}{
+		{chart: kubekeyapiv1alpha2.Chart{Name: "nginx", Version: "v1.0.0"}, want: "nginx-1.0.0.tgz"},
+		{chart: kubekeyapiv1alpha2.Chart{Name: "oci://harbor.example.com/library/nginx"}, want: "nginx-1.2.0.tgz"},
+		{chart: kubekeyapiv1alpha2.Chart{Name: "nginx-ingress"}, want: "nginx-ingress-4.0.0.tgz"},
+		{chart: kubekeyapiv1alpha2.Chart{Name: "cilium"}, want: "cilium-1.10.0.tgz"},
+		{chart: kubekeyapiv1alpha2.Chart{Name: "nginx", Version: "2.0.0"}},
+		{chart: kubekeyapiv1alpha2.Chart{Name: "redis"}},
+	}
+	for _, tt := range tests {
+		got := localChart(dir, &tt.chart)
+		if tt.want != "" && got != filepath.Join(dir, tt.want) || tt.want == "" && got != "" {
+			t.Errorf("localChart(%+v) = %s, want %s", tt.chart, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	content, err := registryCredentials(map[string]*kkregistry.DockerRegistryEntry{
+		"https://harbor.example.com/": {Username: "admin", Password: "Harbor12345"},
+		"anonymous.example.com":       {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"auths":{"harbor.example.com":{"auth":"YWRtaW46SGFyYm9yMTIzNDU="}}}`; string(content) != want {
+		t.Errorf("credentials = %s, want %s", content, want)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+)
+
+const (
+	// StateName is the name of the ConfigMap in which the addons installed by KubeKey are recorded.
+	StateName = "kubekey-addons"
+
+	chartAddon = "chart"
+	yamlAddon  = "yaml"
+)
+
+// record is what KubeKey knows about an installed addon. The records of the addons are kept in the cluster, so that
+// the addons removed from the configuration can be uninstalled by the next sync.
+type record struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Type      string        `json:"type"`
+	DependsOn []string      `json:"dependsOn,omitempty"`
+	Digest    string        `json:"digest,omitempty"`
+	Resources []resourceRef `json:"resources,omitempty"`
+}
+
+// resourceRef is an object applied by the yaml sources of an addon.
+type resourceRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (r resourceRef) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+func refsOf(infos []*resource.Info) []resourceRef {
+	refs := make([]resourceRef, 0, len(infos))
+	for _, info := range infos {
+		gvk := info.Object.GetObjectKind().GroupVersionKind()
+		refs = append(refs, resourceRef{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  info.Namespace,
+			Name:       info.Name,
+		})
+	}
+	return refs
+}
+
+// manifestOf returns the minimal manifest of the objects, it is used to delete the objects which are not in the
+// yaml sources any more.
+func manifestOf(refs []resourceRef) []byte {
+	var buf bytes.Buffer
+	for _, r := range refs {
+		buf.WriteString("---\n")
+		fmt.Fprintf(&buf, "apiVersion: %s\nkind: %s\nmetadata:\n  name: %s\n", r.APIVersion, r.Kind, r.Name)
+		if r.Namespace != "" {
+			fmt.Fprintf(&buf, "  namespace: %s\n", r.Namespace)
+		}
+	}
+	return buf.Bytes()
+}
+
+func loadRecords(clientset kubernetes.Interface) (map[string]*record, error) {
+	records := make(map[string]*record)
+	cm, err := clientset.CoreV1().ConfigMaps(common.ClusterConfigNamespace).Get(context.TODO(), StateName, metav1.GetOptions{})
+	if kubeerrors.IsNotFound(err) {
+		return records, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "get the addon records failed")
+	}
+	for name, data := range cm.Data {
+		r := &record{}
+		if err := json.Unmarshal([]byte(data), r); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal the record of addon %s failed", name)
+		}
+		records[name] = r
+	}
+	return records, nil
+}
+
+func saveRecords(clientset kubernetes.Interface, records map[string]*record) error {
+	data := make(map[string]string, len(records))
+	for name, r := range records {
+		content, err := json.Marshal(r)
+		if err != nil {
+			return errors.Wrapf(err, "marshal the record of addon %s failed", name)
+		}
+		data[name] = string(content)
+	}
+
+	cms := clientset.CoreV1().ConfigMaps(common.ClusterConfigNamespace)
+	cm, err := cms.Get(context.TODO(), StateName, metav1.GetOptions{})
+	if kubeerrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      StateName,
+				Namespace: common.ClusterConfigNamespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "kubekey"},
+			},
+			Data: data,
+		}
+		_, err = cms.Create(context.TODO(), cm, metav1.CreateOptions{})
+		return errors.Wrap(err, "create the addon records failed")
+	} else if err != nil {
+		return errors.Wrap(err, "get the addon records failed")
+	}
+	cm.Data = data
+	_, err = cms.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	return errors.Wrap(err, "update the addon records failed")
+}
@@ -0,0 +1,521 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+)
+
+// The actions taken by Sync on an addon.
+const (
+	ActionNone      = "none"
+	ActionInstall   = "install"
+	ActionUpgrade   = "upgrade"
+	ActionUninstall = "uninstall"
+)
+
+// SyncOptions are the options of Sync.
+type SyncOptions struct {
+	KubeConfig string
+	// ChartsDir is the directory of the chart archives unarchived from the artifact.
+	ChartsDir string
+	// DryRun only reports the drift, nothing is changed in the cluster.
+	DryRun bool
+	// Prune uninstalls the addons which are installed by KubeKey but removed from the configuration.
+	Prune bool
+}
+
+// SyncResult is the drift of an addon and the action taken to fix it.
+type SyncResult struct {
+	Name      string
+	Namespace string
+	Type      string
+	Action    string
+	Drift     []string
+}
+
+// Sync makes the addons in the cluster match the configuration. The addons are installed in the order of dependsOn,
+// and each addon is waited to be ready before the addons depending on it.
+func Sync(kubeConf *common.KubeConf, opts SyncOptions) ([]SyncResult, error) {
+	addons, err := SortAddons(kubeConf.Cluster.Addons)
+	if err != nil {
+		return nil, err
+	}
+	if len(addons) == 0 && !opts.Prune {
+		return nil, nil
+	}
+
+	restConfig, err := clientcmd.BuildConfigFromFlags("", opts.KubeConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load the kubeconfig %s failed", opts.KubeConfig)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "create the kubernetes client failed")
+	}
+	records, err := loadRecords(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SyncResult, 0, len(addons))
+	changed := false
+	defer func() {
+		// the records are saved even if an addon failed, so that the addons installed before are tracked
+		if changed && !opts.DryRun {
+			if err := saveRecords(clientset, records); err != nil {
+				logger.Log.Errorf("Failed to save the addon records: %v", err)
+			}
+		}
+	}()
+
+	desired := make(map[string]struct{}, len(addons))
+	for i := range addons {
+		addon := &addons[i]
+		desired[addon.Name] = struct{}{}
+		result, r, err := syncAddon(kubeConf, addon, records[addon.Name], opts)
+		results = append(results, result)
+		if err != nil {
+			return results, errors.Wrapf(err, "sync addon %s failed", addon.Name)
+		}
+		if !reflect.DeepEqual(records[addon.Name], r) {
+			records[addon.Name] = r
+			changed = true
+		}
+	}
+
+	if !opts.Prune {
+		return results, nil
+	}
+	for _, r := range removedRecords(records, desired) {
+		result := SyncResult{
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Type:      r.Type,
+			Action:    ActionUninstall,
+			Drift:     []string{"the addon is removed from the configuration"},
+		}
+		results = append(results, result)
+		if opts.DryRun {
+			continue
+		}
+		logger.Log.Messagef(common.LocalHost, "Uninstall addon %s", r.Name)
+		if err := uninstall(r, opts.KubeConfig); err != nil {
+			return results, errors.Wrapf(err, "uninstall addon %s failed", r.Name)
+		}
+		delete(records, r.Name)
+		changed = true
+	}
+	return results, nil
+}
+
+func syncAddon(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, old *record, opts SyncOptions) (SyncResult, *record, error) {
+	result := SyncResult{Name: addon.Name, Namespace: addonNamespace(addon), Action: ActionNone}
+	r := &record{Name: addon.Name, Namespace: result.Namespace, DependsOn: addon.DependsOn}
+
+	if old != nil && old.Namespace != result.Namespace {
+		result.Drift = append(result.Drift, fmt.Sprintf("the namespace is changed from %s", old.Namespace))
+	}
+
+	var (
+		drift     []string
+		installed bool
+		apply     func() error
+		err       error
+	)
+	if addon.Sources.Chart.Name != "" {
+		r.Type = chartAddon
+		drift, installed, err = diffChart(addon, opts.KubeConfig)
+		apply = func() error {
+			return InstallChart(kubeConf, addon, opts.KubeConfig, opts.ChartsDir)
+		}
+	} else {
+		r.Type = yamlAddon
+		var source *yamlSource
+		source, err = loadYaml(addon, opts.KubeConfig)
+		if err != nil {
+			return result, nil, err
+		}
+		r.Digest = source.digest
+		r.Resources = refsOf(source.resources)
+		drift, installed, err = source.diff(old)
+		apply = func() error {
+			if err := source.apply(kubeConf, addon, old, opts.KubeConfig); err != nil {
+				return err
+			}
+			r.Resources = refsOf(source.resources)
+			return nil
+		}
+	}
+	if err != nil {
+		return result, nil, err
+	}
+	result.Type = r.Type
+	result.Drift = append(result.Drift, drift...)
+
+	if len(result.Drift) == 0 {
+		return result, r, nil
+	}
+	if installed && (old == nil || old.Namespace == result.Namespace) {
+		result.Action = ActionUpgrade
+	} else {
+		result.Action = ActionInstall
+	}
+	if opts.DryRun {
+		return result, r, nil
+	}
+
+	if old != nil && old.Namespace != result.Namespace {
+		if err := uninstall(old, opts.KubeConfig); err != nil {
+			return result, nil, err
+		}
+	}
+	logger.Log.Messagef(common.LocalHost, "Sync addon %s (%s): %s", addon.Name, result.Action, strings.Join(result.Drift, ", "))
+	if err := retry(addon, apply); err != nil {
+		return result, nil, err
+	}
+	return result, r, nil
+}
+
+// diffChart returns the drift between the chart addon and its helm release, and whether the release exists.
+func diffChart(addon *kubekeyapiv1alpha2.Addon, kubeConfig string) ([]string, bool, error) {
+	actionConfig, settings, err := newActionConfig(kubeConfig, addonNamespace(addon))
+	if err != nil {
+		return nil, false, err
+	}
+	rel, err := action.NewGet(actionConfig).Run(addon.Name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return []string{"the release is not found"}, false, nil
+	} else if err != nil {
+		return nil, false, errors.Wrapf(err, "get the release %s failed", addon.Name)
+	}
+
+	var drift []string
+	if rel.Info.Status != release.StatusDeployed {
+		drift = append(drift, fmt.Sprintf("the release is %s", rel.Info.Status))
+	}
+	if v := addon.Sources.Chart.Version; v != "" && rel.Chart != nil && rel.Chart.Metadata.Version != strings.TrimPrefix(v, "v") &&
+		rel.Chart.Metadata.Version != v {
+		drift = append(drift, fmt.Sprintf("the chart version is %s, want %s", rel.Chart.Metadata.Version, v))
+	}
+	vals, err := chartValues(addon).MergeValues(getter.All(settings))
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "merge the values of addon %s failed", addon.Name)
+	}
+	if equal, err := valuesEqual(vals, rel.Config); err != nil {
+		return nil, false, err
+	} else if !equal {
+		drift = append(drift, "the values are changed")
+	}
+	return drift, true, nil
+}
+
+func valuesEqual(a, b map[string]interface{}) (bool, error) {
+	if len(a) == 0 && len(b) == 0 {
+		return true, nil
+	}
+	// the maps are marshaled with sorted keys
+	ac, err := json.Marshal(a)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	bc, err := json.Marshal(b)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return bytes.Equal(ac, bc), nil
+}
+
+// yamlSource is the manifests of a yaml addon.
+type yamlSource struct {
+	paths     []string
+	content   []byte
+	digest    string
+	client    *kube.Client
+	resources kube.ResourceList
+	// buildErr is the error of building the objects, the kinds of the objects might not be served until the
+	// addons it depends on are installed.
+	buildErr error
+}
+
+func loadYaml(addon *kubekeyapiv1alpha2.Addon, kubeConfig string) (*yamlSource, error) {
+	paths, err := yamlPaths(addon)
+	if err != nil {
+		return nil, err
+	}
+	content, err := readManifests(paths)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+
+	_, settings, err := newActionConfig(kubeConfig, addonNamespace(addon))
+	if err != nil {
+		return nil, err
+	}
+	client := kube.New(settings.RESTClientGetter())
+	client.Namespace = addonNamespace(addon)
+	y := &yamlSource{paths: paths, content: content, digest: hex.EncodeToString(sum[:]), client: client}
+	y.resources, y.buildErr = client.Build(bytes.NewReader(content), false)
+	return y, nil
+}
+
+// diff returns the drift between the manifests and the cluster, and whether the addon has been installed.
+func (y *yamlSource) diff(old *record) ([]string, bool, error) {
+	var drift []string
+	if old == nil {
+		drift = append(drift, "the addon is not installed by KubeKey")
+	} else if old.Digest != y.digest {
+		drift = append(drift, "the manifests are changed")
+	}
+	if y.buildErr != nil {
+		return append(drift, fmt.Sprintf("the objects cannot be resolved: %v", y.buildErr)), old != nil, nil
+	}
+
+	installed := false
+	for _, info := range y.resources {
+		if err := info.Get(); kubeerrors.IsNotFound(err) {
+			drift = append(drift, fmt.Sprintf("%s is missing", refsOf(kube.ResourceList{info})[0]))
+		} else if err != nil {
+			return nil, false, errors.Wrapf(err, "get %s failed", info.ObjectName())
+		} else {
+			installed = true
+		}
+	}
+	return drift, installed, nil
+}
+
+// apply applies the manifests, removes the objects which are not in the manifests any more and waits for the
+// objects to be ready.
+func (y *yamlSource) apply(kubeConf *common.KubeConf, addon *kubekeyapiv1alpha2.Addon, old *record, kubeConfig string) error {
+	if err := InstallYaml(y.paths, addon.Namespace, kubeConfig, kubeConf.Cluster.Kubernetes.Version); err != nil {
+		return err
+	}
+	if y.buildErr != nil {
+		resources, err := y.client.Build(bytes.NewReader(y.content), false)
+		if err != nil {
+			return errors.Wrapf(err, "build the manifests of addon %s failed", addon.Name)
+		}
+		y.resources, y.buildErr = resources, nil
+	}
+
+	if old != nil && old.Namespace == addonNamespace(addon) {
+		current := make(map[resourceRef]struct{}, len(y.resources))
+		for _, ref := range refsOf(y.resources) {
+			current[ref] = struct{}{}
+		}
+		var stale []resourceRef
+		for _, ref := range old.Resources {
+			if _, ok := current[ref]; !ok {
+				stale = append(stale, ref)
+			}
+		}
+		if err := deleteRefs(y.client, stale); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(y.client.Wait(y.resources, addonTimeout(addon)), "wait for the addon to be ready failed")
+}
+
+func uninstall(r *record, kubeConfig string) error {
+	actionConfig, settings, err := newActionConfig(kubeConfig, r.Namespace)
+	if err != nil {
+		return err
+	}
+	if r.Type == chartAddon {
+		if _, err := action.NewUninstall(actionConfig).Run(r.Name); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return errors.Wrapf(err, "uninstall the release %s failed", r.Name)
+		}
+		return nil
+	}
+	client := kube.New(settings.RESTClientGetter())
+	client.Namespace = r.Namespace
+	return deleteRefs(client, r.Resources)
+}
+
+func deleteRefs(client *kube.Client, refs []resourceRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	resources, err := client.Build(bytes.NewReader(manifestOf(refs)), false)
+	if err != nil {
+		return errors.Wrap(err, "build the objects to delete failed")
+	}
+	if _, errs := client.Delete(resources); len(errs) != 0 {
+		return errors.Errorf("delete the objects failed: %v", errs)
+	}
+	return nil
+}
+
+func retry(addon *kubekeyapiv1alpha2.Addon, fn func() error) error {
+	var err error
+	for i := 0; i <= addon.Retries; i++ {
+		if i > 0 {
+			logger.Log.Warningf("Retry addon %s after %ds: %v", addon.Name, addon.Delay, err)
+			time.Sleep(time.Duration(addon.Delay) * time.Second)
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// SortAddons returns the addons in the order of dependsOn, the order in the configuration is kept for the
+// independent addons.
+func SortAddons(addons []kubekeyapiv1alpha2.Addon) ([]kubekeyapiv1alpha2.Addon, error) {
+	index := make(map[string]int, len(addons))
+	for i, addon := range addons {
+		if addon.Name == "" {
+			return nil, errors.Errorf("the name of the addon %d is empty", i)
+		}
+		if _, ok := index[addon.Name]; ok {
+			return nil, errors.Errorf("addon %s is duplicated", addon.Name)
+		}
+		index[addon.Name] = i
+	}
+
+	names := make([]string, 0, len(addons))
+	deps := make(map[string][]string, len(addons))
+	for _, addon := range addons {
+		for _, dep := range addon.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return nil, errors.Errorf("addon %s depends on %s which is not found", addon.Name, dep)
+			}
+		}
+		names = append(names, addon.Name)
+		deps[addon.Name] = addon.DependsOn
+	}
+	sorted, err := sortByDependencies(names, deps)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]kubekeyapiv1alpha2.Addon, 0, len(addons))
+	for _, name := range sorted {
+		result = append(result, addons[index[name]])
+	}
+	return result, nil
+}
+
+// removedRecords returns the records of the addons which are not desired, in the reverse order of dependsOn so that
+// an addon is uninstalled before the addons it depends on.
+func removedRecords(records map[string]*record, desired map[string]struct{}) []*record {
+	var names []string
+	deps := make(map[string][]string)
+	for name, r := range records {
+		if _, ok := desired[name]; !ok {
+			names = append(names, name)
+			deps[name] = r.DependsOn
+		}
+	}
+	// the records are in a map, they are sorted by name to make the order stable
+	sort.Strings(names)
+	sorted, err := sortByDependencies(names, deps)
+	if err != nil {
+		// the records might be edited by hand, fall back to the order of names
+		sorted = names
+	}
+
+	removed := make([]*record, 0, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		removed = append(removed, records[sorted[i]])
+	}
+	return removed
+}
+
+// sortByDependencies sorts the names topologically, the dependencies which are not in the names are ignored.
+func sortByDependencies(names []string, deps map[string][]string) ([]string, error) {
+	pending := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		pending[name] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for len(pending) > 0 {
+		progress := false
+		for _, name := range names {
+			if _, ok := pending[name]; !ok {
+				continue
+			}
+			ready := true
+			for _, dep := range deps[name] {
+				if _, ok := pending[dep]; ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, name)
+				delete(pending, name)
+				progress = true
+			}
+		}
+		if !progress {
+			var cycle []string
+			for _, name := range names {
+				if _, ok := pending[name]; ok {
+					cycle = append(cycle, name)
+				}
+			}
+			return nil, errors.Errorf("circular dependencies are found in addons: %s", strings.Join(cycle, ", "))
+		}
+	}
+	return sorted, nil
+}
+
+// PrintSyncResults prints the drift of the addons and the actions taken.
+func PrintSyncResults(out io.Writer, results []SyncResult, dryRun bool) {
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ADDON\tNAMESPACE\tTYPE\tACTION\tDRIFT")
+	for _, r := range results {
+		act := r.Action
+		if dryRun && act != ActionNone {
+			act += " (dry run)"
+		}
+		drift := strings.Join(r.Drift, "; ")
+		if drift == "" {
+			drift = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Name, r.Namespace, r.Type, act, drift)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,129 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+)
+
+func TestSortAddons(t *testing.T) {
+	tests := []struct {
+		name   string
+		addons []kubekeyapiv1alpha2.Addon
+		want   []string
+		err    string
+	}{
+		{
+			name: "keep the order of independent addons",
+			addons: []kubekeyapiv1alpha2.Addon{
+				{Name: "c"}, {Name: "a"}, {Name: "b"},
+			},
+			want: []string{"c", "a", "b"},
+		},
+		{
+			name: "dependencies first",
+			addons: []kubekeyapiv1alpha2.Addon{
+				{Name: "app", DependsOn: []string{"storage", "ingress"}},
+				{Name: "storage"},
+				{Name: "ingress", DependsOn: []string{"cert-manager"}},
+				{Name: "cert-manager"},
+			},
+			want: []string{"storage", "cert-manager", "ingress", "app"},
+		},
+		{
+			name: "unknown dependency",
+			addons: []kubekeyapiv1alpha2.Addon{
+				{Name: "app", DependsOn: []string{"storage"}},
+			},
+			err: "depends on storage which is not found",
+		},
+		{
+			name: "circular dependencies",
+			addons: []kubekeyapiv1alpha2.Addon{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+				{Name: "c"},
+			},
+			err: "circular dependencies are found in addons: a, b",
+		},
+		{
+			name: "duplicated addons",
+			addons: []kubekeyapiv1alpha2.Addon{
+				{Name: "a"}, {Name: "a"},
+			},
+			err: "addon a is duplicated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := SortAddons(tt.addons)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, addon := range sorted {
+				names = append(names, addon.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("order = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemovedRecords(t *testing.T) {
+	records := map[string]*record{
+		"app":     {Name: "app", DependsOn: []string{"storage"}},
+		"storage": {Name: "storage"},
+		"kept":    {Name: "kept"},
+	}
+	var names []string
+	for _, r := range removedRecords(records, map[string]struct{}{"kept": {}}) {
+		names = append(names, r.Name)
+	}
+	if want := []string{"app", "storage"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("removed = %v, want %v", names, want)
+	}
+}
+
+func TestValuesEqual(t *testing.T) {
+	equal, err := valuesEqual(map[string]interface{}{}, nil)
+	if err != nil || !equal {
+		t.Errorf("empty values are not equal: %v", err)
+	}
+	equal, err = valuesEqual(
+		map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "d"}},
+		map[string]interface{}{"b": map[string]interface{}{"c": "d"}, "a": 1},
+	)
+	if err != nil || !equal {
+		t.Errorf("the same values are not equal: %v", err)
+	}
+	equal, _ = valuesEqual(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 2})
+	if equal {
+		t.Errorf("different values are equal")
+	}
+}
@@ -18,8 +18,11 @@ package addons
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
+	"github.com/pkg/errors"
+
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
@@ -30,10 +33,51 @@ type Install struct {
 }
 
 func (i *Install) Execute(runtime connector.Runtime) error {
-	nums := len(i.KubeConf.Cluster.Addons)
-	for index, addon := range i.KubeConf.Cluster.Addons {
-		logger.Log.Messagef(runtime.RemoteHost().GetName(), "Install addon [%v-%v]: %s", nums, index, addon.Name)
-		if err := InstallAddons(i.KubeConf, &addon, filepath.Join(runtime.GetWorkDir(), fmt.Sprintf("config-%s", runtime.GetObjName()))); err != nil {
+	// the addons removed from the configuration are only uninstalled by kk addons sync
+	_, err := Sync(i.KubeConf, SyncOptions{KubeConfig: kubeConfigPath(runtime), ChartsDir: chartsDir(runtime)})
+	return err
+}
+
+type SyncAddons struct {
+	common.KubeAction
+}
+
+func (s *SyncAddons) Execute(runtime connector.Runtime) error {
+	results, err := Sync(s.KubeConf, SyncOptions{
+		KubeConfig: kubeConfigPath(runtime),
+		ChartsDir:  chartsDir(runtime),
+		DryRun:     s.KubeConf.Arg.DryRun,
+		Prune:      true,
+	})
+	PrintSyncResults(os.Stdout, results, s.KubeConf.Arg.DryRun)
+	return err
+}
+
+// chartsDir returns the directory of the chart archives, the artifact is unarchived into the work dir.
+func chartsDir(runtime connector.Runtime) string {
+	return filepath.Join(runtime.GetWorkDir(), ChartsDir)
+}
+
+func kubeConfigPath(runtime connector.Runtime) string {
+	return filepath.Join(runtime.GetWorkDir(), fmt.Sprintf("config-%s", runtime.GetObjName()))
+}
+
+type PullCharts struct {
+	common.ArtifactAction
+}
+
+func (p *PullCharts) Execute(runtime connector.Runtime) error {
+	if len(p.Manifest.Spec.Charts) == 0 {
+		return nil
+	}
+	dir := filepath.Join(runtime.GetWorkDir(), common.Artifact, ChartsDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "create dir %s failed", dir)
+	}
+	for i := range p.Manifest.Spec.Charts {
+		chart := &p.Manifest.Spec.Charts[i]
+		logger.Log.Messagef(common.LocalHost, "Pull chart %s %s", chart.Name, chart.Version)
+		if err := PullChart(chart, p.Manifest.Spec.ManifestRegistry.Auths, dir); err != nil {
 			return err
 		}
 	}
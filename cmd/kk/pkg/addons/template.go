@@ -0,0 +1,158 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	helmLoader "helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+)
+
+// AddonImages returns the images in the manifests of the addon. The chart is rendered locally like helm template
+// with the Kubernetes version, so that no cluster is needed.
+func AddonImages(addon *kubekeyapiv1alpha2.Addon, auths runtime.RawExtension, kubeVersion string) ([]string, error) {
+	var manifests []byte
+	if addon.Sources.Chart.Name != "" {
+		manifest, err := TemplateChart(addon, auths, kubeVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "render the chart of addon %s failed", addon.Name)
+		}
+		manifests = []byte(manifest)
+	} else if len(addon.Sources.Yaml.Path) != 0 {
+		paths, err := yamlPaths(addon)
+		if err != nil {
+			return nil, err
+		}
+		if manifests, err = readManifests(paths); err != nil {
+			return nil, errors.Wrapf(err, "read the manifests of addon %s failed", addon.Name)
+		}
+	}
+	return ManifestImages(manifests)
+}
+
+// TemplateChart renders the chart addon without a cluster, and returns the manifests.
+func TemplateChart(addon *kubekeyapiv1alpha2.Addon, auths runtime.RawExtension, kubeVersion string) (string, error) {
+	actionConfig := &action.Configuration{Log: debug}
+	registryClient, err := newRegistryClient(auths)
+	if err != nil {
+		return "", err
+	}
+	actionConfig.RegistryClient = registryClient
+
+	client := action.NewInstall(actionConfig)
+	client.DryRun = true
+	client.ClientOnly = true
+	client.Replace = true
+	client.IncludeCRDs = true
+	client.ReleaseName = addon.Name
+	client.Namespace = addonNamespace(addon)
+	client.Keyring = defaultKeyring()
+	client.Version = addon.Sources.Chart.Version
+	if kubeVersion != "" {
+		if client.KubeVersion, err = chartutil.ParseKubeVersion(kubeVersion); err != nil {
+			return "", errors.Wrapf(err, "invalid kubernetes version %s", kubeVersion)
+		}
+	}
+
+	chartName, repoURL := chartRef(&addon.Sources.Chart)
+	client.RepoURL = repoURL
+	settings := cli.New()
+	cp, err := client.ChartPathOptions.LocateChart(chartName, settings)
+	if err != nil {
+		return "", err
+	}
+	vals, err := chartValues(addon).MergeValues(getter.All(settings))
+	if err != nil {
+		return "", err
+	}
+	ch, err := helmLoader.Load(cp)
+	if err != nil {
+		return "", err
+	}
+	if err := checkIfInstallable(ch); err != nil {
+		return "", err
+	}
+	if req := ch.Metadata.Dependencies; req != nil {
+		if err := action.CheckDependencies(ch, req); err != nil {
+			return "", err
+		}
+	}
+
+	rel, err := client.Run(ch, vals)
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+// ManifestImages returns the sorted images in the multi-document YAML manifests, the images are the string values of
+// the image fields, such as the ones of the containers.
+func ManifestImages(manifests []byte) ([]string, error) {
+	found := make(map[string]struct{})
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifests)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "read the manifests failed")
+		}
+		var obj interface{}
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			return nil, errors.Wrap(err, "unmarshal the manifests failed")
+		}
+		collectImages(obj, found)
+	}
+
+	images := make([]string, 0, len(found))
+	for image := range found {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+func collectImages(obj interface{}, found map[string]struct{}) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if image, ok := value.(string); ok && key == "image" && image != "" {
+				found[image] = struct{}{}
+				continue
+			}
+			collectImages(value, found)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectImages(item, found)
+		}
+	}
+}
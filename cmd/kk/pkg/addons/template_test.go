@@ -0,0 +1,60 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package addons
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestManifestImages(t *testing.T) {
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox:1.36
+      containers:
+      - name: app
+        image: registry.example.com/app/server:v1.0.0
+      - name: sidecar
+        image: busybox:1.36
+---
+# an empty document
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  image: quay.io/app/worker:v1.0.0
+  images: ignored
+`
+	got, err := ManifestImages([]byte(manifests))
+	if err != nil {
+		t.Fatalf("ManifestImages() error = %v", err)
+	}
+	want := []string{"busybox:1.36", "quay.io/app/worker:v1.0.0", "registry.example.com/app/server:v1.0.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ManifestImages() = %v, want %v", got, want)
+	}
+}
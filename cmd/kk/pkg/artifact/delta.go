@@ -0,0 +1,177 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	coreutil "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+)
+
+// DeltaFile is the file in the root of a delta artifact, it records the base artifact which the delta is exported from.
+const DeltaFile = "delta.json"
+
+// Delta is the content of the DeltaFile.
+type Delta struct {
+	// Base is the md5 of the base artifact, the same as the one recorded when an artifact is unarchived.
+	Base string `json:"base"`
+}
+
+// RemoveBaseFiles removes the files of the artifact dir which are already present in the base artifact, and returns
+// the number of the removed files. The blobs of the OCI images are named by their digests, they are removed if the
+// base contains the same digests. The other files, such as the binaries and the ISO repositories, are removed if the
+// base contains the same file with the same content.
+func RemoveBaseFiles(dir, base string) (int, error) {
+	removed := 0
+	err := walkArtifact(base, func(hdr *tar.Header, r io.Reader) error {
+		name := path.Clean(hdr.Name)
+		if name == DeltaFile {
+			return errors.Errorf("the base artifact %s is a delta artifact", base)
+		}
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		info, err := os.Stat(file)
+		if err != nil || !info.Mode().IsRegular() || info.Size() != hdr.Size {
+			return nil
+		}
+		if !isBlob(name) {
+			baseSum, err := sha256sum(r)
+			if err != nil {
+				return errors.Wrapf(err, "read %s of the base artifact failed", name)
+			}
+			f, err := os.Open(file)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			sum, err := sha256sum(f)
+			f.Close()
+			if err != nil {
+				return errors.Wrapf(err, "read %s failed", file)
+			}
+			if sum != baseSum {
+				return nil
+			}
+		}
+		if err := os.Remove(file); err != nil {
+			return errors.WithStack(err)
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// ReadDelta returns the DeltaFile of the artifact, it returns nil if the artifact is not a delta.
+func ReadDelta(artifact string) (*Delta, error) {
+	var delta *Delta
+	err := walkArtifact(artifact, func(hdr *tar.Header, r io.Reader) error {
+		if delta != nil || path.Clean(hdr.Name) != DeltaFile {
+			return nil
+		}
+		delta = new(Delta)
+		if err := json.NewDecoder(r).Decode(delta); err != nil {
+			return errors.Wrapf(err, "decode %s of %s failed", DeltaFile, artifact)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delta, nil
+}
+
+// CheckDelta checks the artifact is a delta artifact exported from the base artifact. If the base is empty, it checks
+// the artifact is not a delta artifact, which is incomplete without its base.
+func CheckDelta(artifact, base string) error {
+	delta, err := ReadDelta(artifact)
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		if delta != nil {
+			return errors.Errorf("the artifact %s is a delta artifact, it can only be imported with its base artifact by kk artifact import --base", artifact)
+		}
+		return nil
+	}
+	if delta == nil {
+		return errors.Errorf("the artifact %s is not a delta artifact, it can be imported without the base", artifact)
+	}
+	baseMd5, err := coreutil.FileMD5(base)
+	if err != nil {
+		return errors.Wrapf(err, "get the md5 of the base artifact %s failed", base)
+	}
+	if baseMd5 != delta.Base {
+		return errors.Errorf("the delta artifact %s is not exported from the base artifact %s", artifact, base)
+	}
+	return nil
+}
+
+// isBlob returns whether the file is a blob of the OCI image layout, such as images/blobs/sha256/<hex>.
+func isBlob(name string) bool {
+	parts := strings.Split(name, "/")
+	return len(parts) >= 3 && parts[len(parts)-3] == "blobs"
+}
+
+// walkArtifact calls fn with each regular file in the gzip tarball of the artifact.
+func walkArtifact(artifact string, fn func(hdr *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(artifact)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return errors.Wrapf(err, "read the artifact %s failed", artifact)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "read the artifact %s failed", artifact)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func sha256sum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,138 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"path/filepath"
+	"testing"
+
+	coreutil "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		if err := coreutil.WriteFile(filepath.Join(dir, name), []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRemoveBaseFiles(t *testing.T) {
+	tmp := t.TempDir()
+	baseDir, dir := filepath.Join(tmp, "base"), filepath.Join(tmp, "artifact")
+	writeFiles(t, baseDir, map[string]string{
+		"images/index.json":          `{"manifests":[{"digest":"sha256:aaaa"}]}`,
+		"images/blobs/sha256/aaaa":   "layer a",
+		"images/oci-layout":          `{"imageLayoutVersion":"1.0.0"}`,
+		"kube/v1.25.3/amd64/kubeadm": "kubeadm v1.25.3",
+		"cni/v1.2.0/amd64/cni.tgz":   "cni v1.2.0",
+	})
+	base := filepath.Join(tmp, "base.tar.gz")
+	if err := coreutil.Tar(baseDir, base, baseDir); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"images/index.json":          `{"manifests":[{"digest":"sha256:aaaa"},{"digest":"sha256:bbbb"}]}`,
+		"images/blobs/sha256/aaaa":   "layer a",
+		"images/blobs/sha256/bbbb":   "layer b",
+		"images/oci-layout":          `{"imageLayoutVersion":"1.0.0"}`,
+		"kube/v1.26.0/amd64/kubeadm": "kubeadm v1.26.0",
+		"cni/v1.2.0/amd64/cni.tgz":   "cni v1.2.1",
+	})
+
+	removed, err := RemoveBaseFiles(dir, base)
+	if err != nil {
+		t.Fatalf("RemoveBaseFiles() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("RemoveBaseFiles() removes %d files, want 2", removed)
+	}
+	for name, exist := range map[string]bool{
+		"images/index.json":          true,
+		"images/blobs/sha256/aaaa":   false,
+		"images/blobs/sha256/bbbb":   true,
+		"images/oci-layout":          false,
+		"kube/v1.26.0/amd64/kubeadm": true,
+		"cni/v1.2.0/amd64/cni.tgz":   true,
+	} {
+		if coreutil.IsExist(filepath.Join(dir, name)) != exist {
+			t.Errorf("%s exists: %v, want %v", name, !exist, exist)
+		}
+	}
+
+	// a delta can not be the base of another delta
+	writeFiles(t, dir, map[string]string{DeltaFile: `{"base":"0123"}`})
+	delta := filepath.Join(tmp, "delta.tar.gz")
+	if err := coreutil.Tar(dir, delta, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RemoveBaseFiles(dir, delta); err == nil {
+		t.Errorf("RemoveBaseFiles() with a delta base returns no error")
+	}
+	if d, err := ReadDelta(delta); err != nil || d == nil || d.Base != "0123" {
+		t.Errorf("ReadDelta() = %v, %v, want the base 0123", d, err)
+	}
+	if d, err := ReadDelta(base); err != nil || d != nil {
+		t.Errorf("ReadDelta() of a full artifact = %v, %v, want nil", d, err)
+	}
+}
+
+func TestCheckDelta(t *testing.T) {
+	tmp := t.TempDir()
+	full := filepath.Join(tmp, "base.tar.gz")
+	writeFiles(t, filepath.Join(tmp, "base"), map[string]string{"kube/v1.25.3/amd64/kubeadm": "kubeadm v1.25.3"})
+	if err := coreutil.Tar(filepath.Join(tmp, "base"), full, filepath.Join(tmp, "base")); err != nil {
+		t.Fatal(err)
+	}
+	baseMd5, err := coreutil.FileMD5(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newDelta := func(name, base string) string {
+		dir := filepath.Join(tmp, name)
+		writeFiles(t, dir, map[string]string{
+			DeltaFile:                    `{"base":"` + base + `"}`,
+			"kube/v1.26.0/amd64/kubeadm": "kubeadm v1.26.0",
+		})
+		artifact := dir + ".tar.gz"
+		if err := coreutil.Tar(dir, artifact, dir); err != nil {
+			t.Fatal(err)
+		}
+		return artifact
+	}
+	delta, other := newDelta("delta", baseMd5), newDelta("other", "0123")
+
+	tests := []struct {
+		name     string
+		artifact string
+		base     string
+		wantErr  bool
+	}{
+		{name: "full artifact", artifact: full},
+		{name: "delta without the base", artifact: delta, wantErr: true},
+		{name: "delta with the base", artifact: delta, base: full},
+		{name: "delta with another base", artifact: other, base: full, wantErr: true},
+		{name: "full artifact with a base", artifact: full, base: full, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckDelta(tt.artifact, tt.base); (err != nil) != tt.wantErr {
+				t.Errorf("CheckDelta() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/addons"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/images"
+)
+
+// binaryNames are the binaries which are named by the files instead of the dirs of their types, they share the dirs
+// with the other binaries.
+var binaryNames = map[string]bool{
+	"calicoctl": true,
+	"k3s":       true,
+	"k8e":       true,
+}
+
+// Binary is a component binary in an artifact.
+type Binary struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Arches  []string `json:"arches"`
+}
+
+// Contents are the contents of an artifact.
+type Contents struct {
+	// Delta is set if the artifact is a delta artifact.
+	Delta  *Delta `json:"delta,omitempty"`
+	Signed bool   `json:"signed"`
+	// Binaries are sorted by the names and the versions.
+	Binaries []Binary `json:"binaries"`
+	// OperatingSystems are the ISO repositories, the local paths of the ISOs are the paths in the artifact.
+	OperatingSystems []kubekeyv1alpha2.OperatingSystem `json:"operatingSystems"`
+	// Images are the images in the OCI index by platform.
+	Images map[string][]string `json:"images"`
+	Charts []string            `json:"charts"`
+}
+
+// ContentsDiff is the difference between the contents of two artifacts.
+type ContentsDiff struct {
+	Added   *Contents `json:"added"`
+	Removed *Contents `json:"removed"`
+}
+
+// Inspect returns the contents of the artifact, the tarball is read as a stream without being unarchived.
+func Inspect(artifact string) (*Contents, error) {
+	c := newContents()
+	err := walkArtifact(artifact, func(hdr *tar.Header, r io.Reader) error {
+		name := path.Clean(hdr.Name)
+		parts := strings.Split(name, "/")
+		switch {
+		case name == DeltaFile:
+			c.Delta = new(Delta)
+			if err := json.NewDecoder(r).Decode(c.Delta); err != nil {
+				return errors.Wrapf(err, "decode %s of the artifact %s failed", DeltaFile, artifact)
+			}
+		case name == SignatureFile:
+			c.Signed = true
+		case parts[0] == "images":
+			if name != "images/index.json" {
+				return nil
+			}
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return errors.Wrapf(err, "read the images of the artifact %s failed", artifact)
+			}
+			if c.Images, err = images.IndexImages(data); err != nil {
+				return errors.Wrapf(err, "invalid images of the artifact %s", artifact)
+			}
+		case parts[0] == "repository" && len(parts) == 5:
+			// Ex: repository/amd64/ubuntu/20.04/ubuntu-20.04-amd64.iso
+			c.OperatingSystems = append(c.OperatingSystems, kubekeyv1alpha2.OperatingSystem{
+				Arch:       parts[1],
+				Id:         parts[2],
+				Version:    parts[3],
+				Repository: kubekeyv1alpha2.Repository{Iso: kubekeyv1alpha2.Iso{LocalPath: name}},
+			})
+		case parts[0] == addons.ChartsDir && len(parts) == 2:
+			c.Charts = append(c.Charts, parts[1])
+		case parts[0] == files.REGISTRY && len(parts) == 5:
+			// Ex: registry/harbor/v2.5.3/amd64/harbor-offline-installer-v2.5.3.tgz
+			c.addBinary(parts[1], parts[2], parts[3])
+		case len(parts) == 4:
+			// Ex: kube/v1.23.10/amd64/kubeadm
+			if binaryNames[parts[3]] {
+				c.addBinary(parts[3], parts[1], parts[2])
+			} else {
+				c.addBinary(parts[0], parts[1], parts[2])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.sort()
+	return c, nil
+}
+
+// Diff returns the contents which are added to the artifact and removed from it compared with the other one.
+func (c *Contents) Diff(other *Contents) *ContentsDiff {
+	return &ContentsDiff{Added: c.subtract(other), Removed: other.subtract(c)}
+}
+
+// Empty returns whether there are no binaries, ISO repositories, images and charts in the contents.
+func (c *Contents) Empty() bool {
+	return len(c.Binaries) == 0 && len(c.OperatingSystems) == 0 && len(c.Images) == 0 && len(c.Charts) == 0
+}
+
+func newContents() *Contents {
+	return &Contents{
+		Binaries:         make([]Binary, 0),
+		OperatingSystems: make([]kubekeyv1alpha2.OperatingSystem, 0),
+		Images:           make(map[string][]string),
+		Charts:           make([]string, 0),
+	}
+}
+
+// addBinary adds the arch to the binary, the binaries with several files, such as kube, are added once for each arch.
+func (c *Contents) addBinary(name, version, arch string) {
+	for i := range c.Binaries {
+		b := &c.Binaries[i]
+		if b.Name != name || b.Version != version {
+			continue
+		}
+		for _, a := range b.Arches {
+			if a == arch {
+				return
+			}
+		}
+		b.Arches = append(b.Arches, arch)
+		return
+	}
+	c.Binaries = append(c.Binaries, Binary{Name: name, Version: version, Arches: []string{arch}})
+}
+
+func (c *Contents) sort() {
+	sort.Slice(c.Binaries, func(i, j int) bool {
+		if c.Binaries[i].Name != c.Binaries[j].Name {
+			return c.Binaries[i].Name < c.Binaries[j].Name
+		}
+		return c.Binaries[i].Version < c.Binaries[j].Version
+	})
+	for _, b := range c.Binaries {
+		sort.Strings(b.Arches)
+	}
+	sort.Slice(c.OperatingSystems, func(i, j int) bool {
+		return c.OperatingSystems[i].Repository.Iso.LocalPath < c.OperatingSystems[j].Repository.Iso.LocalPath
+	})
+	for _, names := range c.Images {
+		sort.Strings(names)
+	}
+	sort.Strings(c.Charts)
+}
+
+// subtract returns the contents which are not in the other one.
+func (c *Contents) subtract(other *Contents) *Contents {
+	result := newContents()
+
+	binaries := make(map[string]bool)
+	for _, b := range other.Binaries {
+		for _, arch := range b.Arches {
+			binaries[fmt.Sprintf("%s/%s/%s", b.Name, b.Version, arch)] = true
+		}
+	}
+	for _, b := range c.Binaries {
+		for _, arch := range b.Arches {
+			if !binaries[fmt.Sprintf("%s/%s/%s", b.Name, b.Version, arch)] {
+				result.addBinary(b.Name, b.Version, arch)
+			}
+		}
+	}
+
+	isos := make(map[string]bool)
+	for _, sys := range other.OperatingSystems {
+		isos[sys.Repository.Iso.LocalPath] = true
+	}
+	for _, sys := range c.OperatingSystems {
+		if !isos[sys.Repository.Iso.LocalPath] {
+			result.OperatingSystems = append(result.OperatingSystems, sys)
+		}
+	}
+
+	for platform, names := range c.Images {
+		found := make(map[string]bool)
+		for _, name := range other.Images[platform] {
+			found[name] = true
+		}
+		for _, name := range names {
+			if !found[name] {
+				result.Images[platform] = append(result.Images[platform], name)
+			}
+		}
+	}
+
+	charts := make(map[string]bool)
+	for _, chart := range other.Charts {
+		charts[chart] = true
+	}
+	for _, chart := range c.Charts {
+		if !charts[chart] {
+			result.Charts = append(result.Charts, chart)
+		}
+	}
+
+	result.sort()
+	return result
+}
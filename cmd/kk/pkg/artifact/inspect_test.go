@@ -0,0 +1,126 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	coreutil "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+)
+
+func writeArtifact(t *testing.T, name string, files map[string]string) string {
+	dir := filepath.Join(t.TempDir(), name)
+	writeFiles(t, dir, files)
+	artifact := dir + ".tar.gz"
+	if err := coreutil.Tar(dir, artifact, dir); err != nil {
+		t.Fatal(err)
+	}
+	return artifact
+}
+
+func indexJSON(refs ...string) string {
+	manifests := ""
+	for i, ref := range refs {
+		if i > 0 {
+			manifests += ","
+		}
+		manifests += fmt.Sprintf(`{"annotations":{"org.opencontainers.image.ref.name":%q}}`, ref)
+	}
+	return fmt.Sprintf(`{"manifests":[%s]}`, manifests)
+}
+
+func TestInspect(t *testing.T) {
+	old, err := Inspect(writeArtifact(t, "old", map[string]string{
+		"images/index.json":           indexJSON("kubesphere:kube-apiserver:v1.23.10-amd64", "calico:cni:v3.23.2-amd64"),
+		"kube/v1.23.10/amd64/kubeadm": "kubeadm",
+		"kube/v1.23.10/amd64/kubelet": "kubelet",
+		"cni/v3.23.2/amd64/calicoctl": "calicoctl",
+		"charts/nginx-13.2.0.tgz":     "nginx",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := Inspect(writeArtifact(t, "new", map[string]string{
+		DeltaFile:     `{"base":"0123"}`,
+		SignatureFile: "signature",
+		"images/index.json": indexJSON("kubesphere:kube-apiserver:v1.24.3-amd64", "kubesphere:kube-apiserver:v1.24.3-arm-v7",
+			"kubesphere:kube-apiserver:sha256-0000000000000000000000000000000000000000000000000000000000000000.sig",
+			"calico:cni:v3.23.2-amd64"),
+		"kube/v1.23.10/amd64/kubeadm":                          "kubeadm",
+		"kube/v1.24.3/amd64/kubeadm":                           "kubeadm",
+		"kube/v1.24.3/arm64/kubeadm":                           "kubeadm",
+		"registry/harbor/v2.5.3/amd64/harbor.tgz":              "harbor",
+		"repository/amd64/ubuntu/20.04/ubuntu-20.04-amd64.iso": "iso",
+		"cni/v3.23.2/amd64/calicoctl":                          "calicoctl",
+		"charts/nginx-13.2.0.tgz":                              "nginx",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contents.Delta == nil || contents.Delta.Base != "0123" || !contents.Signed {
+		t.Errorf("Inspect() returns delta %v and signed %v", contents.Delta, contents.Signed)
+	}
+	wantBinaries := []Binary{
+		{Name: "calicoctl", Version: "v3.23.2", Arches: []string{"amd64"}},
+		{Name: "harbor", Version: "v2.5.3", Arches: []string{"amd64"}},
+		{Name: "kube", Version: "v1.23.10", Arches: []string{"amd64"}},
+		{Name: "kube", Version: "v1.24.3", Arches: []string{"amd64", "arm64"}},
+	}
+	if !reflect.DeepEqual(contents.Binaries, wantBinaries) {
+		t.Errorf("Inspect() returns binaries %v, want %v", contents.Binaries, wantBinaries)
+	}
+	if len(contents.OperatingSystems) != 1 || contents.OperatingSystems[0].Id != "ubuntu" || contents.OperatingSystems[0].Version != "20.04" {
+		t.Errorf("Inspect() returns operating systems %v", contents.OperatingSystems)
+	}
+	wantImages := map[string][]string{
+		"amd64":  {"calico/cni:v3.23.2", "kubesphere/kube-apiserver:v1.24.3"},
+		"arm/v7": {"kubesphere/kube-apiserver:v1.24.3"},
+	}
+	if !reflect.DeepEqual(contents.Images, wantImages) {
+		t.Errorf("Inspect() returns images %v, want %v", contents.Images, wantImages)
+	}
+
+	diff := contents.Diff(old)
+	wantAdded := []Binary{
+		{Name: "harbor", Version: "v2.5.3", Arches: []string{"amd64"}},
+		{Name: "kube", Version: "v1.24.3", Arches: []string{"amd64", "arm64"}},
+	}
+	if !reflect.DeepEqual(diff.Added.Binaries, wantAdded) {
+		t.Errorf("Diff() adds binaries %v, want %v", diff.Added.Binaries, wantAdded)
+	}
+	wantAddedImages := map[string][]string{
+		"amd64":  {"kubesphere/kube-apiserver:v1.24.3"},
+		"arm/v7": {"kubesphere/kube-apiserver:v1.24.3"},
+	}
+	if !reflect.DeepEqual(diff.Added.Images, wantAddedImages) {
+		t.Errorf("Diff() adds images %v, want %v", diff.Added.Images, wantAddedImages)
+	}
+	if len(diff.Added.OperatingSystems) != 1 || len(diff.Added.Charts) != 0 {
+		t.Errorf("Diff() adds operating systems %v and charts %v", diff.Added.OperatingSystems, diff.Added.Charts)
+	}
+	wantRemoved := map[string][]string{"amd64": {"kubesphere/kube-apiserver:v1.23.10"}}
+	if !reflect.DeepEqual(diff.Removed.Images, wantRemoved) {
+		t.Errorf("Diff() removes images %v, want %v", diff.Removed.Images, wantRemoved)
+	}
+	if len(diff.Removed.Binaries) != 0 || len(diff.Removed.OperatingSystems) != 0 || len(diff.Removed.Charts) != 0 {
+		t.Errorf("Diff() removes %+v", diff.Removed)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils/certs"
+)
+
+const (
+	// ChecksumsFile is the file in the root of an artifact, it records the sha256 of every file in the artifact in the
+	// format of sha256sum.
+	ChecksumsFile = "sha256sums.txt"
+	// SignatureFile is the detached signature of the ChecksumsFile, it is encoded in base64 like cosign sign-blob.
+	SignatureFile = ChecksumsFile + ".sig"
+	// CertificateFile is the x509 certificate of the key which signs the ChecksumsFile.
+	CertificateFile = ChecksumsFile + ".pem"
+)
+
+// ErrNoChecksums is returned by VerifyArtifact if the artifact is exported without the ChecksumsFile.
+var ErrNoChecksums = errors.New("the artifact has no checksums")
+
+// VerifyOptions are the options to verify the signature of an artifact, the signature is only verified if one of them
+// is set.
+type VerifyOptions struct {
+	// Key is the path of the public key or the certificate which the signature is verified with.
+	Key string
+	// CA is the path of the CA certificates which the certificate in the artifact is verified with.
+	CA string
+}
+
+// WriteChecksums writes the ChecksumsFile of the files in the artifact dir.
+func WriteChecksums(dir string) error {
+	lines := make([]string, 0)
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if isIntegrityFile(name) {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		sum, err := sha256sum(f)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s\n", sum, name))
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(errors.WithStack(err), "compute the checksums of %s failed", dir)
+	}
+	sort.Strings(lines)
+	return errors.WithStack(os.WriteFile(filepath.Join(dir, ChecksumsFile), []byte(strings.Join(lines, "")), 0644))
+}
+
+// SignChecksums signs the ChecksumsFile of the artifact dir with the private key, and writes the SignatureFile. The
+// certificate of the key is also written into the artifact if it is set.
+func SignChecksums(dir, key, cert string) error {
+	signer, err := loadPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	checksums, err := os.ReadFile(filepath.Join(dir, ChecksumsFile))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if cert != "" {
+		data, err := os.ReadFile(cert)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		c, err := certs.ParseCertificatePEM(data)
+		if err != nil {
+			return errors.Wrapf(err, "load the certificate %s failed", cert)
+		}
+		if !certs.PublicKeyEqual(c.PublicKey, signer.Public()) {
+			return errors.Errorf("the certificate %s does not match the key %s", cert, key)
+		}
+		if err := os.WriteFile(filepath.Join(dir, CertificateFile), data, 0644); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	sig, err := certs.Sign(signer, checksums)
+	if err != nil {
+		return errors.Wrapf(err, "sign %s failed", ChecksumsFile)
+	}
+	return errors.WithStack(os.WriteFile(filepath.Join(dir, SignatureFile), []byte(base64.StdEncoding.EncodeToString(sig)), 0644))
+}
+
+// VerifyArtifact verifies every file of the artifact against its ChecksumsFile, and the signature of the ChecksumsFile
+// if the options are set. The error lists all the missing, corrupt and unexpected files.
+func VerifyArtifact(artifact string, opts VerifyOptions) error {
+	var checksums, sig, cert []byte
+	sums := make(map[string]string)
+	err := walkArtifact(artifact, func(hdr *tar.Header, r io.Reader) error {
+		name := path.Clean(hdr.Name)
+		var err error
+		switch name {
+		case ChecksumsFile:
+			checksums, err = io.ReadAll(r)
+		case SignatureFile:
+			sig, err = io.ReadAll(r)
+		case CertificateFile:
+			cert, err = io.ReadAll(r)
+		default:
+			sums[name], err = sha256sum(r)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "read %s of the artifact %s failed", name, artifact)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if checksums == nil {
+		return errors.WithMessagef(ErrNoChecksums, "verify the artifact %s failed", artifact)
+	}
+
+	if opts.Key != "" || opts.CA != "" {
+		if sig == nil {
+			return errors.Errorf("the artifact %s is not signed", artifact)
+		}
+		if err := verifySignature(checksums, sig, cert, opts); err != nil {
+			return errors.Wrapf(err, "verify the signature of the artifact %s failed", artifact)
+		}
+	}
+
+	want, err := parseChecksums(checksums)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s of the artifact %s failed", ChecksumsFile, artifact)
+	}
+	problems := make([]string, 0)
+	for name, sum := range want {
+		got, ok := sums[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s: missing", name))
+		case got != sum:
+			problems = append(problems, fmt.Sprintf("%s: sha256 %s, want %s", name, got, sum))
+		}
+	}
+	for name := range sums {
+		if _, ok := want[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: not in %s", name, ChecksumsFile))
+		}
+	}
+	if len(problems) != 0 {
+		sort.Strings(problems)
+		return errors.Errorf("the artifact %s is corrupt:\n  %s", artifact, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// parseChecksums parses the ChecksumsFile in the format of sha256sum.
+func parseChecksums(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			return nil, errors.Errorf("invalid line %q", line)
+		}
+		sums[fields[1]] = fields[0]
+	}
+	return sums, errors.WithStack(scanner.Err())
+}
+
+func isIntegrityFile(name string) bool {
+	return name == ChecksumsFile || name == SignatureFile || name == CertificateFile
+}
@@ -0,0 +1,181 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	coreutil "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+)
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newCert(t *testing.T, key, parentKey *ecdsa.PrivateKey, parent *x509.Certificate) *x509.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "kubekey"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestVerifyArtifact(t *testing.T) {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "artifact")
+	writeFiles(t, dir, map[string]string{
+		"images/index.json":          `{"manifests":[]}`,
+		"images/blobs/sha256/aaaa":   "layer a",
+		"kube/v1.26.0/amd64/kubeadm": "kubeadm v1.26.0",
+	})
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ca := newCert(t, caKey, nil, nil)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherCA := newCert(t, otherKey, nil, nil)
+	cert := newCert(t, key, caKey, ca)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pub, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	otherPub, _ := x509.MarshalPKIXPublicKey(&otherKey.PublicKey)
+	files := map[string]string{}
+	for name, data := range map[string][]byte{"key.pem": der, "pub.pem": pub, "other.pem": otherPub, "ca.pem": ca.Raw, "other-ca.pem": otherCA.Raw, "cert.pem": cert.Raw} {
+		files[name] = filepath.Join(tmp, name)
+		typ := map[string]string{"key.pem": "PRIVATE KEY", "pub.pem": "PUBLIC KEY", "other.pem": "PUBLIC KEY"}[name]
+		if typ == "" {
+			typ = "CERTIFICATE"
+		}
+		writePEM(t, files[name], typ, data)
+	}
+
+	unsigned := filepath.Join(tmp, "unsigned.tar.gz")
+	if err := coreutil.Tar(dir, unsigned, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyArtifact(unsigned, VerifyOptions{}); !errors.Is(err, ErrNoChecksums) {
+		t.Errorf("VerifyArtifact() without checksums = %v, want %v", err, ErrNoChecksums)
+	}
+
+	if err := WriteChecksums(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignChecksums(dir, files["key.pem"], files["cert.pem"]); err != nil {
+		t.Fatalf("SignChecksums() error = %v", err)
+	}
+	signed := filepath.Join(tmp, "signed.tar.gz")
+	if err := coreutil.Tar(dir, signed, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []VerifyOptions{{}, {Key: files["pub.pem"]}, {Key: files["cert.pem"]}, {CA: files["ca.pem"]}} {
+		if err := VerifyArtifact(signed, opts); err != nil {
+			t.Errorf("VerifyArtifact() with %+v error = %v", opts, err)
+		}
+	}
+	for _, opts := range []VerifyOptions{{Key: files["other.pem"]}, {CA: files["other-ca.pem"]}} {
+		if err := VerifyArtifact(signed, opts); err == nil {
+			t.Errorf("VerifyArtifact() with %+v returns no error", opts)
+		}
+	}
+
+	// a corrupt file, a missing file and an unexpected file
+	writeFiles(t, dir, map[string]string{"kube/v1.26.0/amd64/kubeadm": "tampered", "kube/v1.26.0/amd64/kubelet": "kubelet"})
+	if err := os.Remove(filepath.Join(dir, "images/blobs/sha256/aaaa")); err != nil {
+		t.Fatal(err)
+	}
+	tampered := filepath.Join(tmp, "tampered.tar.gz")
+	if err := coreutil.Tar(dir, tampered, dir); err != nil {
+		t.Fatal(err)
+	}
+	err := VerifyArtifact(tampered, VerifyOptions{Key: files["pub.pem"]})
+	if err == nil {
+		t.Fatal("VerifyArtifact() of the tampered artifact returns no error")
+	}
+	for _, entry := range []string{"images/blobs/sha256/aaaa: missing", "kube/v1.26.0/amd64/kubeadm: sha256", "kube/v1.26.0/amd64/kubelet: not in"} {
+		if !strings.Contains(err.Error(), entry) {
+			t.Errorf("VerifyArtifact() error = %v, want the entry %s", err, entry)
+		}
+	}
+}
+
+func TestLoadEncryptedKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+
+	enc := new(encryptedKey)
+	enc.KDF.Name, enc.Cipher.Name = "scrypt", "nacl/secretbox"
+	enc.KDF.Params.N, enc.KDF.Params.R, enc.KDF.Params.P = 1024, 8, 1
+	enc.KDF.Salt, enc.Cipher.Nonce = []byte("0123456789abcdef0123456789abcdef"), []byte("0123456789abcdef01234567")
+	derived, err := scrypt.Key([]byte("secret"), enc.KDF.Salt, 1024, 8, 1, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nonce [24]byte
+	var secret [32]byte
+	copy(nonce[:], enc.Cipher.Nonce)
+	copy(secret[:], derived)
+	enc.Ciphertext = secretbox.Seal(nil, der, &nonce, &secret)
+	data, _ := json.Marshal(enc)
+	path := filepath.Join(t.TempDir(), "cosign.key")
+	writePEM(t, path, "ENCRYPTED SIGSTORE PRIVATE KEY", data)
+
+	t.Setenv(PasswordEnv, "wrong")
+	if _, err := loadPrivateKey(path); err == nil {
+		t.Errorf("loadPrivateKey() with a wrong password returns no error")
+	}
+	t.Setenv(PasswordEnv, "secret")
+	signer, err := loadPrivateKey(path)
+	if err != nil {
+		t.Fatalf("loadPrivateKey() error = %v", err)
+	}
+	if !key.PublicKey.Equal(signer.Public()) {
+		t.Errorf("loadPrivateKey() returns another key")
+	}
+}
@@ -30,10 +30,17 @@ import (
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 
 	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/addons"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/artifact/templates"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/binaries"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/client/kubernetes"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/images"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubesphere"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/validation"
 )
 
 func CreateManifest(arg common.Argument, name string) error {
@@ -66,6 +73,11 @@ func CreateManifest(arg common.Argument, name string) error {
 			versionutil.MustParseSemantic(containerRuntime.Version).LessThan(versionutil.MustParseSemantic("1.6.2")) {
 			containerRuntime.Version = "1.6.2"
 		}
+		// CRI-O reports itself as "cri-o://1.26.1", the bundles are versioned as "v1.26.1"
+		if containerRuntime.Type == "cri-o" {
+			containerRuntime.Type = kubekeyv1alpha2.Crio
+			containerRuntime.Version = "v" + strings.TrimPrefix(containerRuntime.Version, "v")
+		}
 		containerSet.Add(containerRuntime)
 
 		archSet.Add(node.Status.NodeInfo.Architecture)
@@ -73,13 +85,7 @@ func CreateManifest(arg common.Argument, name string) error {
 			for _, name := range image.Names {
 				if !strings.Contains(name, "@sha256") {
 					if containerRuntime.Type == kubekeyv1alpha2.Docker {
-						arr := strings.Split(name, "/")
-						switch len(arr) {
-						case 1:
-							name = fmt.Sprintf("docker.io/library/%s", name)
-						case 2:
-							name = fmt.Sprintf("docker.io/%s", name)
-						}
+						name = fullImageName(name)
 					}
 					imagesSet.Add(name)
 				}
@@ -181,6 +187,160 @@ func CreateManifest(arg common.Argument, name string) error {
 	return nil
 }
 
+// CreateManifestFromConfig creates the manifest from the cluster configuration file, no running cluster is needed.
+// The images are the ones deployed by kk with the configuration, including the images of KubeSphere and the addons,
+// and the components are the versions downloaded by kk which are verified by version/components.json.
+func CreateManifestFromConfig(arg common.Argument, name, output string) error {
+	checkFileExists(output)
+	// no pipeline is run, the runtime is only created to initialize the work dir and the logger
+	_ = connector.NewBaseRuntime(name, nil, arg.Debug, false)
+
+	cluster, err := common.NewLoader(common.File, arg).Load()
+	if err != nil {
+		return err
+	}
+	// the hosts are not connected, so their SSH credentials are not checked
+	if err := validation.ToError(validation.ValidateCluster(&cluster.Spec)); err != nil {
+		return err
+	}
+	spec, roleGroups, err := cluster.Spec.SetDefaultClusterSpec()
+	if err != nil {
+		return err
+	}
+
+	options, err := configManifestOptions(spec, name, k8sNodes(roleGroups), arg.DeployLocalStorage)
+	if err != nil {
+		return err
+	}
+	manifestStr, err := templates.RenderManifest(options)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, []byte(manifestStr), 0644); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("write file %s failed", output))
+	}
+
+	fmt.Println("Generate KubeKey manifest file successfully")
+	return nil
+}
+
+// k8sNodes returns the number of the kubernetes nodes, a host which is both a master and a worker is counted once.
+func k8sNodes(roleGroups map[string][]*kubekeyv1alpha2.KubeHost) int {
+	names := make(map[string]struct{})
+	for _, role := range []string{kubekeyv1alpha2.Master, kubekeyv1alpha2.Worker} {
+		for _, host := range roleGroups[role] {
+			names[host.GetName()] = struct{}{}
+		}
+	}
+	return len(names)
+}
+
+// configManifestOptions returns the options of the manifest of the cluster which is set with the defaults.
+func configManifestOptions(spec *kubekeyv1alpha2.ClusterSpec, name string, k8sNodes int, deployLocalStorage *bool) (*templates.Options, error) {
+	archSet := mapset.NewThreadUnsafeSet()
+	for _, host := range spec.Hosts {
+		archSet.Add(host.Arch)
+	}
+	arches := make([]string, 0, archSet.Cardinality())
+	for _, v := range archSet.ToSlice() {
+		arches = append(arches, v.(string))
+	}
+	sort.Strings(arches)
+
+	components := kubekeyv1alpha2.Components{
+		Helm:      kubekeyv1alpha2.Helm{Version: kubekeyv1alpha2.DefaultHelmVersion},
+		CNI:       kubekeyv1alpha2.CNI{Version: kubekeyv1alpha2.DefaultCniVersion},
+		ETCD:      kubekeyv1alpha2.ETCD{Version: kubekeyv1alpha2.DefaultEtcdVersion},
+		Crictl:    kubekeyv1alpha2.Crictl{Version: kubekeyv1alpha2.DefaultCrictlVersion},
+		Calicoctl: kubekeyv1alpha2.Calicoctl{Version: kubekeyv1alpha2.DefaultCalicoVersion},
+	}
+	// the binaries of the components are looked up in version/components.json, the same as they are downloaded
+	versions := map[string]string{
+		"helm":      components.Helm.Version,
+		"kubecni":   components.CNI.Version,
+		"etcd":      components.ETCD.Version,
+		"crictl":    components.Crictl.Version,
+		"calicoctl": components.Calicoctl.Version,
+	}
+	switch spec.Kubernetes.Type {
+	case common.K3s, common.K8e:
+		versions[spec.Kubernetes.Type] = spec.Kubernetes.Version
+	default:
+		versions["kubeadm"] = spec.Kubernetes.Version
+	}
+	switch spec.Kubernetes.ContainerManager {
+	case common.Docker:
+		components.ContainerRuntimes = []kubekeyv1alpha2.ContainerRuntime{{Type: common.Docker, Version: kubekeyv1alpha2.DefaultDockerVersion}}
+		versions[common.Docker] = kubekeyv1alpha2.DefaultDockerVersion
+	case common.Containerd:
+		components.ContainerRuntimes = []kubekeyv1alpha2.ContainerRuntime{{Type: common.Containerd, Version: kubekeyv1alpha2.DefaultContainerdVersion}}
+		versions[common.Containerd] = kubekeyv1alpha2.DefaultContainerdVersion
+	case common.Crio:
+		// CRI-O is verified with the sha256sum file published along with the release instead of components.json
+		components.ContainerRuntimes = []kubekeyv1alpha2.ContainerRuntime{{Type: common.Crio, Version: binaries.CrioVersion(spec.Kubernetes.Version)}}
+	}
+	for _, arch := range arches {
+		for binary, version := range versions {
+			if _, ok := files.FileSha256[binary][arch][version]; !ok {
+				return nil, errors.Errorf("%s %s for %s is not found in components.json", binary, version, arch)
+			}
+		}
+	}
+
+	localStorage := spec.KubeSphere.Enabled
+	if deployLocalStorage != nil {
+		localStorage = *deployLocalStorage
+	}
+	imageSet := mapset.NewThreadUnsafeSet()
+	for _, image := range images.ClusterImages(spec, k8sNodes, localStorage) {
+		imageSet.Add(image)
+	}
+	if spec.KubeSphere.Enabled {
+		ksImages, err := kubesphere.Images(&spec.KubeSphere)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range ksImages {
+			imageSet.Add(fullImageName(image))
+		}
+	}
+	for i := range spec.Addons {
+		addonImages, err := addons.AddonImages(&spec.Addons[i], spec.Registry.Auths, spec.Kubernetes.Version)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range addonImages {
+			imageSet.Add(fullImageName(image))
+		}
+	}
+	imageArr := make([]string, 0, imageSet.Cardinality())
+	for _, v := range imageSet.ToSlice() {
+		imageArr = append(imageArr, v.(string))
+	}
+	sort.Strings(imageArr)
+
+	return &templates.Options{
+		Name:                    name,
+		Arches:                  arches,
+		KubernetesDistributions: []kubekeyv1alpha2.KubernetesDistribution{{Type: spec.Kubernetes.Type, Version: spec.Kubernetes.Version}},
+		Components:              components,
+		Images:                  imageArr,
+	}, nil
+}
+
+// fullImageName returns the name of the image with the registry, the short names are resolved to docker.io.
+func fullImageName(name string) string {
+	arr := strings.Split(name, "/")
+	switch {
+	case len(arr) == 1:
+		return fmt.Sprintf("docker.io/library/%s", name)
+	case len(arr) == 2 && !strings.ContainsAny(arr[0], ".:") && arr[0] != "localhost":
+		return fmt.Sprintf("docker.io/%s", name)
+	default:
+		return name
+	}
+}
+
 func checkFileExists(fileName string) {
 	if util.IsExist(fileName) {
 		reader := bufio.NewReader(os.Stdin)
@@ -0,0 +1,45 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"testing"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+)
+
+func TestK8sNodes(t *testing.T) {
+	cluster := &kubekeyv1alpha2.ClusterSpec{
+		Hosts: []kubekeyv1alpha2.HostCfg{
+			{Name: "node1", Address: "172.16.0.2"},
+			{Name: "node2", Address: "172.16.0.3"},
+			{Name: "node3", Address: "172.16.0.4"},
+		},
+		RoleGroups: map[string][]string{
+			"etcd":          {"node1"},
+			"control-plane": {"node1", "node2"},
+			"worker":        {"node1", "node2", "node3"},
+		},
+	}
+	_, roleGroups, err := cluster.SetDefaultClusterSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := k8sNodes(roleGroups); got != 3 {
+		t.Errorf("k8sNodes() = %d, want 3", got)
+	}
+}
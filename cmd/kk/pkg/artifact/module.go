@@ -67,6 +67,108 @@ func (a *ArchiveModule) Init() {
 	}
 }
 
+// ChecksumModule writes the checksums of the artifact dir and signs them before it is archived.
+type ChecksumModule struct {
+	common.ArtifactModule
+}
+
+func (c *ChecksumModule) Init() {
+	c.Name = "ArtifactChecksumModule"
+	c.Desc = "Generate the checksums of the dependencies"
+
+	checksums := &task.LocalTask{
+		Name:   "GenerateChecksums",
+		Desc:   "Generate the sha256 checksums of the dependencies",
+		Action: new(GenerateChecksums),
+	}
+
+	sign := &task.LocalTask{
+		Name:    "SignArtifact",
+		Desc:    "Sign the checksums of the dependencies",
+		Prepare: new(EnableSign),
+		Action:  new(SignArtifact),
+	}
+
+	c.Tasks = []task.Interface{
+		checksums,
+		sign,
+	}
+}
+
+// DeltaModule removes the files already present in the base artifact from the artifact dir before it is archived.
+type DeltaModule struct {
+	common.ArtifactModule
+	Skip bool
+}
+
+func (d *DeltaModule) IsSkip() bool {
+	return d.Skip
+}
+
+func (d *DeltaModule) Init() {
+	d.Name = "ArtifactDeltaModule"
+	d.Desc = "Exclude the dependencies of the base artifact"
+
+	extract := &task.LocalTask{
+		Name:   "ExtractDelta",
+		Desc:   "Remove the dependencies already present in the base artifact",
+		Action: new(ExtractDelta),
+	}
+
+	d.Tasks = []task.Interface{
+		extract,
+	}
+}
+
+// UnArchiveBaseModule unarchives the base artifact of a delta artifact, the delta is laid onto it by UnArchiveModule.
+type UnArchiveBaseModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (u *UnArchiveBaseModule) IsSkip() bool {
+	return u.Skip
+}
+
+func (u *UnArchiveBaseModule) Init() {
+	u.Name = "UnArchiveBaseArtifactModule"
+	u.Desc = "UnArchive the base of the KubeKey delta artifact"
+
+	md5Check := &task.LocalTask{
+		Name:   "CheckArtifactMd5",
+		Desc:   "Check the KubeKey artifact md5 value",
+		Action: new(Md5Check),
+	}
+
+	verify := &task.LocalTask{
+		Name:    "VerifyBaseArtifact",
+		Desc:    "Verify the checksums and the signature of the base KubeKey artifact",
+		Prepare: &Md5AreEqual{Not: true},
+		Action:  &VerifyIntegrity{Base: true},
+	}
+
+	checkBase := &task.LocalTask{
+		Name:    "CheckDeltaBase",
+		Desc:    "Check the delta artifact is exported from the base artifact",
+		Prepare: &Md5AreEqual{Not: true},
+		Action:  new(CheckDeltaBase),
+	}
+
+	unArchiveBase := &task.LocalTask{
+		Name:    "UnArchiveBaseArtifact",
+		Desc:    "UnArchive the base KubeKey artifact",
+		Prepare: &Md5AreEqual{Not: true},
+		Action:  new(UnArchiveBase),
+	}
+
+	u.Tasks = []task.Interface{
+		md5Check,
+		verify,
+		checkBase,
+		unArchiveBase,
+	}
+}
+
 type UnArchiveModule struct {
 	common.KubeModule
 	Skip bool
@@ -86,6 +188,13 @@ func (u *UnArchiveModule) Init() {
 		Action: new(Md5Check),
 	}
 
+	verify := &task.LocalTask{
+		Name:    "VerifyArtifact",
+		Desc:    "Verify the checksums and the signature of the KubeKey artifact",
+		Prepare: &Md5AreEqual{Not: true},
+		Action:  new(VerifyIntegrity),
+	}
+
 	unArchive := &task.LocalTask{
 		Name:    "UnArchiveArtifact",
 		Desc:    "UnArchive the KubeKey artifact",
@@ -102,6 +211,7 @@ func (u *UnArchiveModule) Init() {
 
 	u.Tasks = []task.Interface{
 		md5Check,
+		verify,
 		unArchive,
 		createMd5File,
 	}
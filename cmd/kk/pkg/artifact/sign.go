@@ -0,0 +1,171 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package artifact
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils/certs"
+)
+
+// PasswordEnv is the environment of the password of the encrypted private key, the same as cosign.
+const PasswordEnv = "COSIGN_PASSWORD"
+
+// encryptedKey is the encrypted private key generated by cosign generate-key-pair.
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// loadPrivateKey loads the PEM private key, which is either a PKCS#8, EC or RSA private key, or an encrypted private
+// key generated by cosign whose password is read from PasswordEnv.
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM block is found in the key %s", path)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "ENCRYPTED SIGSTORE PRIVATE KEY", "ENCRYPTED COSIGN PRIVATE KEY":
+		var der []byte
+		if der, err = decryptKey(block.Bytes, []byte(os.Getenv(PasswordEnv))); err == nil {
+			key, err = x509.ParsePKCS8PrivateKey(der)
+		}
+	default:
+		return nil, errors.Errorf("unsupported PEM type %s of the key %s", block.Type, path)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "load the key %s failed", path)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported key type %T of the key %s", key, path)
+	}
+	return signer, nil
+}
+
+// decryptKey decrypts the encrypted private key of cosign, it is encrypted by nacl/secretbox with a key derived by
+// scrypt from the password.
+func decryptKey(data, password []byte) ([]byte, error) {
+	enc := new(encryptedKey)
+	if err := json.Unmarshal(data, enc); err != nil {
+		return nil, errors.Wrap(err, "decode the encrypted key failed")
+	}
+	if enc.KDF.Name != "scrypt" || enc.Cipher.Name != "nacl/secretbox" {
+		return nil, errors.Errorf("unsupported encryption %s with %s", enc.Cipher.Name, enc.KDF.Name)
+	}
+	if len(enc.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid nonce of the encrypted key")
+	}
+	derived, err := scrypt.Key(password, enc.KDF.Salt, enc.KDF.Params.N, enc.KDF.Params.R, enc.KDF.Params.P, 32)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var nonce [24]byte
+	var secret [32]byte
+	copy(nonce[:], enc.Cipher.Nonce)
+	copy(secret[:], derived)
+	der, ok := secretbox.Open(nil, enc.Ciphertext, &nonce, &secret)
+	if !ok {
+		return nil, errors.Errorf("decrypt the key failed, the password should be set by %s", PasswordEnv)
+	}
+	return der, nil
+}
+
+// loadPublicKey loads the PEM public key, or the public key of the PEM certificate.
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	key, err := certs.ParsePublicKeyPEM(data)
+	return key, errors.Wrapf(err, "load the key %s failed", path)
+}
+
+// verifySignature verifies the base64 signature of the checksums with the key of the options, or with the certificate
+// in the artifact which is issued by the CA of the options.
+func verifySignature(checksums, sig, cert []byte, opts VerifyOptions) error {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return errors.Wrapf(err, "decode %s failed", SignatureFile)
+	}
+
+	var pub crypto.PublicKey
+	if opts.Key != "" {
+		if pub, err = loadPublicKey(opts.Key); err != nil {
+			return err
+		}
+	}
+	if opts.CA != "" {
+		if cert == nil {
+			return errors.Errorf("no certificate is found in %s", CertificateFile)
+		}
+		c, err := certs.ParseCertificatePEM(cert)
+		if err != nil {
+			return errors.Wrapf(err, "load %s failed", CertificateFile)
+		}
+		ca, err := os.ReadFile(opts.CA)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(ca) {
+			return errors.Errorf("no PEM certificate is found in the CA %s", opts.CA)
+		}
+		if _, err := c.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+			return errors.Wrapf(err, "verify %s with the CA %s failed", CertificateFile, opts.CA)
+		}
+		if pub != nil && !certs.PublicKeyEqual(pub, c.PublicKey) {
+			return errors.Errorf("%s does not match the key %s", CertificateFile, opts.Key)
+		}
+		pub = c.PublicKey
+	}
+	return certs.VerifySignature(pub, checksums, decoded)
+}
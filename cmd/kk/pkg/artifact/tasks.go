@@ -17,6 +17,7 @@
 package artifact
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -30,6 +31,7 @@ import (
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
 	coreutil "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
 )
 
 type DownloadISOFile struct {
@@ -44,6 +46,13 @@ func (d *DownloadISOFile) Execute(runtime connector.Runtime) error {
 
 		fileName := fmt.Sprintf("%s-%s-%s.iso", sys.Id, sys.Version, sys.Arch)
 		filePath := filepath.Join(runtime.GetWorkDir(), fileName)
+		if d.Manifest.Arg.DownloadCommand == nil {
+			if err := files.DefaultDownloader().Download(sys.Repository.Iso.Url, filePath, ""); err != nil {
+				return fmt.Errorf("Failed to download %s iso file: %w ", fileName, err)
+			}
+			d.Manifest.Spec.OperatingSystems[i].Repository.Iso.LocalPath = filePath
+			continue
+		}
 		getCmd := d.Manifest.Arg.DownloadCommand(filePath, sys.Repository.Iso.Url)
 
 		cmd := exec.Command("/bin/sh", "-c", getCmd)
@@ -116,11 +125,104 @@ func (a *ArchiveDependencies) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+type GenerateChecksums struct {
+	common.ArtifactAction
+}
+
+func (g *GenerateChecksums) Execute(runtime connector.Runtime) error {
+	return WriteChecksums(filepath.Join(runtime.GetWorkDir(), common.Artifact))
+}
+
+type SignArtifact struct {
+	common.ArtifactAction
+}
+
+func (s *SignArtifact) Execute(runtime connector.Runtime) error {
+	return SignChecksums(filepath.Join(runtime.GetWorkDir(), common.Artifact), s.Manifest.Arg.SignKey, s.Manifest.Arg.SignCert)
+}
+
+type ExtractDelta struct {
+	common.ArtifactAction
+}
+
+func (e *ExtractDelta) Execute(runtime connector.Runtime) error {
+	dir := filepath.Join(runtime.GetWorkDir(), common.Artifact)
+	removed, err := RemoveBaseFiles(dir, e.Manifest.Arg.Base)
+	if err != nil {
+		return err
+	}
+	logger.Log.Infof("%d files are already present in the base artifact %s", removed, e.Manifest.Arg.Base)
+
+	baseMd5, err := coreutil.FileMD5(e.Manifest.Arg.Base)
+	if err != nil {
+		return errors.Wrapf(err, "get the md5 of the base artifact %s failed", e.Manifest.Arg.Base)
+	}
+	data, err := json.Marshal(&Delta{Base: baseMd5})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := coreutil.WriteFile(filepath.Join(dir, DeltaFile), data); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "write %s failed", DeltaFile)
+	}
+	return nil
+}
+
+type CheckDeltaBase struct {
+	common.KubeAction
+}
+
+func (c *CheckDeltaBase) Execute(_ connector.Runtime) error {
+	return CheckDelta(c.KubeConf.Arg.Artifact, c.KubeConf.Arg.BaseArtifact)
+}
+
+type UnArchiveBase struct {
+	common.KubeAction
+}
+
+func (u *UnArchiveBase) Execute(runtime connector.Runtime) error {
+	if err := coreutil.Untar(u.KubeConf.Arg.BaseArtifact, runtime.GetWorkDir()); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "unArchive %s failed", u.KubeConf.Arg.BaseArtifact)
+	}
+	return nil
+}
+
+type VerifyIntegrity struct {
+	common.KubeAction
+	Base bool
+}
+
+func (v *VerifyIntegrity) Execute(_ connector.Runtime) error {
+	artifact := v.KubeConf.Arg.Artifact
+	if v.Base {
+		artifact = v.KubeConf.Arg.BaseArtifact
+	}
+	opts := VerifyOptions{Key: v.KubeConf.Arg.ArtifactKey, CA: v.KubeConf.Arg.ArtifactCA}
+	err := VerifyArtifact(artifact, opts)
+	if errors.Is(err, ErrNoChecksums) && opts.Key == "" && opts.CA == "" {
+		logger.Log.Warningf("The artifact %s has no checksums, it is exported by an earlier version of KubeKey and can not be verified", artifact)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// the annotations of the images in the artifact are only trusted if they are bound to the verified signature
+	if !v.Base && (opts.Key != "" || opts.CA != "") {
+		v.PipelineCache.Set(common.ArtifactSigned, true)
+	}
+	return nil
+}
+
 type UnArchive struct {
 	common.KubeAction
 }
 
 func (u *UnArchive) Execute(runtime connector.Runtime) error {
+	// the base is checked before it is unarchived
+	if u.KubeConf.Arg.BaseArtifact == "" {
+		if err := CheckDelta(u.KubeConf.Arg.Artifact, ""); err != nil {
+			return err
+		}
+	}
 	if err := coreutil.Untar(u.KubeConf.Arg.Artifact, runtime.GetWorkDir()); err != nil {
 		return errors.Wrapf(errors.WithStack(err), "unArchive %s failed", u.KubeConf.Arg.Artifact)
 	}
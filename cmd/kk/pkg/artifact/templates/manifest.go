@@ -68,6 +68,10 @@ spec:
     {{- end}}
     crictl: 
       version: {{ .Options.Components.Crictl.Version }}
+    {{- if .Options.Components.Calicoctl.Version }}
+    calicoctl:
+      version: {{ .Options.Components.Calicoctl.Version }}
+    {{- end }}
     ## 
     # docker-registry:
     #   version: "2"
@@ -0,0 +1,86 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package binaries
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
+)
+
+// downloadBinaries downloads the binaries in parallel, the binaries which exist and pass the SHA256 check are skipped.
+// The download mirrors are tried in order before the default download source.
+func downloadBinaries(arch string, binaries []*files.KubeBinary, mirrors []kubekeyapiv1alpha2.DownloadMirror) error {
+	for _, binary := range binaries {
+		if err := binary.ApplyMirrors(mirrors); err != nil {
+			return err
+		}
+		if err := binary.CreateBaseDir(); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, binary := range binaries {
+		wg.Add(1)
+		go func(binary *files.KubeBinary) {
+			defer wg.Done()
+			if err := downloadBinary(arch, binary); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(binary)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func downloadBinary(arch string, binary *files.KubeBinary) error {
+	logger.Log.Messagef(common.LocalHost, "downloading %s %s %s ...", arch, binary.ID, binary.Version)
+
+	if util.IsExist(binary.Path()) {
+		// download it again if it's incorrect
+		if err := binary.SHA256Check(); err != nil {
+			_ = os.Remove(binary.Path())
+		} else {
+			logger.Log.Messagef(common.LocalHost, "%s exists", binary.ID)
+			return nil
+		}
+	}
+
+	if err := binary.Download(); err != nil {
+		return fmt.Errorf("Failed to download %s binary: %s error: %w ", binary.ID, binary.GetCmd(), err)
+	}
+	return nil
+}
@@ -17,16 +17,9 @@
 package binaries
 
 import (
-	"fmt"
-	"os/exec"
-
-	"github.com/pkg/errors"
-
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/cache"
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
-	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
 )
 
@@ -41,26 +34,10 @@ func K8eFilesDownloadHTTP(kubeConf *common.KubeConf, path, version, arch string,
 	binaries := []*files.KubeBinary{k8e, helm, kubecni, etcd}
 	binariesMap := make(map[string]*files.KubeBinary)
 	for _, binary := range binaries {
-		if err := binary.CreateBaseDir(); err != nil {
-			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
-		}
-
-		logger.Log.Messagef(common.LocalHost, "downloading %s %s %s ...", arch, binary.ID, binary.Version)
-
 		binariesMap[binary.ID] = binary
-		if util.IsExist(binary.Path()) {
-			// download it again if it's incorrect
-			p := binary.Path()
-			if err := binary.SHA256Check(); err != nil {
-				_ = exec.Command("/bin/sh", "-c", fmt.Sprintf("rm -f %s", p)).Run()
-			} else {
-				continue
-			}
-		}
-
-		if err := binary.Download(); err != nil {
-			return fmt.Errorf("Failed to download %s binary: %s error: %w ", binary.ID, binary.GetCmd(), err)
-		}
+	}
+	if err := downloadBinaries(arch, binaries, kubeConf.Cluster.DownloadMirrors); err != nil {
+		return err
 	}
 
 	pipelineCache.Set(common.KubeBinaries+"-"+arch, binariesMap)
@@ -98,25 +75,8 @@ func K8eArtifactBinariesDownload(manifest *common.ArtifactManifest, path, arch,
 		binaries = append(binaries, crictl)
 	}
 
-	for _, binary := range binaries {
-		if err := binary.CreateBaseDir(); err != nil {
-			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
-		}
-
-		logger.Log.Messagef(common.LocalHost, "downloading %s %s %s ...", arch, binary.ID, binary.Version)
-
-		if util.IsExist(binary.Path()) {
-			// download it again if it's incorrect
-			if err := binary.SHA256Check(); err != nil {
-				_ = exec.Command("/bin/sh", "-c", fmt.Sprintf("rm -f %s", binary.Path())).Run()
-			} else {
-				continue
-			}
-		}
-
-		if err := binary.Download(); err != nil {
-			return fmt.Errorf("Failed to download %s binary: %s error: %w ", binary.ID, binary.GetCmd(), err)
-		}
+	if err := downloadBinaries(arch, binaries, m.DownloadMirrors); err != nil {
+		return err
 	}
 
 	return nil
@@ -21,6 +21,7 @@ import (
 	"os/exec"
 
 	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
 
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
@@ -43,6 +44,7 @@ func K8sFilesDownloadHTTP(kubeConf *common.KubeConf, path, version, arch string,
 	crictl := files.NewKubeBinary("crictl", arch, kubekeyapiv1alpha2.DefaultCrictlVersion, path, kubeConf.Arg.DownloadCommand)
 	containerd := files.NewKubeBinary("containerd", arch, kubekeyapiv1alpha2.DefaultContainerdVersion, path, kubeConf.Arg.DownloadCommand)
 	runc := files.NewKubeBinary("runc", arch, kubekeyapiv1alpha2.DefaultRuncVersion, path, kubeConf.Arg.DownloadCommand)
+	crio := files.NewKubeBinary("crio", arch, CrioVersion(version), path, kubeConf.Arg.DownloadCommand)
 	calicoctl := files.NewKubeBinary("calicoctl", arch, kubekeyapiv1alpha2.DefaultCalicoVersion, path, kubeConf.Arg.DownloadCommand)
 
 	binaries := []*files.KubeBinary{kubeadm, kubelet, kubectl, helm, kubecni, crictl, etcd}
@@ -51,6 +53,8 @@ func K8sFilesDownloadHTTP(kubeConf *common.KubeConf, path, version, arch string,
 		binaries = append(binaries, docker)
 	} else if kubeConf.Cluster.Kubernetes.ContainerManager == kubekeyapiv1alpha2.Containerd {
 		binaries = append(binaries, containerd, runc)
+	} else if kubeConf.Cluster.Kubernetes.ContainerManager == kubekeyapiv1alpha2.Crio {
+		binaries = append(binaries, crio)
 	}
 
 	if kubeConf.Cluster.Network.Plugin == "calico" {
@@ -59,35 +63,22 @@ func K8sFilesDownloadHTTP(kubeConf *common.KubeConf, path, version, arch string,
 
 	binariesMap := make(map[string]*files.KubeBinary)
 	for _, binary := range binaries {
-		if err := binary.CreateBaseDir(); err != nil {
-			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
-		}
-
-		logger.Log.Messagef(common.LocalHost, "downloading %s %s %s ...", arch, binary.ID, binary.Version)
-
 		binariesMap[binary.ID] = binary
-		if util.IsExist(binary.Path()) {
-			// download it again if it's incorrect
-			if err := binary.SHA256Check(); err != nil {
-				p := binary.Path()
-				_ = exec.Command("/bin/sh", "-c", fmt.Sprintf("rm -f %s", p)).Run()
-			} else {
-				logger.Log.Messagef(common.LocalHost, "%s exists", binary.ID)
-				continue
-			}
-		}
-
-		if err := binary.Download(); err != nil {
-			return fmt.Errorf("Failed to download %s binary: %s error: %w ", binary.ID, binary.GetCmd(), err)
-		}
+	}
+	if err := downloadBinaries(arch, binaries, kubeConf.Cluster.DownloadMirrors); err != nil {
+		return err
 	}
 
 	if kubeConf.Cluster.KubeSphere.Version == "v2.1.1" {
 		logger.Log.Infoln(fmt.Sprintf("Downloading %s ...", "helm2"))
 		if util.IsExist(fmt.Sprintf("%s/helm2", helm.BaseDir)) == false {
-			cmd := kubeConf.Arg.DownloadCommand(fmt.Sprintf("%s/helm2", helm.BaseDir),
-				fmt.Sprintf("https://kubernetes-helm.pek3b.qingstor.com/linux-%s/%s/helm", helm.Arch, "v2.16.9"))
-			if output, err := exec.Command("/bin/sh", "-c", cmd).CombinedOutput(); err != nil {
+			path := fmt.Sprintf("%s/helm2", helm.BaseDir)
+			url := fmt.Sprintf("https://kubernetes-helm.pek3b.qingstor.com/linux-%s/%s/helm", helm.Arch, "v2.16.9")
+			if kubeConf.Arg.DownloadCommand == nil {
+				if err := files.DefaultDownloader().Download(url, path, ""); err != nil {
+					return errors.Wrap(err, "Failed to download helm2 binary")
+				}
+			} else if output, err := exec.Command("/bin/sh", "-c", kubeConf.Arg.DownloadCommand(path, url)).CombinedOutput(); err != nil {
 				fmt.Println(string(output))
 				return errors.Wrap(err, "Failed to download helm2 binary")
 			}
@@ -130,25 +121,8 @@ func KubernetesArtifactBinariesDownload(manifest *common.ArtifactManifest, path,
 		binaries = append(binaries, crictl)
 	}
 
-	for _, binary := range binaries {
-		if err := binary.CreateBaseDir(); err != nil {
-			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
-		}
-
-		logger.Log.Messagef(common.LocalHost, "downloading %s %s %s ...", arch, binary.ID, binary.Version)
-
-		if util.IsExist(binary.Path()) {
-			// download it again if it's incorrect
-			if err := binary.SHA256Check(); err != nil {
-				_ = exec.Command("/bin/sh", "-c", fmt.Sprintf("rm -f %s", binary.Path())).Run()
-			} else {
-				continue
-			}
-		}
-
-		if err := binary.Download(); err != nil {
-			return fmt.Errorf("Failed to download %s binary: %s error: %w ", binary.ID, binary.GetCmd(), err)
-		}
+	if err := downloadBinaries(arch, binaries, m.DownloadMirrors); err != nil {
+		return err
 	}
 
 	return nil
@@ -167,33 +141,30 @@ func CriDownloadHTTP(kubeConf *common.KubeConf, path, arch string, pipelineCache
 		runc := files.NewKubeBinary("runc", arch, kubekeyapiv1alpha2.DefaultRuncVersion, path, kubeConf.Arg.DownloadCommand)
 		crictl := files.NewKubeBinary("crictl", arch, kubekeyapiv1alpha2.DefaultCrictlVersion, path, kubeConf.Arg.DownloadCommand)
 		binaries = append(binaries, containerd, runc, crictl)
+	case common.Crio:
+		crio := files.NewKubeBinary("crio", arch, CrioVersion(kubeConf.Cluster.Kubernetes.Version), path, kubeConf.Arg.DownloadCommand)
+		crictl := files.NewKubeBinary("crictl", arch, kubekeyapiv1alpha2.DefaultCrictlVersion, path, kubeConf.Arg.DownloadCommand)
+		binaries = append(binaries, crio, crictl)
 	default:
 	}
 	binariesMap := make(map[string]*files.KubeBinary)
 	for _, binary := range binaries {
-		if err := binary.CreateBaseDir(); err != nil {
-			return errors.Wrapf(errors.WithStack(err), "create file %s base dir failed", binary.FileName)
-		}
-
-		logger.Log.Messagef(common.LocalHost, "downloading %s %s %s ...", arch, binary.ID, binary.Version)
-
 		binariesMap[binary.ID] = binary
-		if util.IsExist(binary.Path()) {
-			// download it again if it's incorrect
-			if err := binary.SHA256Check(); err != nil {
-				p := binary.Path()
-				_ = exec.Command("/bin/sh", "-c", fmt.Sprintf("rm -f %s", p)).Run()
-			} else {
-				logger.Log.Messagef(common.LocalHost, "%s exists", binary.ID)
-				continue
-			}
-		}
-
-		if err := binary.Download(); err != nil {
-			return fmt.Errorf("Failed to download %s binary: %s error: %w ", binary.ID, binary.GetCmd(), err)
-		}
+	}
+	if err := downloadBinaries(arch, binaries, kubeConf.Cluster.DownloadMirrors); err != nil {
+		return err
 	}
 
 	pipelineCache.Set(common.KubeBinaries+"-"+arch, binariesMap)
 	return nil
 }
+
+// CrioVersion returns the version of CRI-O for the Kubernetes version. The minor versions of CRI-O follow the
+// Kubernetes releases, the first patch release of the minor version is used.
+func CrioVersion(kubeVersion string) string {
+	v, err := versionutil.ParseGeneric(kubeVersion)
+	if err != nil {
+		v = versionutil.MustParseGeneric(kubekeyapiv1alpha2.DefaultKubeVersion)
+	}
+	return fmt.Sprintf("v%d.%d.0", v.Major(), v.Minor())
+}
@@ -157,3 +157,27 @@ func (d *MigrateCriConfirmModule) Init() {
 	}
 
 }
+
+type RestoreETCDConfirmModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *RestoreETCDConfirmModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RestoreETCDConfirmModule) Init() {
+	r.Name = "RestoreETCDConfirmModule"
+	r.Desc = "Display restore confirmation form"
+
+	display := &task.LocalTask{
+		Name:   "ConfirmForm",
+		Desc:   "Display confirmation form",
+		Action: new(RestoreETCDConfirm),
+	}
+
+	r.Tasks = []task.Interface{
+		display,
+	}
+}
@@ -60,6 +60,7 @@ type InstallationConfirm struct {
 }
 
 func (i *InstallationConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	var (
 		results  []PreCheckResults
 		stopFlag bool
@@ -79,7 +80,7 @@ func (i *InstallationConfirm) Execute(runtime connector.Runtime) error {
 		_ = mapstructure.Decode(pre[node], &result)
 		results = append(results, result)
 	}
-	table.OutputA(results)
+	fmt.Fprintln(out, table.AsciiTable(results))
 	reader := bufio.NewReader(os.Stdin)
 
 	if i.KubeConf.Arg.Artifact == "" {
@@ -101,22 +102,22 @@ func (i *InstallationConfirm) Execute(runtime connector.Runtime) error {
 		}
 	}
 
-	fmt.Println("")
-	fmt.Println("This is a simple check of your environment.")
-	fmt.Println("Before installation, ensure that your machines meet all requirements specified at")
-	fmt.Println("https://github.com/kubesphere/kubekey#requirements-and-recommendations")
-	fmt.Println("")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "This is a simple check of your environment.")
+	fmt.Fprintln(out, "Before installation, ensure that your machines meet all requirements specified at")
+	fmt.Fprintln(out, "https://github.com/kubesphere/kubekey#requirements-and-recommendations")
+	fmt.Fprintln(out, "")
 
 	if k8sVersion, err := versionutil.ParseGeneric(i.KubeConf.Cluster.Kubernetes.Version); err == nil {
 		if k8sVersion.AtLeast(versionutil.MustParseSemantic("v1.24.0")) && i.KubeConf.Cluster.Kubernetes.ContainerManager == common.Docker {
-			fmt.Println("[Notice]")
-			fmt.Println("Incorrect runtime. Please specify a container runtime other than Docker to install Kubernetes v1.24 or later.")
-			fmt.Println("You can set \"spec.kubernetes.containerManager\" in the configuration file to \"containerd\" or add \"--container-manager containerd\" to the \"./kk create cluster\" command.")
-			fmt.Println("For more information, see:")
-			fmt.Println("https://github.com/kubesphere/kubekey/blob/master/docs/commands/kk-create-cluster.md")
-			fmt.Println("https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
-			fmt.Println("https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
-			fmt.Println("")
+			fmt.Fprintln(out, "[Notice]")
+			fmt.Fprintln(out, "Incorrect runtime. Please specify a container runtime other than Docker to install Kubernetes v1.24 or later.")
+			fmt.Fprintln(out, "You can set \"spec.kubernetes.containerManager\" in the configuration file to \"containerd\" or add \"--container-manager containerd\" to the \"./kk create cluster\" command.")
+			fmt.Fprintln(out, "For more information, see:")
+			fmt.Fprintln(out, "https://github.com/kubesphere/kubekey/blob/master/docs/commands/kk-create-cluster.md")
+			fmt.Fprintln(out, "https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
+			fmt.Fprintln(out, "https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
+			fmt.Fprintln(out, "")
 			stopFlag = true
 		}
 	}
@@ -131,7 +132,7 @@ func (i *InstallationConfirm) Execute(runtime connector.Runtime) error {
 
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Continue this installation? [yes/no]: ")
+		fmt.Fprintf(out, "Continue this installation? [yes/no]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			logger.Log.Fatal(err)
@@ -156,11 +157,12 @@ type DeleteConfirm struct {
 }
 
 func (d *DeleteConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	reader := bufio.NewReader(os.Stdin)
 
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Are you sure to delete this %s? [yes/no]: ", d.Content)
+		fmt.Fprintf(out, "Are you sure to delete this %s? [yes/no]: ", d.Content)
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return err
@@ -185,6 +187,7 @@ type UpgradeConfirm struct {
 }
 
 func (u *UpgradeConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	pre := make([]map[string]string, len(runtime.GetAllHosts()), len(runtime.GetAllHosts()))
 	for i, host := range runtime.GetAllHosts() {
 		if v, ok := host.GetCache().Get(common.NodePreCheck); ok {
@@ -200,8 +203,8 @@ func (u *UpgradeConfirm) Execute(runtime connector.Runtime) error {
 		_ = mapstructure.Decode(pre[i], &result)
 		results[i] = result
 	}
-	table.OutputA(results)
-	fmt.Println()
+	fmt.Fprintln(out, table.AsciiTable(results))
+	fmt.Fprintln(out)
 
 	warningFlag := false
 	cmp, err := versionutil.MustParseSemantic(u.KubeConf.Cluster.Kubernetes.Version).Compare("v1.19.0")
@@ -223,13 +226,13 @@ func (u *UpgradeConfirm) Execute(runtime connector.Runtime) error {
 			}
 		}
 		if warningFlag {
-			fmt.Println(`
+			fmt.Fprintln(out, `
 Warning:
 
   An old Docker version may cause the failure of upgrade. It is recommended that you upgrade Docker to 20.10+ beforehand.
 
   Issue: https://github.com/kubernetes/kubernetes/issues/101056`)
-			fmt.Print("\n")
+			fmt.Fprint(out, "\n")
 		}
 	}
 
@@ -237,37 +240,37 @@ Warning:
 	if !ok {
 		return errors.New("get cluster nodes status failed by pipeline cache")
 	}
-	fmt.Println("Cluster nodes status:")
-	fmt.Println(nodeStats + "\n")
+	fmt.Fprintln(out, "Cluster nodes status:")
+	fmt.Fprintln(out, nodeStats+"\n")
 
-	fmt.Println("Upgrade Confirmation:")
+	fmt.Fprintln(out, "Upgrade Confirmation:")
 	currentK8sVersion, ok := u.PipelineCache.GetMustString(common.K8sVersion)
 	if !ok {
 		return errors.New("get current Kubernetes version failed by pipeline cache")
 	}
-	fmt.Printf("kubernetes version: %s to %s\n", currentK8sVersion, u.KubeConf.Cluster.Kubernetes.Version)
+	fmt.Fprintf(out, "kubernetes version: %s to %s\n", currentK8sVersion, u.KubeConf.Cluster.Kubernetes.Version)
 
 	if u.KubeConf.Cluster.KubeSphere.Enabled {
 		currentKsVersion, ok := u.PipelineCache.GetMustString(common.KubeSphereVersion)
 		if !ok {
 			return errors.New("get current KubeSphere version failed by pipeline cache")
 		}
-		fmt.Printf("kubesphere version: %s to %s\n", currentKsVersion, u.KubeConf.Cluster.KubeSphere.Version)
+		fmt.Fprintf(out, "kubesphere version: %s to %s\n", currentKsVersion, u.KubeConf.Cluster.KubeSphere.Version)
 	}
-	fmt.Println()
+	fmt.Fprintln(out)
 
 	if k8sVersion, err := versionutil.ParseGeneric(u.KubeConf.Cluster.Kubernetes.Version); err == nil {
 		if cri, ok := u.PipelineCache.GetMustString(common.ClusterNodeCRIRuntimes); ok {
 			k8sV124 := versionutil.MustParseSemantic("v1.24.0")
 			if k8sVersion.AtLeast(k8sV124) && versionutil.MustParseSemantic(currentK8sVersion).LessThan(k8sV124) && strings.Contains(cri, "docker") {
-				fmt.Println("[Notice]")
-				fmt.Println("Pre-upgrade check failed. The container runtime of the current cluster is Docker.")
-				fmt.Println("Kubernetes v1.24 and later no longer support dockershim and Docker.")
-				fmt.Println("Make sure you have completed the migration from Docker to other container runtimes that are compatible with the Kubernetes CRI.")
-				fmt.Println("For more information, see:")
-				fmt.Println("https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
-				fmt.Println("https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
-				fmt.Println("")
+				fmt.Fprintln(out, "[Notice]")
+				fmt.Fprintln(out, "Pre-upgrade check failed. The container runtime of the current cluster is Docker.")
+				fmt.Fprintln(out, "Kubernetes v1.24 and later no longer support dockershim and Docker.")
+				fmt.Fprintln(out, "Make sure you have completed the migration from Docker to other container runtimes that are compatible with the Kubernetes CRI.")
+				fmt.Fprintln(out, "For more information, see:")
+				fmt.Fprintln(out, "https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
+				fmt.Fprintln(out, "https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
+				fmt.Fprintln(out, "")
 			}
 		}
 	}
@@ -275,7 +278,7 @@ Warning:
 	reader := bufio.NewReader(os.Stdin)
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Continue upgrading cluster? [yes/no]: ")
+		fmt.Fprintf(out, "Continue upgrading cluster? [yes/no]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return err
@@ -320,6 +323,7 @@ type CheckFile struct {
 }
 
 func (c *CheckFile) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	if util.IsExist(c.FileName) {
 		reader := bufio.NewReader(os.Stdin)
 		stop := false
@@ -327,7 +331,7 @@ func (c *CheckFile) Execute(runtime connector.Runtime) error {
 			if stop {
 				break
 			}
-			fmt.Printf("%s already exists. Are you sure you want to overwrite this file? [yes/no]: ", c.FileName)
+			fmt.Fprintf(out, "%s already exists. Are you sure you want to overwrite this file? [yes/no]: ", c.FileName)
 			input, _ := reader.ReadString('\n')
 			input = strings.ToLower(strings.TrimSpace(input))
 
@@ -349,11 +353,44 @@ type MigrateCri struct {
 }
 
 func (d *MigrateCri) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	reader := bufio.NewReader(os.Stdin)
 
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Are you sure to Migrate Cri? [yes/no]: ")
+		fmt.Fprintf(out, "Are you sure to Migrate Cri? [yes/no]: ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		input = strings.ToLower(strings.TrimSpace(input))
+
+		switch strings.ToLower(input) {
+		case "yes", "y":
+			confirmOK = true
+		case "no", "n":
+			os.Exit(0)
+		default:
+			continue
+		}
+	}
+
+	return nil
+}
+
+type RestoreETCDConfirm struct {
+	common.KubeAction
+}
+
+func (r *RestoreETCDConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Fprintf(out, "The etcd data of the cluster will be replaced by the snapshot %s, "+
+		"and kube-apiserver will be stopped during the restoration.\n", r.KubeConf.Arg.Snapshot)
+	confirmOK := false
+	for !confirmOK {
+		fmt.Fprintf(out, "Are you sure to restore etcd? [yes/no]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return err
@@ -0,0 +1,155 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package customscripts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
+)
+
+// HookModule runs a hook on the nodes selected by its roles and labels.
+type HookModule struct {
+	common.KubeModule
+	Index int
+	Hook  kubekeyapiv1alpha2.Hook
+	// module is the module which the hook is attached to.
+	module module.Module
+}
+
+// IsSkip is evaluated when the hook is reached by the pipeline, a hook is skipped together with its module.
+func (h *HookModule) IsSkip() bool {
+	return h.module.IsSkip()
+}
+
+// Key identifies the hook by its name and the module which it is attached to, instead of its position in the hooks.
+func (h *HookModule) Key() string {
+	position := "after"
+	if h.Hook.Before != "" {
+		position = "before"
+	}
+	return fmt.Sprintf("%T(%s %s %T)", h, h.Hook.Name, position, h.module)
+}
+
+func (h *HookModule) Init() {
+	h.Name = "HookModule"
+	h.Desc = fmt.Sprintf("Exec hook %s", h.Hook.Name)
+
+	script := kubekeyapiv1alpha2.CustomScripts{
+		Name:      h.Hook.Name,
+		Bash:      h.Hook.Bash,
+		Materials: h.Hook.Materials,
+	}
+	hook := &task.RemoteTask{
+		Name:        fmt.Sprintf("Hook:%s", h.Hook.Name),
+		Desc:        fmt.Sprintf("Exec hook %s", h.Hook.Name),
+		Hosts:       hookHosts(h.Runtime.GetAllHosts(), h.KubeConf.Cluster.Hosts, h.Hook),
+		Action:      &CustomScriptTask{taskDir: fmt.Sprintf("hook-%d-script", h.Index), script: script},
+		Parallel:    true,
+		Retry:       1,
+		Timeout:     time.Duration(h.Hook.Timeout) * time.Second,
+		IgnoreError: h.Hook.FailurePolicy == kubekeyapiv1alpha2.HookFailurePolicyIgnore,
+	}
+
+	h.Tasks = []task.Interface{
+		hook,
+	}
+}
+
+// AttachHooks inserts the modules of the hooks before or after the modules which they are attached to. A hook is
+// skipped together with its module.
+func AttachHooks(modules []module.Module, hooks []kubekeyapiv1alpha2.Hook) []module.Module {
+	if len(hooks) == 0 {
+		return modules
+	}
+
+	attached := make([]module.Module, 0, len(modules))
+	for _, m := range modules {
+		for i, hook := range hooks {
+			if moduleMatches(m, hook.Before) {
+				attached = append(attached, newHookModule(m, i, hook))
+			}
+		}
+		attached = append(attached, m)
+		for i, hook := range hooks {
+			if moduleMatches(m, hook.After) {
+				attached = append(attached, newHookModule(m, i, hook))
+			}
+		}
+	}
+	return attached
+}
+
+func newHookModule(m module.Module, index int, hook kubekeyapiv1alpha2.Hook) *HookModule {
+	return &HookModule{Index: index, Hook: hook, module: m}
+}
+
+// moduleMatches reports whether the name refers to the module, such as JoinNodesModule or kubernetes.JoinNodesModule
+// for *kubernetes.JoinNodesModule.
+func moduleMatches(m module.Module, name string) bool {
+	if name == "" {
+		return false
+	}
+	if _, ok := m.(*HookModule); ok {
+		return false
+	}
+	kind := strings.TrimPrefix(fmt.Sprintf("%T", m), "*")
+	return kind == name || kind[strings.LastIndex(kind, ".")+1:] == name
+}
+
+// hookHosts returns the hosts which have any of the roles and all the labels of the hook.
+func hookHosts(hosts []connector.Host, cfgs []kubekeyapiv1alpha2.HostCfg, hook kubekeyapiv1alpha2.Hook) []connector.Host {
+	labels := make(map[string]map[string]string, len(cfgs))
+	for _, cfg := range cfgs {
+		labels[cfg.Name] = cfg.Labels
+	}
+
+	selected := make([]connector.Host, 0, len(hosts))
+	for _, host := range hosts {
+		if hasAnyRole(host, hook.Roles) && hasLabels(labels[host.GetName()], hook.Labels) {
+			selected = append(selected, host)
+		}
+	}
+	return selected
+}
+
+func hasAnyRole(host connector.Host, roles []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if host.IsRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package customscripts
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+)
+
+type InstallContainerModule struct {
+	common.KubeModule
+}
+
+type JoinNodesModule struct {
+	common.KubeModule
+}
+
+func TestAttachHooks(t *testing.T) {
+	container := &InstallContainerModule{}
+	join := &JoinNodesModule{}
+	modules := []module.Module{container, join}
+	hooks := []kubekeyapiv1alpha2.Hook{
+		{Name: "after-container", After: "InstallContainerModule"},
+		{Name: "before-join", Before: "customscripts.JoinNodesModule"},
+		{Name: "unknown", After: "UnknownModule"},
+	}
+
+	attached := AttachHooks(modules, hooks)
+	// the skip of a module is evaluated when the hook is run
+	join.Skip = true
+
+	var got []string
+	for _, m := range attached {
+		if h, ok := m.(*HookModule); ok {
+			got = append(got, fmt.Sprintf("hook:%s:%v", h.Hook.Name, h.IsSkip()))
+			continue
+		}
+		got = append(got, fmt.Sprintf("%T", m))
+	}
+	want := []string{
+		"*customscripts.InstallContainerModule",
+		"hook:after-container:false",
+		"hook:before-join:true",
+		"*customscripts.JoinNodesModule",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("modules = %v, want %v", got, want)
+	}
+
+	if key, want := attached[2].(*HookModule).Key(), "*customscripts.HookModule(before-join before *customscripts.JoinNodesModule)"; key != want {
+		t.Errorf("Key() = %s, want %s", key, want)
+	}
+}
+
+func TestHookHosts(t *testing.T) {
+	var hosts []connector.Host
+	for name, role := range map[string]string{"node1": common.Master, "node2": common.Worker, "node3": common.Worker} {
+		host := connector.NewHost()
+		host.Name = name
+		host.SetRole(role)
+		hosts = append(hosts, host)
+	}
+	cfgs := []kubekeyapiv1alpha2.HostCfg{
+		{Name: "node2", Labels: map[string]string{"disk": "ssd"}},
+		{Name: "node3", Labels: map[string]string{"disk": "hdd"}},
+	}
+
+	tests := []struct {
+		hook kubekeyapiv1alpha2.Hook
+		want []string
+	}{
+		{hook: kubekeyapiv1alpha2.Hook{}, want: []string{"node1", "node2", "node3"}},
+		{hook: kubekeyapiv1alpha2.Hook{Roles: []string{common.Master}}, want: []string{"node1"}},
+		{hook: kubekeyapiv1alpha2.Hook{Labels: map[string]string{"disk": "ssd"}}, want: []string{"node2"}},
+		{hook: kubekeyapiv1alpha2.Hook{Roles: []string{common.Master}, Labels: map[string]string{"disk": "ssd"}}},
+	}
+	for _, tt := range tests {
+		got := map[string]bool{}
+		for _, host := range hookHosts(hosts, cfgs, tt.hook) {
+			got[host.GetName()] = true
+		}
+		want := map[string]bool{}
+		for _, name := range tt.want {
+			want[name] = true
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("hosts of %+v = %v, want %v", tt.hook, got, tt.want)
+		}
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	tests := []struct {
+		hook  kubekeyapiv1alpha2.Hook
+		valid bool
+	}{
+		{hook: kubekeyapiv1alpha2.Hook{Name: "a", Bash: "true", After: "JoinNodesModule"}, valid: true},
+		{hook: kubekeyapiv1alpha2.Hook{Name: "a", Bash: "true", Before: "JoinNodesModule", FailurePolicy: "ignore"}, valid: true},
+		{hook: kubekeyapiv1alpha2.Hook{Name: "a", After: "JoinNodesModule"}},
+		{hook: kubekeyapiv1alpha2.Hook{Name: "a", Bash: "true"}},
+		{hook: kubekeyapiv1alpha2.Hook{Name: "a", Bash: "true", Before: "A", After: "B"}},
+		{hook: kubekeyapiv1alpha2.Hook{Name: "a", Bash: "true", After: "A", FailurePolicy: "retry"}},
+		{hook: kubekeyapiv1alpha2.Hook{Name: "a", Bash: "true", After: "A", Timeout: -1}},
+	}
+	for _, tt := range tests {
+		system := kubekeyapiv1alpha2.System{Hooks: []kubekeyapiv1alpha2.Hook{tt.hook}}
+		if err := system.ValidateHooks(); (err == nil) != tt.valid {
+			t.Errorf("ValidateHooks(%+v) = %v, want valid %v", tt.hook, err, tt.valid)
+		}
+	}
+
+	hook := kubekeyapiv1alpha2.Hook{Name: "a", Bash: "true", After: "JoinNodesModule"}
+	system := kubekeyapiv1alpha2.System{Hooks: []kubekeyapiv1alpha2.Hook{hook, hook}}
+	if err := system.ValidateHooks(); err == nil {
+		t.Error("ValidateHooks() accepts the hooks with the same name")
+	}
+}
@@ -29,6 +29,10 @@ type GreetingsModule struct {
 	module.BaseTaskModule
 }
 
+func (h *GreetingsModule) IsCollector() bool {
+	return true
+}
+
 func (h *GreetingsModule) Init() {
 	h.Name = "GreetingsModule"
 	h.Desc = "Greetings"
@@ -61,6 +65,10 @@ func (n *NodePreCheckModule) IsSkip() bool {
 	return n.Skip
 }
 
+func (n *NodePreCheckModule) IsCollector() bool {
+	return true
+}
+
 func (n *NodePreCheckModule) Init() {
 	n.Name = "NodePreCheckModule"
 	n.Desc = "Do pre-check on cluster nodes"
@@ -90,6 +98,10 @@ type ClusterPreCheckModule struct {
 	common.KubeModule
 }
 
+func (c *ClusterPreCheckModule) IsCollector() bool {
+	return true
+}
+
 func (c *ClusterPreCheckModule) Init() {
 	c.Name = "ClusterPreCheckModule"
 	c.Desc = "Do pre-check on cluster"
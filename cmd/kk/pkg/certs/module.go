@@ -17,15 +17,18 @@
 package certs
 
 import (
+	"fmt"
 	"path/filepath"
 
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs/templates"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/action"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/etcd"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
 )
 
@@ -188,3 +191,237 @@ func (u *UninstallAutoRenewCertsModule) Init() {
 		uninstall,
 	}
 }
+
+// RotateCAModule runs a stage of the cluster and etcd CA rotation. The components are restarted one by one after the
+// CA files of the stage are synchronized, so that the cluster keeps serving during the rotation.
+type RotateCAModule struct {
+	common.KubeModule
+	Stage string
+}
+
+func (r *RotateCAModule) Init() {
+	r.Name = "RotateCAModule"
+	r.Desc = fmt.Sprintf("Rotate the cluster CA: %s stage", r.Stage)
+
+	fetchCA := &task.RemoteTask{
+		Name:     "FetchCA",
+		Desc:     "Fetch the CA and prepare the CA files of the stage",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   &FetchCA{Stage: r.Stage},
+		Parallel: true,
+	}
+
+	syncCA := &task.RemoteTask{
+		Name:     "SyncCA",
+		Desc:     "Synchronize the cluster CA to masters",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   &SyncCA{Stage: r.Stage, CA: clusterCA, WithKey: true},
+		Parallel: true,
+		Retry:    1,
+	}
+
+	syncCAToWorker := &task.RemoteTask{
+		Name:     "SyncCAToWorker",
+		Desc:     "Synchronize the cluster CA to workers",
+		Hosts:    r.Runtime.GetHostsByRole(common.Worker),
+		Prepare:  new(common.OnlyWorker),
+		Action:   &SyncCA{Stage: r.Stage, CA: clusterCA},
+		Parallel: true,
+		Retry:    1,
+	}
+
+	updateKubeConfig := &task.RemoteTask{
+		Name:     "UpdateKubeConfigCA",
+		Desc:     "Update the CA in the kubeconfig files",
+		Hosts:    r.Runtime.GetHostsByRole(common.K8s),
+		Action:   new(UpdateKubeConfigCA),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	updateClusterInfo := &task.RemoteTask{
+		Name:     "UpdateClusterInfo",
+		Desc:     "Update the CA in the cluster-info configmap",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(UpdateClusterInfo),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	r.Tasks = []task.Interface{
+		fetchCA,
+		syncCA,
+		syncCAToWorker,
+		updateKubeConfig,
+		updateClusterInfo,
+	}
+
+	if r.Stage == RotateCAStageReissue {
+		reissueControlPlane := &task.RemoteTask{
+			Name:     "ReissueControlPlaneCerts",
+			Desc:     "Sign the control plane certs by the new CA",
+			Hosts:    r.Runtime.GetHostsByRole(common.Master),
+			Action:   new(ReissueControlPlaneCerts),
+			Parallel: false,
+			Retry:    2,
+		}
+
+		reissueKubelet := &task.RemoteTask{
+			Name:     "ReissueKubeletClientCert",
+			Desc:     "Sign the kubelet client cert by the new CA",
+			Hosts:    r.Runtime.GetHostsByRole(common.K8s),
+			Action:   new(ReissueKubeletClientCert),
+			Parallel: true,
+			Retry:    1,
+		}
+
+		r.Tasks = append(r.Tasks, reissueControlPlane, reissueKubelet)
+	}
+
+	if r.KubeConf.Cluster.Etcd.Type == kubekeyapiv1alpha2.KubeKey {
+		r.Tasks = append(r.Tasks, r.rotateETCDCA()...)
+	}
+
+	restartControlPlane := &task.RemoteTask{
+		Name:     "RestartControlPlane",
+		Desc:     "Restart the control plane one by one",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(kubernetes.RestartControlPlane),
+		Parallel: false,
+		Retry:    2,
+	}
+
+	restartKubelet := &task.RemoteTask{
+		Name:     "RestartKubelet",
+		Desc:     "Restart kubelet one by one",
+		Hosts:    r.Runtime.GetHostsByRole(common.K8s),
+		Action:   new(kubernetes.RestartKubelet),
+		Parallel: false,
+	}
+
+	copyKubeConfig := &task.RemoteTask{
+		Name:     "CopyKubeConfig",
+		Desc:     "Copy admin.conf to ~/.kube/config",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(kubernetes.CopyKubeConfigForControlPlane),
+		Parallel: true,
+		Retry:    2,
+	}
+
+	fetchKubeConfig := &task.RemoteTask{
+		Name:     "FetchKubeConfig",
+		Desc:     "Fetch the kubeconfig from the first master",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(FetchKubeConfig),
+		Parallel: true,
+	}
+
+	syncKubeConfig := &task.RemoteTask{
+		Name:     "SyncKubeConfigToWorker",
+		Desc:     "Synchronize the kubeconfig to workers",
+		Hosts:    r.Runtime.GetHostsByRole(common.Worker),
+		Prepare:  new(common.OnlyWorker),
+		Action:   new(SyneKubeConfigToWorker),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	r.Tasks = append(r.Tasks,
+		restartControlPlane,
+		restartKubelet,
+		copyKubeConfig,
+		fetchKubeConfig,
+		syncKubeConfig,
+	)
+
+	if r.Stage == RotateCAStageTrust {
+		restartWorkloads := &task.RemoteTask{
+			Name:     "RestartSystemWorkloads",
+			Desc:     "Restart the workloads in kube-system to trust the new CA",
+			Hosts:    r.Runtime.GetHostsByRole(common.Master),
+			Prepare:  new(common.OnlyFirstMaster),
+			Action:   new(RestartSystemWorkloads),
+			Parallel: true,
+		}
+		r.Tasks = append(r.Tasks, restartWorkloads)
+	}
+}
+
+// rotateETCDCA returns the tasks which synchronize the etcd CA of the stage and restart the etcd members one by one.
+// The etcd certs are signed by the new CA in the reissue stage.
+func (r *RotateCAModule) rotateETCDCA() []task.Interface {
+	syncCA := &task.RemoteTask{
+		Name:     "SyncETCDCA",
+		Desc:     "Synchronize the etcd CA",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   &SyncCA{Stage: r.Stage, CA: etcdCA, WithKey: true},
+		Parallel: true,
+		Retry:    1,
+	}
+
+	syncCAToMaster := &task.RemoteTask{
+		Name:     "SyncETCDCAToMaster",
+		Desc:     "Synchronize the etcd CA to master",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  &common.OnlyETCD{Not: true},
+		Action:   &SyncCA{Stage: r.Stage, CA: etcdCA, WithKey: true},
+		Parallel: true,
+		Retry:    1,
+	}
+
+	tasks := []task.Interface{
+		syncCA,
+		syncCAToMaster,
+	}
+
+	if r.Stage == RotateCAStageReissue {
+		reissue := &task.LocalTask{
+			Name:   "ReissueETCDCerts",
+			Desc:   "Sign the etcd certs by the new CA",
+			Action: new(etcd.RenewCerts),
+		}
+
+		syncCerts := &task.RemoteTask{
+			Name:     "SyncETCDCertsFile",
+			Desc:     "Synchronize etcd certs file",
+			Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+			Action:   new(etcd.SyncCertsFile),
+			Parallel: true,
+			Retry:    1,
+		}
+
+		syncCertsToMaster := &task.RemoteTask{
+			Name:     "SyncETCDCertsFileToMaster",
+			Desc:     "Synchronize etcd certs file to master",
+			Hosts:    r.Runtime.GetHostsByRole(common.Master),
+			Prepare:  &common.OnlyETCD{Not: true},
+			Action:   new(etcd.SyncCertsFile),
+			Parallel: true,
+			Retry:    1,
+		}
+
+		tasks = append(tasks, reissue, syncCerts, syncCertsToMaster)
+	}
+
+	restart := &task.RemoteTask{
+		Name:     "RollingRestartETCD",
+		Desc:     "Restart etcd members one by one",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(etcd.RollingRestartETCD),
+		Parallel: false,
+	}
+
+	refreshClientCerts := &task.RemoteTask{
+		Name:     "RefreshKubeSphereETCDClientCerts",
+		Desc:     "Refresh the etcd client certs of KubeSphere",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(etcd.RefreshKubeSphereClientCerts),
+		Parallel: true,
+	}
+
+	return append(tasks, restart, refreshClientCerts)
+}
@@ -0,0 +1,503 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/cache"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	utilcerts "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils/certs"
+)
+
+// The stages of the CA rotation. The new CA is trusted beside the old one first, then the certs are signed by the new
+// CA, and the old CA is dropped at last, so that the components keep trusting each other during the rotation.
+const (
+	RotateCAStageTrust   = "trust"
+	RotateCAStageReissue = "reissue"
+	RotateCAStageDrop    = "drop"
+	RotateCAStageAll     = "all"
+)
+
+// RotateCAStages are the stages run by RotateCAStageAll in order.
+var RotateCAStages = []string{RotateCAStageTrust, RotateCAStageReissue, RotateCAStageDrop}
+
+// ValidateRotateCAStage checks whether the stage of the CA rotation is supported.
+func ValidateRotateCAStage(stage string) error {
+	if stage == RotateCAStageAll {
+		return nil
+	}
+	for _, s := range RotateCAStages {
+		if stage == s {
+			return nil
+		}
+	}
+	return errors.Errorf("unsupported stage %s, it should be one of %s or %s",
+		stage, strings.Join(RotateCAStages, ", "), RotateCAStageAll)
+}
+
+// authority describes the files of a CA on the nodes, the new CA is kept beside it until the old CA is dropped.
+type authority struct {
+	name     string
+	dir      string
+	cert     string
+	key      string
+	nextCert string
+	nextKey  string
+}
+
+var (
+	clusterCA = authority{
+		name:     "kubernetes",
+		dir:      common.KubeCertDir,
+		cert:     "ca.crt",
+		key:      "ca.key",
+		nextCert: "ca-next.crt",
+		nextKey:  "ca-next.key",
+	}
+	// the local dir of the etcd CA is the one used by the etcd module, so that the etcd certs are signed again by
+	// etcd.RenewCerts with the CA of the stage.
+	etcdCA = authority{
+		name:     "etcd",
+		dir:      common.ETCDCertDir,
+		cert:     "ca.pem",
+		key:      "ca-key.pem",
+		nextCert: "ca-next.pem",
+		nextKey:  "ca-next-key.pem",
+	}
+)
+
+func (a authority) localDir(runtime connector.Runtime) string {
+	return filepath.Join(runtime.GetWorkDir(), "pki", a.name)
+}
+
+func (a authority) cacheKey() string {
+	return fmt.Sprintf("rotateCA-%s", a.name)
+}
+
+// authorities returns the CAs rotated by KubeKey, the etcd CA of kubeadm and the external etcd are not rotated.
+func authorities(kubeConf *common.KubeConf) []authority {
+	if kubeConf.Cluster.Etcd.Type == kubekeyapiv1alpha2.KubeKey {
+		return []authority{clusterCA, etcdCA}
+	}
+	return []authority{clusterCA}
+}
+
+// rotation is the CA of a stage, which is saved in the module cache.
+type rotation struct {
+	bundle  []byte
+	next    *x509.Certificate
+	nextKey crypto.Signer
+}
+
+// caBundle returns the CA certs trusted in the stage, the first one is the CA which signs the certs.
+func caBundle(stage string, current []*x509.Certificate, next *x509.Certificate) ([]*x509.Certificate, error) {
+	var previous *x509.Certificate
+	for _, c := range current {
+		if !c.Equal(next) {
+			previous = c
+			break
+		}
+	}
+
+	switch stage {
+	case RotateCAStageTrust:
+		if previous == nil || current[0].Equal(next) {
+			return nil, errors.New("the certs have been signed by the new CA, the rotation should continue with the reissue or drop stage")
+		}
+		return []*x509.Certificate{previous, next}, nil
+	case RotateCAStageReissue:
+		if previous == nil {
+			return []*x509.Certificate{next}, nil
+		}
+		return []*x509.Certificate{next, previous}, nil
+	case RotateCAStageDrop:
+		return []*x509.Certificate{next}, nil
+	default:
+		return nil, ValidateRotateCAStage(stage)
+	}
+}
+
+func encodeCerts(certs []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, c := range certs {
+		buf.Write(utilcerts.EncodeCertPEM(c))
+	}
+	return buf.Bytes()
+}
+
+// FetchCA fetches the CAs from the first master, the new CA is generated in the trust stage if it does not exist. The
+// CA files of the stage are prepared in the local pki dir.
+type FetchCA struct {
+	common.KubeAction
+	Stage string
+}
+
+func (f *FetchCA) Execute(runtime connector.Runtime) error {
+	for _, ca := range authorities(f.KubeConf) {
+		r, err := f.prepare(runtime, ca)
+		if err != nil {
+			return err
+		}
+		f.ModuleCache.Set(ca.cacheKey(), r)
+	}
+	return nil
+}
+
+func (f *FetchCA) prepare(runtime connector.Runtime, ca authority) (*rotation, error) {
+	dir := ca.localDir(runtime)
+	if err := util.CreateDir(dir); err != nil {
+		return nil, errors.Wrapf(err, "failed to create dir %s", dir)
+	}
+	files := []string{ca.cert, ca.key}
+	exist, err := runtime.GetRunner().FileExist(filepath.Join(ca.dir, ca.nextCert))
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		files = append(files, ca.nextCert, ca.nextKey)
+	}
+	for _, name := range files {
+		if err := runtime.GetRunner().Fetch(filepath.Join(dir, name), filepath.Join(ca.dir, name)); err != nil {
+			return nil, errors.Wrapf(err, "fetch %s failed", filepath.Join(ca.dir, name))
+		}
+	}
+
+	current, err := certutil.CertsFromFile(filepath.Join(dir, ca.cert))
+	if err != nil {
+		return nil, errors.Wrapf(err, "load the %s CA failed", ca.name)
+	}
+
+	r := &rotation{}
+	switch {
+	case exist:
+		certs, err := certutil.CertsFromFile(filepath.Join(dir, ca.nextCert))
+		if err != nil {
+			return nil, errors.Wrapf(err, "load the new %s CA failed", ca.name)
+		}
+		key, err := keyutil.PrivateKeyFromFile(filepath.Join(dir, ca.nextKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "load the key of the new %s CA failed", ca.name)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.Errorf("the key of the new %s CA is not a signer", ca.name)
+		}
+		r.next, r.nextKey = certs[0], signer
+	case f.Stage == RotateCAStageTrust:
+		cfg := &utilcerts.CertConfig{Config: certutil.Config{CommonName: current[0].Subject.CommonName}}
+		if r.next, r.nextKey, err = utilcerts.NewCertificateAuthority(cfg); err != nil {
+			return nil, err
+		}
+		if err := certutil.WriteCert(filepath.Join(dir, ca.nextCert), utilcerts.EncodeCertPEM(r.next)); err != nil {
+			return nil, errors.Wrapf(err, "write the new %s CA failed", ca.name)
+		}
+	default:
+		return nil, errors.Errorf("the new %s CA is not found, the rotation should start with the %s stage", ca.name, RotateCAStageTrust)
+	}
+	nextKey, err := keyutil.MarshalPrivateKeyToPEM(r.nextKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal private key to PEM")
+	}
+	if err := keyutil.WriteKey(filepath.Join(dir, ca.nextKey), nextKey); err != nil {
+		return nil, errors.Wrapf(err, "write the key of the new %s CA failed", ca.name)
+	}
+
+	bundle, err := caBundle(f.Stage, current, r.next)
+	if err != nil {
+		return nil, errors.Wrapf(err, "rotate the %s CA failed", ca.name)
+	}
+	r.bundle = encodeCerts(bundle)
+	if err := certutil.WriteCert(filepath.Join(dir, ca.cert), r.bundle); err != nil {
+		return nil, errors.Wrapf(err, "write the %s CA failed", ca.name)
+	}
+	// the key of the old CA is kept until the certs are signed by the new CA
+	if f.Stage != RotateCAStageTrust {
+		if err := keyutil.WriteKey(filepath.Join(dir, ca.key), nextKey); err != nil {
+			return nil, errors.Wrapf(err, "write the key of the %s CA failed", ca.name)
+		}
+	}
+	return r, nil
+}
+
+// SyncCA synchronizes the CA files of the stage to the node, the key and the new CA are only synchronized to the nodes
+// which sign certs. The new CA is removed in the drop stage.
+type SyncCA struct {
+	common.KubeAction
+	Stage   string
+	CA      authority
+	WithKey bool
+}
+
+func (s *SyncCA) Execute(runtime connector.Runtime) error {
+	files := []string{s.CA.cert}
+	if s.WithKey {
+		files = append(files, s.CA.key)
+		if s.Stage != RotateCAStageDrop {
+			files = append(files, s.CA.nextCert, s.CA.nextKey)
+		}
+	}
+
+	dir := s.CA.localDir(runtime)
+	for _, name := range files {
+		if err := runtime.GetRunner().SudoScp(filepath.Join(dir, name), filepath.Join(s.CA.dir, name)); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "sync %s failed", filepath.Join(s.CA.dir, name))
+		}
+	}
+
+	if s.Stage == RotateCAStageDrop {
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -f %s %s",
+			filepath.Join(s.CA.dir, s.CA.nextCert), filepath.Join(s.CA.dir, s.CA.nextKey)), false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "remove the new %s CA failed", s.CA.name)
+		}
+	}
+	return nil
+}
+
+// UpdateKubeConfigCA sets the CA of the stage in the kubeconfig files of the control plane and kubelet.
+type UpdateKubeConfigCA struct {
+	common.KubeAction
+}
+
+func (u *UpdateKubeConfigCA) Execute(runtime connector.Runtime) error {
+	r, err := clusterRotation(u.ModuleCache)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"admin.conf", "controller-manager.conf", "scheduler.conf", "kubelet.conf"} {
+		remote := filepath.Join("/etc/kubernetes", name)
+		if exist, err := runtime.GetRunner().FileExist(remote); err != nil {
+			return err
+		} else if !exist {
+			continue
+		}
+		if err := updateRemoteKubeConfig(runtime, remote, func(config *clientcmdapi.Config) {
+			setKubeConfigCA(config, r.bundle)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateClusterInfo sets the CA of the stage in the cluster-info ConfigMap, which is used by the nodes to join.
+type UpdateClusterInfo struct {
+	common.KubeAction
+}
+
+func (u *UpdateClusterInfo) Execute(runtime connector.Runtime) error {
+	r, err := clusterRotation(u.ModuleCache)
+	if err != nil {
+		return err
+	}
+
+	remote := "/etc/kubernetes/cluster-info.conf"
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl -n kube-public get configmap cluster-info -o jsonpath='{.data.kubeconfig}' > %s", remote), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "get the cluster-info configmap failed")
+	}
+	if err := updateRemoteKubeConfig(runtime, remote, func(config *clientcmdapi.Config) {
+		setKubeConfigCA(config, r.bundle)
+	}); err != nil {
+		return err
+	}
+
+	// the jws signatures in the configmap are kept by apply, and they are updated by the bootstrap signer
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"/usr/local/bin/kubectl -n kube-public create configmap cluster-info --from-file=kubeconfig=%s --dry-run=client -o yaml "+
+			"| /usr/local/bin/kubectl apply -f - && rm -f %s", remote, remote), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "update the cluster-info configmap failed")
+	}
+	return nil
+}
+
+// ReissueControlPlaneCerts signs the certs and kubeconfig files of the control plane by the new CA.
+type ReissueControlPlaneCerts struct {
+	common.KubeAction
+}
+
+func (r *ReissueControlPlaneCerts) Execute(runtime connector.Runtime) error {
+	// the front-proxy-client is signed by the front-proxy CA, which is not rotated
+	return renewKubeadmCerts(runtime,
+		"apiserver",
+		"apiserver-kubelet-client",
+		"admin.conf",
+		"controller-manager.conf",
+		"scheduler.conf",
+	)
+}
+
+// ReissueKubeletClientCert signs the client cert of kubelet by the new CA. The cert is written to the file referred
+// by kubelet.conf, or embedded into kubelet.conf.
+type ReissueKubeletClientCert struct {
+	common.KubeAction
+}
+
+func (r *ReissueKubeletClientCert) Execute(runtime connector.Runtime) error {
+	ca, err := clusterRotation(r.ModuleCache)
+	if err != nil {
+		return err
+	}
+
+	host := runtime.RemoteHost()
+	cert, key, err := utilcerts.NewCertAndKey(ca.next, ca.nextKey, &utilcerts.CertConfig{
+		Config: certutil.Config{
+			CommonName:   fmt.Sprintf("system:node:%s", host.GetName()),
+			Organization: []string{"system:nodes"},
+			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "sign the kubelet client cert of %s failed", host.GetName())
+	}
+	encodedKey, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal private key to PEM")
+	}
+	encodedCert := utilcerts.EncodeCertPEM(cert)
+
+	var certFile string
+	if err := updateRemoteKubeConfig(runtime, "/etc/kubernetes/kubelet.conf", func(config *clientcmdapi.Config) {
+		for _, authInfo := range config.AuthInfos {
+			if authInfo.ClientCertificate != "" {
+				certFile = authInfo.ClientCertificate
+				continue
+			}
+			authInfo.ClientCertificateData = encodedCert
+			authInfo.ClientKeyData = encodedKey
+		}
+	}); err != nil {
+		return err
+	}
+	if certFile == "" {
+		return nil
+	}
+
+	// the rotated client cert of kubelet is a link to the pem file which contains both the cert and key
+	local := filepath.Join(runtime.GetWorkDir(), host.GetName(), "kubelet-client.pem")
+	if err := os.WriteFile(local, append(encodedCert, encodedKey...), 0600); err != nil {
+		return errors.Wrap(err, "write the kubelet client cert failed")
+	}
+	defer os.Remove(local)
+
+	remote := filepath.Join(filepath.Dir(certFile), fmt.Sprintf("kubelet-client-%s.pem", time.Now().Format("2006-01-02-15-04-05")))
+	if err := runtime.GetRunner().SudoScp(local, remote); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "sync %s failed", remote)
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("chmod 600 %s && ln -sf %s %s", remote, remote, certFile), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "link %s to %s failed", certFile, remote)
+	}
+	return nil
+}
+
+// RestartSystemWorkloads waits until the new CA is published to the service accounts by kube-controller-manager,
+// and then restarts the workloads in kube-system, so that they trust the new CA before the certs are signed by it.
+type RestartSystemWorkloads struct {
+	common.KubeAction
+}
+
+func (r *RestartSystemWorkloads) Execute(runtime connector.Runtime) error {
+	ca, err := clusterRotation(r.ModuleCache)
+	if err != nil {
+		return err
+	}
+
+	published := false
+	for i := 0; i < 30 && !published; i++ {
+		output, err := runtime.GetRunner().SudoCmd(
+			"/usr/local/bin/kubectl -n kube-system get configmap kube-root-ca.crt -o jsonpath='{.data.ca\\.crt}'", false)
+		if err == nil {
+			certs, err := certutil.ParseCertsPEM([]byte(strings.ReplaceAll(output, "\r\n", "\n")))
+			if err == nil {
+				for _, c := range certs {
+					published = published || c.Equal(ca.next)
+				}
+			}
+		}
+		if !published {
+			time.Sleep(5 * time.Second)
+		}
+	}
+	if !published {
+		return errors.New("the new CA is not published to the kube-root-ca.crt configmap")
+	}
+
+	if _, err := runtime.GetRunner().SudoCmd(
+		"/usr/local/bin/kubectl -n kube-system rollout restart deployment && "+
+			"/usr/local/bin/kubectl -n kube-system rollout restart daemonset", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restart the workloads in kube-system failed")
+	}
+	return nil
+}
+
+// updateRemoteKubeConfig fetches the kubeconfig file from the node, and writes it back after it is updated.
+func updateRemoteKubeConfig(runtime connector.Runtime, remote string, update func(config *clientcmdapi.Config)) error {
+	host := runtime.RemoteHost()
+	local := filepath.Join(runtime.GetWorkDir(), host.GetName(), filepath.Base(remote))
+	if err := runtime.GetRunner().Fetch(local, remote); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "fetch %s failed", remote)
+	}
+	defer os.Remove(local)
+
+	config, err := clientcmd.LoadFromFile(local)
+	if err != nil {
+		return errors.Wrapf(err, "load %s failed", remote)
+	}
+	update(config)
+	if err := clientcmd.WriteToFile(*config, local); err != nil {
+		return errors.Wrapf(err, "write %s failed", remote)
+	}
+
+	if err := runtime.GetRunner().SudoScp(local, remote); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "sync %s failed", remote)
+	}
+	return nil
+}
+
+// setKubeConfigCA sets the CA data of the clusters, the clusters which refer to a CA file are not changed.
+func setKubeConfigCA(config *clientcmdapi.Config, ca []byte) {
+	for _, cluster := range config.Clusters {
+		if cluster.CertificateAuthority == "" {
+			cluster.CertificateAuthorityData = ca
+		}
+	}
+}
+
+func clusterRotation(moduleCache *cache.Cache) (*rotation, error) {
+	v, ok := moduleCache.Get(clusterCA.cacheKey())
+	if !ok {
+		return nil, errors.New("get the CA of the stage from module cache failed")
+	}
+	return v.(*rotation), nil
+}
@@ -0,0 +1,70 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509"
+	"testing"
+
+	certutil "k8s.io/client-go/util/cert"
+
+	utilcerts "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils/certs"
+)
+
+func newCA(t *testing.T) *x509.Certificate {
+	cert, _, err := utilcerts.NewCertificateAuthority(&utilcerts.CertConfig{Config: certutil.Config{CommonName: "kubernetes"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestCABundle(t *testing.T) {
+	old, next := newCA(t), newCA(t)
+
+	tests := []struct {
+		name    string
+		stage   string
+		current []*x509.Certificate
+		want    []*x509.Certificate
+		wantErr bool
+	}{
+		{name: "trust", stage: RotateCAStageTrust, current: []*x509.Certificate{old}, want: []*x509.Certificate{old, next}},
+		{name: "trust again", stage: RotateCAStageTrust, current: []*x509.Certificate{old, next}, want: []*x509.Certificate{old, next}},
+		{name: "trust after reissue", stage: RotateCAStageTrust, current: []*x509.Certificate{next, old}, wantErr: true},
+		{name: "reissue", stage: RotateCAStageReissue, current: []*x509.Certificate{old, next}, want: []*x509.Certificate{next, old}},
+		{name: "reissue again", stage: RotateCAStageReissue, current: []*x509.Certificate{next, old}, want: []*x509.Certificate{next, old}},
+		{name: "drop", stage: RotateCAStageDrop, current: []*x509.Certificate{next, old}, want: []*x509.Certificate{next}},
+		{name: "unknown stage", stage: "rollback", current: []*x509.Certificate{old}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := caBundle(tt.stage, tt.current, next)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("caBundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("caBundle() returns %d certs, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("caBundle()[%d] is %s, want %s", i, got[i].SerialNumber, tt.want[i].SerialNumber)
+				}
+			}
+		})
+	}
+}
@@ -32,9 +32,11 @@ import (
 	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
 	certutil "k8s.io/client-go/util/cert"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs/templates"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils"
 )
 
@@ -44,6 +46,7 @@ type Certificate struct {
 	Residual      string
 	AuthorityName string
 	NodeName      string
+	NotAfter      time.Time
 }
 
 type CaCertificate struct {
@@ -51,6 +54,7 @@ type CaCertificate struct {
 	Expires       string
 	Residual      string
 	NodeName      string
+	NotAfter      time.Time
 }
 
 var (
@@ -156,6 +160,7 @@ func getCertInfo(certContext, certFileName, nodeName string) (*Certificate, erro
 		Residual:      ResidualTime(certs[0].NotAfter),
 		AuthorityName: authorityName,
 		NodeName:      nodeName,
+		NotAfter:      certs[0].NotAfter,
 	}
 	return &cert, nil
 }
@@ -170,6 +175,7 @@ func getCaCertInfo(certContext, certFileName, nodeName string) (*CaCertificate,
 		Expires:       certs[0].NotAfter.Format("Jan 02, 2006 15:04 MST"),
 		Residual:      ResidualTime(certs[0].NotAfter),
 		NodeName:      nodeName,
+		NotAfter:      certs[0].NotAfter,
 	}
 	return &cert1, nil
 }
@@ -252,31 +258,31 @@ type RenewCerts struct {
 }
 
 func (r *RenewCerts) Execute(runtime connector.Runtime) error {
-	var kubeadmAlphaList = []string{
-		"/usr/local/bin/kubeadm alpha certs renew apiserver",
-		"/usr/local/bin/kubeadm alpha certs renew apiserver-kubelet-client",
-		"/usr/local/bin/kubeadm alpha certs renew front-proxy-client",
-		"/usr/local/bin/kubeadm alpha certs renew admin.conf",
-		"/usr/local/bin/kubeadm alpha certs renew controller-manager.conf",
-		"/usr/local/bin/kubeadm alpha certs renew scheduler.conf",
+	renewList := []string{
+		"apiserver",
+		"apiserver-kubelet-client",
+		"front-proxy-client",
+		"admin.conf",
+		"controller-manager.conf",
+		"scheduler.conf",
 	}
-
-	var kubeadmList = []string{
-		"/usr/local/bin/kubeadm certs renew apiserver",
-		"/usr/local/bin/kubeadm certs renew apiserver-kubelet-client",
-		"/usr/local/bin/kubeadm certs renew front-proxy-client",
-		"/usr/local/bin/kubeadm certs renew admin.conf",
-		"/usr/local/bin/kubeadm certs renew controller-manager.conf",
-		"/usr/local/bin/kubeadm certs renew scheduler.conf",
+	components := kubernetes.ControlPlaneComponents
+	// the etcd certs of kubeadm are renewed by kubeadm, the others are renewed by etcd.RenewCertsModule
+	if r.KubeConf.Arg.RenewETCDCerts && r.KubeConf.Cluster.Etcd.Type == kubekeyapiv1alpha2.Kubeadm {
+		renewList = append(renewList, "etcd-server", "etcd-peer", "etcd-healthcheck-client", "apiserver-etcd-client")
+		// etcd is restarted first, so that kube-apiserver connects to it with the new certs
+		components = append([]string{kubernetes.ETCD}, components...)
 	}
 
-	var restartList = []string{
-		"docker ps -af name=k8s_kube-apiserver* -q | xargs --no-run-if-empty docker rm -f",
-		"docker ps -af name=k8s_kube-scheduler* -q | xargs --no-run-if-empty docker rm -f",
-		"docker ps -af name=k8s_kube-controller-manager* -q | xargs --no-run-if-empty docker rm -f",
-		"systemctl restart kubelet",
+	if err := renewKubeadmCerts(runtime, renewList...); err != nil {
+		return err
 	}
 
+	return kubernetes.RestartStaticPods(runtime, r.KubeConf.Cluster.Kubernetes.ContainerManager, components...)
+}
+
+// renewKubeadmCerts renews the certs and kubeconfig files managed by kubeadm with the CA in /etc/kubernetes/pki.
+func renewKubeadmCerts(runtime connector.Runtime, renewList ...string) error {
 	version, err := runtime.GetRunner().SudoCmd("/usr/local/bin/kubeadm version -o short", true)
 	if err != nil {
 		return errors.Wrap(errors.WithStack(err), "kubeadm get version failed")
@@ -285,21 +291,17 @@ func (r *RenewCerts) Execute(runtime connector.Runtime) error {
 	if err != nil {
 		return errors.Wrap(errors.WithStack(err), "parse kubeadm version failed")
 	}
+	renewCmd := "/usr/local/bin/kubeadm certs renew"
 	if cmp == -1 {
-		_, err := runtime.GetRunner().SudoCmd(strings.Join(kubeadmAlphaList, " && "), false)
-		if err != nil {
-			return errors.Wrap(err, "kubeadm alpha certs renew failed")
-		}
-	} else {
-		_, err := runtime.GetRunner().SudoCmd(strings.Join(kubeadmList, " && "), false)
-		if err != nil {
-			return errors.Wrap(err, "kubeadm alpha certs renew failed")
-		}
+		renewCmd = "/usr/local/bin/kubeadm alpha certs renew"
 	}
 
-	_, err = runtime.GetRunner().SudoCmd(strings.Join(restartList, " && "), false)
-	if err != nil {
-		return errors.Wrap(err, "kube-apiserver, kube-schedule, kube-controller-manager or kubelet restart failed")
+	cmds := make([]string, 0, len(renewList))
+	for _, cert := range renewList {
+		cmds = append(cmds, fmt.Sprintf("%s %s", renewCmd, cert))
+	}
+	if _, err := runtime.GetRunner().SudoCmd(strings.Join(cmds, " && "), false); err != nil {
+		return errors.Wrap(err, "kubeadm certs renew failed")
 	}
 	return nil
 }
@@ -26,15 +26,22 @@ import (
 
 	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
 )
 
 type ArtifactArgument struct {
-	ManifestFile    string
-	Output          string
-	CriSocket       string
-	Debug           bool
-	IgnoreErr       bool
-	DownloadCommand func(path, url string) string
+	ManifestFile     string
+	Output           string
+	Base             string
+	SignKey          string
+	SignCert         string
+	SignaturePolicy  string
+	CriSocket        string
+	Debug            bool
+	IgnoreErr        bool
+	DownloadCommand  func(path, url string) string
+	DownloadProxy    string
+	DownloadCABundle string
 }
 
 type ArtifactRuntime struct {
@@ -49,6 +56,10 @@ func NewArtifactRuntime(arg ArtifactArgument) (*ArtifactRuntime, error) {
 		return nil, err
 	}
 
+	if err := files.ConfigureDownloader(files.DownloaderOptions{Proxy: arg.DownloadProxy, CABundle: arg.DownloadCABundle}); err != nil {
+		return nil, err
+	}
+
 	fp, err := filepath.Abs(arg.ManifestFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to look up current directory")
@@ -0,0 +1,241 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+)
+
+const (
+	// ClusterConfigNamespace is the namespace where the effective cluster configuration is kept.
+	ClusterConfigNamespace = "kube-system"
+	// ClusterConfigName is the name of both the ConfigMap of the redacted cluster configuration and the Secret of
+	// the credentials in it.
+	ClusterConfigName = "kubekey-cluster-config"
+	// ClusterConfigKey is the key of the cluster configuration in the ConfigMap.
+	ClusterConfigKey = "cluster.yaml"
+
+	registryAuthsSecret = "registry.auths"
+	s3AccessKeySecret   = "etcd.backupTarget.s3.accessKeyID"
+	s3SecretKeySecret   = "etcd.backupTarget.s3.secretAccessKey"
+	sftpPasswordSecret  = "etcd.backupTarget.sftp.password"
+)
+
+func hostPasswordSecret(name string) string {
+	return fmt.Sprintf("hosts.%s.password", name)
+}
+
+func hostPrivateKeySecret(name string) string {
+	return fmt.Sprintf("hosts.%s.privateKey", name)
+}
+
+// RedactCluster returns a copy of the cluster without the credentials, and the credentials keyed by their paths in
+// the configuration.
+func RedactCluster(cluster *kubekeyapiv1alpha2.Cluster) (*kubekeyapiv1alpha2.Cluster, map[string][]byte, error) {
+	redacted, err := copyCluster(cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets := make(map[string][]byte)
+	take := func(key string, value *string) {
+		if *value != "" {
+			secrets[key] = []byte(*value)
+			*value = ""
+		}
+	}
+
+	for i := range redacted.Spec.Hosts {
+		host := &redacted.Spec.Hosts[i]
+		take(hostPasswordSecret(host.Name), &host.Password)
+		take(hostPrivateKeySecret(host.Name), &host.PrivateKey)
+	}
+	if len(redacted.Spec.Registry.Auths.Raw) != 0 {
+		secrets[registryAuthsSecret] = redacted.Spec.Registry.Auths.Raw
+		redacted.Spec.Registry.Auths.Raw = nil
+	}
+	if target := redacted.Spec.Etcd.BackupTarget; target != nil {
+		if target.S3 != nil {
+			take(s3AccessKeySecret, &target.S3.AccessKeyID)
+			take(s3SecretKeySecret, &target.S3.SecretAccessKey)
+		}
+		if target.SFTP != nil {
+			take(sftpPasswordSecret, &target.SFTP.Password)
+		}
+	}
+	return redacted, secrets, nil
+}
+
+// RestoreClusterSecrets fills the credentials taken by RedactCluster back into the cluster. The values which are
+// already set in the cluster are kept.
+func RestoreClusterSecrets(cluster *kubekeyapiv1alpha2.Cluster, secrets map[string][]byte) {
+	restore := func(key string, value *string) {
+		if v, ok := secrets[key]; ok && *value == "" {
+			*value = string(v)
+		}
+	}
+
+	for i := range cluster.Spec.Hosts {
+		host := &cluster.Spec.Hosts[i]
+		restore(hostPasswordSecret(host.Name), &host.Password)
+		restore(hostPrivateKeySecret(host.Name), &host.PrivateKey)
+	}
+	if v, ok := secrets[registryAuthsSecret]; ok && len(cluster.Spec.Registry.Auths.Raw) == 0 {
+		cluster.Spec.Registry.Auths.Raw = v
+	}
+	if target := cluster.Spec.Etcd.BackupTarget; target != nil {
+		if target.S3 != nil {
+			restore(s3AccessKeySecret, &target.S3.AccessKeyID)
+			restore(s3SecretKeySecret, &target.S3.SecretAccessKey)
+		}
+		if target.SFTP != nil {
+			restore(sftpPasswordSecret, &target.SFTP.Password)
+		}
+	}
+}
+
+// ClusterConfigManifest returns the ConfigMap of the redacted cluster configuration and the Secret of its
+// credentials, they are applied into the cluster by kubectl.
+func ClusterConfigManifest(cluster *kubekeyapiv1alpha2.Cluster) ([]byte, error) {
+	redacted, secrets, err := RedactCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+	content, err := yaml.Marshal(redacted)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal the cluster configuration failed")
+	}
+
+	labels := map[string]string{"app.kubernetes.io/managed-by": "kubekey"}
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: ClusterConfigName, Namespace: ClusterConfigNamespace, Labels: labels},
+		Data:       map[string]string{ClusterConfigKey: string(content)},
+	}
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: ClusterConfigName, Namespace: ClusterConfigNamespace, Labels: labels},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       secrets,
+	}
+
+	cmContent, err := yaml.Marshal(cm)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal the cluster configuration configmap failed")
+	}
+	secretContent, err := yaml.Marshal(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal the cluster configuration secret failed")
+	}
+	return append(append(cmContent, []byte("---\n")...), secretContent...), nil
+}
+
+// MergeClusterDelta merges the configuration supplied by the operator into the stored one. The hosts are merged by
+// name, the role groups are merged by role, and the other fields are merged as a JSON merge patch (RFC 7386), so
+// that a field can be removed by setting it to null.
+func MergeClusterDelta(stored *kubekeyapiv1alpha2.Cluster, delta []byte) (*kubekeyapiv1alpha2.Cluster, error) {
+	deltaCluster := &kubekeyapiv1alpha2.Cluster{}
+	if err := json.Unmarshal(delta, deltaCluster); err != nil {
+		return nil, errors.Wrap(err, "unmarshal the cluster configuration delta failed")
+	}
+	patch := make(map[string]interface{})
+	if err := json.Unmarshal(delta, &patch); err != nil {
+		return nil, errors.Wrap(err, "unmarshal the cluster configuration delta failed")
+	}
+	if spec, ok := patch["spec"].(map[string]interface{}); ok {
+		delete(spec, "hosts")
+		delete(spec, "roleGroups")
+	}
+
+	original, err := json.Marshal(stored)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal the stored cluster configuration failed")
+	}
+	patchContent, err := json.Marshal(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal the cluster configuration delta failed")
+	}
+	merged, err := jsonpatch.MergePatch(original, patchContent)
+	if err != nil {
+		return nil, errors.Wrap(err, "merge the cluster configuration delta failed")
+	}
+	cluster := &kubekeyapiv1alpha2.Cluster{}
+	if err := json.Unmarshal(merged, cluster); err != nil {
+		return nil, errors.Wrap(err, "unmarshal the merged cluster configuration failed")
+	}
+
+	cluster.Spec.Hosts = mergeHosts(stored.Spec.Hosts, deltaCluster.Spec.Hosts)
+	cluster.Spec.RoleGroups = mergeRoleGroups(stored.Spec.RoleGroups, deltaCluster.Spec.RoleGroups)
+	return cluster, nil
+}
+
+func mergeHosts(stored, delta []kubekeyapiv1alpha2.HostCfg) []kubekeyapiv1alpha2.HostCfg {
+	hosts := append([]kubekeyapiv1alpha2.HostCfg{}, stored...)
+	index := make(map[string]int, len(hosts))
+	for i, h := range hosts {
+		index[h.Name] = i
+	}
+	for _, h := range delta {
+		if i, ok := index[h.Name]; ok {
+			hosts[i] = h
+			continue
+		}
+		index[h.Name] = len(hosts)
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+func mergeRoleGroups(stored, delta map[string][]string) map[string][]string {
+	roleGroups := make(map[string][]string, len(stored))
+	for role, hosts := range stored {
+		roleGroups[role] = append([]string{}, hosts...)
+	}
+	for role, hosts := range delta {
+		exist := make(map[string]struct{}, len(roleGroups[role]))
+		for _, h := range roleGroups[role] {
+			exist[h] = struct{}{}
+		}
+		for _, h := range hosts {
+			if _, ok := exist[h]; !ok {
+				exist[h] = struct{}{}
+				roleGroups[role] = append(roleGroups[role], h)
+			}
+		}
+	}
+	return roleGroups
+}
+
+func copyCluster(cluster *kubekeyapiv1alpha2.Cluster) (*kubekeyapiv1alpha2.Cluster, error) {
+	content, err := json.Marshal(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal the cluster configuration failed")
+	}
+	c := &kubekeyapiv1alpha2.Cluster{}
+	if err := json.Unmarshal(content, c); err != nil {
+		return nil, errors.Wrap(err, "unmarshal the cluster configuration failed")
+	}
+	return c, nil
+}
@@ -26,6 +26,11 @@ const (
 	AllInOne = "allInOne"
 	File     = "file"
 	Operator = "operator"
+	// InCluster loads the cluster configuration saved in the cluster, see ConfigMapLoader.
+	InCluster = "inCluster"
+
+	OutputText = "text"
+	OutputJSON = "json"
 
 	Master        = "master"
 	Worker        = "worker"
@@ -52,6 +57,9 @@ const (
 
 	HaproxyDir = "/etc/kubekey/haproxy"
 
+	CheckpointDir = "checkpoints"
+	KnownHostsDir = "known_hosts"
+
 	IPv4Regexp = "[\\d]+\\.[\\d]+\\.[\\d]+\\.[\\d]+"
 	IPv6Regexp = "[a-f0-9]{1,4}(:[a-f0-9]{1,4}){7}|[a-f0-9]{1,4}(:[a-f0-9]{1,4}){0,7}::[a-f0-9]{0,4}(:[a-f0-9]{1,4}){0,7}"
 
@@ -94,5 +102,6 @@ const (
 	CaCertificate = "caCertificate"
 
 	// Artifact pipeline
-	Artifact = "artifact"
+	Artifact       = "artifact"
+	ArtifactSigned = "artifactSigned"
 )
@@ -17,8 +17,22 @@
 package common
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/event"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/validation"
 )
 
 type KubeRuntime struct {
@@ -47,12 +61,27 @@ type Argument struct {
 	FromCluster         bool
 	KubeConfig          string
 	Artifact            string
+	BaseArtifact        string
+	ArtifactKey         string
+	ArtifactCA          string
+	SignaturePolicy     string
 	InstallPackages     bool
 	ImagesDir           string
 	Namespace           string
 	DeleteCRI           bool
 	Role                string
 	Type                string
+	Resume              bool
+	HostKeyChecking     string
+	DryRun              bool
+	Output              string
+	ReportFile          string
+	NoRollback          bool
+	DownloadProxy       string
+	DownloadCABundle    string
+	Snapshot            string
+	RenewETCDCerts      bool
+	RotateCAStage       string
 }
 
 func NewKubeRuntime(flag string, arg Argument) (*KubeRuntime, error) {
@@ -62,16 +91,59 @@ func NewKubeRuntime(flag string, arg Argument) (*KubeRuntime, error) {
 		return nil, err
 	}
 
-	base := connector.NewBaseRuntime(cluster.Name, connector.NewDialer(), arg.Debug, arg.IgnoreErr)
+	dialer := connector.NewDialer()
+	base := connector.NewBaseRuntime(cluster.Name, dialer, arg.Debug, arg.IgnoreErr)
+
+	checker, err := connector.NewHostKeyChecker(arg.HostKeyChecking,
+		filepath.Join(base.GetWorkDir(), KnownHostsDir, cluster.Name))
+	if err != nil {
+		return nil, err
+	}
+	dialer.SetHostKeyChecker(checker)
+	if arg.DryRun {
+		base.SetDryRun(connector.NewDryRun())
+	}
+	base.SetNoRollback(arg.NoRollback)
+	if err := files.ConfigureDownloader(files.DownloaderOptions{Proxy: arg.DownloadProxy, CABundle: arg.DownloadCABundle}); err != nil {
+		return nil, err
+	}
+
+	switch arg.Output {
+	case "", OutputText:
+	case OutputJSON:
+		// the console only prints the events, the logs are still written into the log file.
+		logger.Log.SetConsoleOutput(io.Discard)
+	default:
+		return nil, errors.Errorf("unsupported output format [%s], it should be %s or %s", arg.Output, OutputText, OutputJSON)
+	}
+	if arg.Output == OutputJSON || arg.ReportFile != "" {
+		var out io.Writer
+		if arg.Output == OutputJSON {
+			out = os.Stdout
+		}
+		base.SetEvents(event.NewRecorder(out, arg.ReportFile))
+	}
 
 	clusterSpec := &cluster.Spec
-	defaultCluster, roleGroups := clusterSpec.SetDefaultClusterSpec()
+	if err := validation.ToError(append(validation.ValidateCluster(clusterSpec), validation.ValidateCredentials(clusterSpec)...)); err != nil {
+		return nil, err
+	}
+	for _, warning := range validation.ClusterWarnings(clusterSpec) {
+		logger.Log.Warnf("the cluster configuration: %s", warning)
+	}
+	defaultCluster, roleGroups, err := clusterSpec.SetDefaultClusterSpec()
+	if err != nil {
+		return nil, err
+	}
 
 	hostSet := make(map[string]struct{})
 	for _, role := range roleGroups {
 		for _, host := range role {
 			if host.IsRole(Master) || host.IsRole(Worker) {
 				host.SetRole(K8s)
+				if ip := net.ParseIP(host.GetInternalIPv6Address()); defaultCluster.Network.EnableDualStack() && (ip == nil || ip.To4() != nil) {
+					return nil, errors.Errorf("the internalIPv6Address of host %s must be an IPv6 address in a dual-stack cluster", host.GetName())
+				}
 			}
 			if _, ok := hostSet[host.GetName()]; !ok {
 				hostSet[host.GetName()] = struct{}{}
@@ -98,3 +170,30 @@ func (k *KubeRuntime) Copy() connector.Runtime {
 	runtime := *k
 	return &runtime
 }
+
+// ConfigHash returns the hash of the effective cluster configuration. It is used to make sure
+// a checkpoint journal is only resumed with the same configuration.
+func (k *KubeRuntime) ConfigHash() (string, error) {
+	content, err := json.Marshal(struct {
+		ClusterName       string
+		Cluster           *kubekeyapiv1alpha2.ClusterSpec
+		KubernetesVersion string
+		KsEnable          bool
+		KsVersion         string
+		ContainerManager  string
+		Artifact          string
+	}{
+		ClusterName:       k.ClusterName,
+		Cluster:           k.Cluster,
+		KubernetesVersion: k.Arg.KubernetesVersion,
+		KsEnable:          k.Arg.KsEnable,
+		KsVersion:         k.Arg.KsVersion,
+		ContainerManager:  k.Arg.ContainerManager,
+		Artifact:          k.Arg.Artifact,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
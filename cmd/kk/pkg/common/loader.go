@@ -18,6 +18,7 @@ package common
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -31,9 +32,14 @@ import (
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/version/kubesphere"
 )
@@ -52,8 +58,8 @@ func NewLoader(flag string, arg Argument) Loader {
 	switch flag {
 	case File:
 		return NewFileLoader(arg)
-	case Operator:
-		return &ConfigMapLoader{}
+	case Operator, InCluster:
+		return NewConfigMapLoader(arg)
 	case AllInOne:
 		return NewDefaultLoader(arg)
 	default:
@@ -239,17 +245,125 @@ func (f FileLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
 		}
 	}
 
-	if f.KubeSphereEnable {
-		ver := normalizedBuildVersion(f.KubeSphereVersion)
+	if err := overrideByArgument(&clusterCfg, f.KubernetesVersion, f.KubeSphereEnable, f.KubeSphereVersion, f.arg.ContainerManager); err != nil {
+		return nil, err
+	}
+	clusterCfg.Name = objName
+	return &clusterCfg, nil
+}
+
+// ConfigMapLoader loads the cluster configuration saved in the cluster by the last create, add nodes or upgrade.
+// The configuration file, if specified, is the delta supplied by the operator and merged into the saved one.
+type ConfigMapLoader struct {
+	arg               Argument
+	KubeConfig        string
+	FilePath          string
+	KubernetesVersion string
+	KubeSphereVersion string
+	KubeSphereEnable  bool
+}
+
+func NewConfigMapLoader(arg Argument) *ConfigMapLoader {
+	return &ConfigMapLoader{
+		arg:               arg,
+		KubeConfig:        arg.KubeConfig,
+		FilePath:          arg.FilePath,
+		KubernetesVersion: arg.KubernetesVersion,
+		KubeSphereVersion: arg.KsVersion,
+		KubeSphereEnable:  arg.KsEnable,
+	}
+}
+
+func (c ConfigMapLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
+	kubeconfig := c.KubeConfig
+	if kubeconfig == "" {
+		kubeconfig = clientcmd.RecommendedHomeFile
+	}
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to load the kubeconfig %s", kubeconfig)
+	}
+	clientset, err := kube.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create the kubernetes client")
+	}
+
+	cm, err := clientset.CoreV1().ConfigMaps(ClusterConfigNamespace).Get(context.TODO(), ClusterConfigName, metav1.GetOptions{})
+	if kubeerrors.IsNotFound(err) {
+		return nil, errors.Errorf("the cluster configuration %s/%s is not found in the cluster, "+
+			"please specify the configuration file without --from-cluster", ClusterConfigNamespace, ClusterConfigName)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Failed to get the cluster configuration")
+	}
+	clusterCfg := &kubekeyapiv1alpha2.Cluster{}
+	if err := k8syaml.Unmarshal([]byte(cm.Data[ClusterConfigKey]), clusterCfg); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal the cluster configuration")
+	}
+
+	if c.FilePath != "" {
+		delta, err := readClusterDelta(c.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		if clusterCfg, err = MergeClusterDelta(clusterCfg, delta); err != nil {
+			return nil, err
+		}
+	}
+
+	// the credentials are restored after the merge, so that the hosts listed again in the file keep them
+	secret, err := clientset.CoreV1().Secrets(ClusterConfigNamespace).Get(context.TODO(), ClusterConfigName, metav1.GetOptions{})
+	if err != nil {
+		// the hosts might be accessed by the local ssh keys, so the credentials are not mandatory
+		logger.Log.Warningf("Failed to get the credentials of the cluster configuration: %v", err)
+	} else {
+		RestoreClusterSecrets(clusterCfg, secret.Data)
+	}
+
+	if err := overrideByArgument(clusterCfg, c.KubernetesVersion, c.KubeSphereEnable, c.KubeSphereVersion, c.arg.ContainerManager); err != nil {
+		return nil, err
+	}
+	return clusterCfg, nil
+}
+
+// readClusterDelta returns the Cluster in the configuration file in json.
+func readClusterDelta(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open the given cluster configuration file")
+	}
+	defer file.Close()
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(file))
+	for {
+		content, err := reader.Read()
+		if len(content) == 0 {
+			return nil, errors.New("no Cluster is found in the given cluster configuration file")
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Unable to read the given cluster configuration file")
+		}
+		result := make(map[string]interface{})
+		if err := yaml.Unmarshal(content, &result); err != nil {
+			return nil, errors.Wrap(err, "Unable to unmarshal the given cluster configuration file")
+		}
+		if result["kind"] == "Cluster" {
+			return k8syaml.ToJSON(content)
+		}
+	}
+}
+
+// overrideByArgument overrides the versions and the container manager of the cluster by the command line arguments.
+func overrideByArgument(clusterCfg *kubekeyapiv1alpha2.Cluster, kubernetesVersion string, ksEnable bool, ksVersion, containerManager string) error {
+	if ksEnable {
+		ver := normalizedBuildVersion(ksVersion)
 		if ver == "" {
-			return nil, errors.New(fmt.Sprintf("Unsupported Kubesphere Version: %v\n", f.KubeSphereVersion))
+			return errors.New(fmt.Sprintf("Unsupported Kubesphere Version: %v\n", ksVersion))
 		}
 		if err := defaultKSConfig(&clusterCfg.Spec.KubeSphere, ver); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	if ver := normalizedBuildVersion(f.KubernetesVersion); ver != "" {
+	if ver := normalizedBuildVersion(kubernetesVersion); ver != "" {
 		s := strings.Split(ver, "-")
 		if len(s) > 1 {
 			clusterCfg.Spec.Kubernetes.Version = s[0]
@@ -259,21 +373,13 @@ func (f FileLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
 		}
 	}
 
-	if f.arg.ContainerManager != "" && f.arg.ContainerManager != Docker {
-		clusterCfg.Spec.Kubernetes.ContainerManager = f.arg.ContainerManager
+	if containerManager != "" && containerManager != Docker {
+		clusterCfg.Spec.Kubernetes.ContainerManager = containerManager
 	}
 
 	clusterCfg.Spec.Kubernetes.Version = normalizedBuildVersion(clusterCfg.Spec.Kubernetes.Version)
 	clusterCfg.Spec.KubeSphere.Version = normalizedBuildVersion(clusterCfg.Spec.KubeSphere.Version)
-	clusterCfg.Name = objName
-	return &clusterCfg, nil
-}
-
-type ConfigMapLoader struct {
-}
-
-func (c ConfigMapLoader) Load() (*kubekeyapiv1alpha2.Cluster, error) {
-	return nil, nil
+	return nil
 }
 
 func defaultKSConfig(ks *kubekeyapiv1alpha2.KubeSphere, version string) error {
@@ -150,7 +150,10 @@ func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
 			if address.Type == "Hostname" {
 				nodeCfg.Name = address.Address
 			}
-			if address.Type == "InternalIP" {
+			// a node of the dual-stack cluster has an IPv4 and an IPv6 internal address
+			if address.Type == "InternalIP" && net.ParseIP(address.Address).To4() == nil {
+				nodeCfg.InternalIPv6Address = address.Address
+			} else if address.Type == "InternalIP" {
 				nodeCfg.Address = address.Address
 				nodeCfg.InternalAddress = address.Address
 			}
@@ -164,6 +167,9 @@ func GetInfoFromCluster(config, name string) (*OptionsCluster, error) {
 			opt.WorkerGroup = append(opt.WorkerGroup, nodeCfg.Name)
 		}
 		nodeCfgStr := fmt.Sprintf("{name: %s, address: %s, internalAddress: %s}", nodeCfg.Name, nodeCfg.Address, nodeCfg.InternalAddress)
+		if nodeCfg.InternalIPv6Address != "" {
+			nodeCfgStr = fmt.Sprintf("{name: %s, address: %s, internalAddress: %s, internalIPv6Address: %s}", nodeCfg.Name, nodeCfg.Address, nodeCfg.InternalAddress, nodeCfg.InternalIPv6Address)
+		}
 		opt.Hosts = append(opt.Hosts, nodeCfgStr)
 
 		opt.MaxPods = nodeInfo.Status.Capacity.Pods().String()
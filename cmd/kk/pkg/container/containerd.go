@@ -155,15 +155,7 @@ func (d *DisableContainerd) Execute(runtime connector.Runtime) error {
 	}
 
 	// remove containerd related files
-	files := []string{
-		"/usr/local/sbin/runc",
-		"/usr/bin/crictl",
-		"/usr/bin/containerd*",
-		"/usr/bin/ctr",
-		filepath.Join("/etc/systemd/system", templates.ContainerdService.Name()),
-		filepath.Join("/etc/containerd", templates.ContainerdConfig.Name()),
-		filepath.Join("/etc", templates.CrictlConfig.Name()),
-	}
+	files := containerdFiles()
 	if d.KubeConf.Cluster.Registry.DataRoot != "" {
 		files = append(files, d.KubeConf.Cluster.Registry.DataRoot)
 	} else {
@@ -176,6 +168,19 @@ func (d *DisableContainerd) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// containerdFiles returns the binaries and configs of containerd which are installed by KubeKey.
+func containerdFiles() []string {
+	return []string{
+		"/usr/local/sbin/runc",
+		"/usr/bin/crictl",
+		"/usr/bin/containerd*",
+		"/usr/bin/ctr",
+		filepath.Join("/etc/systemd/system", templates.ContainerdService.Name()),
+		filepath.Join("/etc/containerd", templates.ContainerdConfig.Name()),
+		filepath.Join("/etc", templates.CrictlConfig.Name()),
+	}
+}
+
 type CordonNode struct {
 	common.KubeAction
 }
@@ -230,6 +235,10 @@ func (i *RestartCri) Execute(runtime connector.Runtime) error {
 		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("systemctl daemon-reload && systemctl restart containerd"), true); err != nil {
 			return errors.Wrap(err, "restart containerd")
 		}
+	case common.Crio:
+		if _, err := runtime.GetRunner().SudoCmd("systemctl daemon-reload && systemctl restart crio", true); err != nil {
+			return errors.Wrap(err, "restart crio")
+		}
 
 	default:
 		logger.Log.Fatalf("Unsupported container runtime: %s", strings.TrimSpace(i.KubeConf.Arg.Type))
@@ -255,6 +264,15 @@ func (i *EditKubeletCri) Execute(runtime connector.Runtime) error {
 			true); err != nil {
 			return errors.Wrap(err, "Change KubeletTo Containerd failed")
 		}
+	case common.Crio:
+		// replace the endpoint of containerd, or add the remote runtime flags if kubelet runs with dockershim
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+			"sed -i -e 's#--container-runtime-endpoint=unix://[^ ]*\\.sock#--container-runtime-endpoint=unix://%s#' "+
+				"-e '/--container-runtime-endpoint/!s#--network-plugin=cni --pod#--network-plugin=cni --container-runtime=remote --container-runtime-endpoint=unix://%s --pod#' "+
+				"/var/lib/kubelet/kubeadm-flags.env", crioListen(i.KubeConf), crioListen(i.KubeConf)),
+			true); err != nil {
+			return errors.Wrap(err, "Change KubeletTo Crio failed")
+		}
 
 	default:
 		logger.Log.Fatalf("Unsupported container runtime: %s", strings.TrimSpace(i.KubeConf.Arg.Type))
@@ -345,6 +363,18 @@ func MigrateSelfNodeCriTasks(runtime connector.Runtime, kubeAction common.KubeAc
 			Parallel: false,
 		}
 		tasks = append(tasks, CordonNode, DrainNode, Uninstall)
+	case common.Crio:
+		Uninstall := &task.RemoteTask{
+			Name:  "UninstallCrio",
+			Desc:  "Uninstall crio",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: false},
+			},
+			Action:   new(DisableCrio),
+			Parallel: false,
+		}
+		tasks = append(tasks, CordonNode, DrainNode, Uninstall)
 	}
 	if kubeAction.KubeConf.Arg.Type == common.Docker {
 		syncBinaries := &task.RemoteTask{
@@ -507,6 +537,143 @@ func MigrateSelfNodeCriTasks(runtime connector.Runtime, kubeAction common.KubeAc
 			generateCrictlConfig, enableContainerd, RestartCri, EditKubeletCri, RestartKubeletNode, UnCordonNode)
 	}
 
+	if kubeAction.KubeConf.Arg.Type == common.Crio {
+		auths := registry.DockerRegistryAuthEntries(kubeAction.KubeConf.Cluster.Registry.Auths)
+
+		syncCrio := &task.RemoteTask{
+			Name:  "SyncCrio",
+			Desc:  "Sync crio binaries",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: true},
+			},
+			Action:   new(SyncCrio),
+			Parallel: false,
+		}
+
+		syncCrictlBinaries := &task.RemoteTask{
+			Name:  "SyncCrictlBinaries",
+			Desc:  "Sync crictl binaries",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrictlExist{Not: true},
+			},
+			Action:   new(SyncCrictlBinaries),
+			Parallel: false,
+		}
+
+		generateCrioService := &task.RemoteTask{
+			Name:  "GenerateCrioService",
+			Desc:  "Generate crio service",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: true},
+			},
+			Action: &action.Template{
+				Template: templates.CrioService,
+				Dst:      filepath.Join("/etc/systemd/system", templates.CrioService.Name()),
+			},
+			Parallel: false,
+		}
+
+		generateCrioConfig := &task.RemoteTask{
+			Name:  "GenerateCrioConfig",
+			Desc:  "Generate crio config",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: true},
+			},
+			Action: &action.Template{
+				Template: templates.CrioConfig,
+				Dst:      filepath.Join("/etc/crio", templates.CrioConfig.Name()),
+				Data: util.Data{
+					"DataRoot":     templates.DataRoot(kubeAction.KubeConf),
+					"Listen":       crioListen(kubeAction.KubeConf),
+					"SandBoxImage": images.GetImage(runtime, kubeAction.KubeConf, "pause").ImageName(),
+					"AuthFile":     crioAuthFile(auths),
+				},
+			},
+			Parallel: false,
+		}
+
+		generateCrioRegistriesConfig := &task.RemoteTask{
+			Name:  "GenerateCrioRegistriesConfig",
+			Desc:  "Generate crio registries config",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: true},
+			},
+			Action: &action.Template{
+				Template: templates.CrioRegistriesConfig,
+				Dst:      filepath.Join("/etc/containers", templates.CrioRegistriesConfig.Name()),
+				Data: util.Data{
+					"Registries": templates.CrioRegistries(kubeAction.KubeConf, auths),
+				},
+			},
+			Parallel: false,
+		}
+
+		generateCrioPolicy := &task.RemoteTask{
+			Name:  "GenerateCrioPolicy",
+			Desc:  "Generate crio signature policy",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: true},
+			},
+			Action: &action.Template{
+				Template: templates.CrioPolicy,
+				Dst:      filepath.Join("/etc/containers", templates.CrioPolicy.Name()),
+			},
+			Parallel: false,
+		}
+
+		generateCrioAuthConfig := &task.RemoteTask{
+			Name:  "GenerateCrioAuthConfig",
+			Desc:  "Generate crio auth config",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: true},
+				&PrivateRegistryAuth{},
+			},
+			Action: &action.Template{
+				Template: templates.CrioAuthConfig,
+				Dst:      templates.CrioAuthFile,
+				Data: util.Data{
+					"Auths": templates.CrioAuths(auths),
+				},
+			},
+			Parallel: false,
+		}
+
+		generateCrictlConfig := &task.RemoteTask{
+			Name:  "GenerateCrictlConfig",
+			Desc:  "Generate crictl config",
+			Hosts: []connector.Host{host},
+			Prepare: &prepare.PrepareCollection{
+				&CrioExist{Not: true},
+			},
+			Action: &action.Template{
+				Template: templates.CrictlConfig,
+				Dst:      filepath.Join("/etc/", templates.CrictlConfig.Name()),
+				Data: util.Data{
+					"Endpoint": "unix://" + crioListen(kubeAction.KubeConf),
+				},
+			},
+			Parallel: false,
+		}
+
+		enableCrio := &task.RemoteTask{
+			Name:     "EnableCrio",
+			Desc:     "Enable crio",
+			Hosts:    []connector.Host{host},
+			Action:   new(EnableCrio),
+			Parallel: false,
+		}
+		tasks = append(tasks, syncCrio, syncCrictlBinaries, generateCrioService, generateCrioConfig,
+			generateCrioRegistriesConfig, generateCrioPolicy, generateCrioAuthConfig, generateCrictlConfig, enableCrio,
+			RestartCri, EditKubeletCri, RestartKubeletNode, UnCordonNode)
+	}
+
 	for i := range tasks {
 		t := tasks[i]
 		t.Init(runtime, kubeAction.ModuleCache, kubeAction.PipelineCache)
@@ -0,0 +1,141 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/container/templates"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/registry"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils"
+)
+
+type SyncCrio struct {
+	common.KubeAction
+}
+
+func (s *SyncCrio) Execute(runtime connector.Runtime) error {
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+
+	binariesMapObj, ok := s.PipelineCache.Get(common.KubeBinaries + "-" + runtime.RemoteHost().GetArch())
+	if !ok {
+		return errors.New("get KubeBinary by pipeline cache failed")
+	}
+	binariesMap := binariesMapObj.(map[string]*files.KubeBinary)
+
+	crio, ok := binariesMap[common.Crio]
+	if !ok {
+		return errors.New("get KubeBinary key crio by pipeline cache failed")
+	}
+
+	dst := filepath.Join(common.TmpDir, crio.FileName)
+	if err := runtime.GetRunner().Scp(crio.Path(), dst); err != nil {
+		return errors.Wrap(errors.WithStack(err), "sync crio binaries failed")
+	}
+
+	// the bundle contains crio and its dependencies, e.g. conmon, pinns, runc and crun
+	if _, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("mkdir -p /usr/bin && tar -zxf %s -C %s && cp -f %s/cri-o/bin/* /usr/bin/ && rm -rf %s/cri-o",
+			dst, common.TmpDir, common.TmpDir, common.TmpDir),
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "install crio binaries failed")
+	}
+	return nil
+}
+
+type EnableCrio struct {
+	common.KubeAction
+}
+
+func (e *EnableCrio) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl daemon-reload && systemctl enable crio && systemctl restart crio",
+		false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "enable and start crio failed")
+	}
+	return nil
+}
+
+type DisableCrio struct {
+	common.KubeAction
+}
+
+func (d *DisableCrio) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"systemctl disable crio && systemctl stop crio", true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "disable and stop crio failed")
+	}
+
+	// remove crio related files
+	files := crioFiles()
+	if d.KubeConf.Cluster.Registry.DataRoot != "" {
+		files = append(files, d.KubeConf.Cluster.Registry.DataRoot)
+	} else {
+		files = append(files, "/var/lib/containers/storage")
+	}
+
+	for _, file := range files {
+		_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s", file), true)
+	}
+	return nil
+}
+
+// crioFiles returns the binaries and configs of crio which are installed by KubeKey.
+func crioFiles() []string {
+	return []string{
+		"/usr/bin/crio",
+		"/usr/bin/crio-status",
+		"/usr/bin/pinns",
+		"/usr/bin/conmon",
+		"/usr/bin/conmonrs",
+		"/usr/bin/crun",
+		"/usr/bin/runc",
+		"/usr/bin/crictl",
+		filepath.Join("/etc/systemd/system", templates.CrioService.Name()),
+		filepath.Join("/etc/crio", templates.CrioConfig.Name()),
+		templates.CrioAuthFile,
+		filepath.Join("/etc/containers", templates.CrioRegistriesConfig.Name()),
+		filepath.Join("/etc/containers", templates.CrioPolicy.Name()),
+		filepath.Join("/etc", templates.CrictlConfig.Name()),
+	}
+}
+
+// crioAuthFile returns the global auth file of crio if any registry auth is configured.
+func crioAuthFile(auths map[string]*registry.DockerRegistryEntry) string {
+	if len(auths) == 0 {
+		return ""
+	}
+	return templates.CrioAuthFile
+}
+
+// crioListen returns the socket which crio listens on.
+func crioListen(kubeConf *common.KubeConf) string {
+	endpoint := kubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
+	if kubeConf.Cluster.Kubernetes.ContainerManager != common.Crio || endpoint == "" {
+		return templates.CrioSocket
+	}
+	return strings.TrimPrefix(endpoint, "unix://")
+}
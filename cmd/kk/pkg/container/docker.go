@@ -120,14 +120,7 @@ func (d *DisableDocker) Execute(runtime connector.Runtime) error {
 	}
 
 	// remove docker related files
-	files := []string{
-		"/usr/bin/runc",
-		"/usr/bin/ctr",
-		"/usr/bin/docker*",
-		"/usr/bin/containerd*",
-		filepath.Join("/etc/systemd/system", templates.DockerService.Name()),
-		filepath.Join("/etc/docker", templates.DockerConfig.Name()),
-	}
+	files := dockerFiles()
 	if d.KubeConf.Cluster.Registry.DataRoot != "" {
 		files = append(files, d.KubeConf.Cluster.Registry.DataRoot)
 	} else {
@@ -140,6 +133,18 @@ func (d *DisableDocker) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// dockerFiles returns the binaries and configs of docker which are installed by KubeKey.
+func dockerFiles() []string {
+	return []string{
+		"/usr/bin/runc",
+		"/usr/bin/ctr",
+		"/usr/bin/docker*",
+		"/usr/bin/containerd*",
+		filepath.Join("/etc/systemd/system", templates.DockerService.Name()),
+		filepath.Join("/etc/docker", templates.DockerConfig.Name()),
+	}
+}
+
 func escapeSpecialCharacters(str string) string {
 	newStr := strings.ReplaceAll(str, "$", "\\$")
 	newStr = strings.ReplaceAll(newStr, "&", "\\&")
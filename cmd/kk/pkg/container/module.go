@@ -51,7 +51,7 @@ func (i *InstallContainerModule) Init() {
 	case common.Containerd:
 		i.Tasks = InstallContainerd(i)
 	case common.Crio:
-		// TODO: Add the steps of cri-o's installation.
+		i.Tasks = InstallCrio(i)
 	case common.Isula:
 		// TODO: Add the steps of iSula's installation.
 	default:
@@ -71,6 +71,7 @@ func InstallDocker(m *InstallContainerModule) []task.Interface {
 		Action:   new(SyncDockerBinaries),
 		Parallel: true,
 		Retry:    2,
+		Rollback: new(RollbackDocker),
 	}
 
 	generateDockerService := &task.RemoteTask{
@@ -86,6 +87,7 @@ func InstallDocker(m *InstallContainerModule) []task.Interface {
 			Dst:      filepath.Join("/etc/systemd/system", templates.DockerService.Name()),
 		},
 		Parallel: true,
+		Rollback: new(RollbackDocker),
 	}
 
 	generateDockerConfig := &task.RemoteTask{
@@ -107,6 +109,7 @@ func InstallDocker(m *InstallContainerModule) []task.Interface {
 			},
 		},
 		Parallel: true,
+		Rollback: new(RollbackDocker),
 	}
 
 	enableDocker := &task.RemoteTask{
@@ -119,6 +122,7 @@ func InstallDocker(m *InstallContainerModule) []task.Interface {
 		},
 		Action:   new(EnableDocker),
 		Parallel: true,
+		Rollback: new(RollbackDocker),
 	}
 
 	dockerLoginRegistry := &task.RemoteTask{
@@ -155,6 +159,7 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 		Action:   new(SyncContainerd),
 		Parallel: true,
 		Retry:    2,
+		Rollback: new(RollbackContainerd),
 	}
 
 	syncCrictlBinaries := &task.RemoteTask{
@@ -168,6 +173,7 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 		Action:   new(SyncCrictlBinaries),
 		Parallel: true,
 		Retry:    2,
+		Rollback: new(RollbackContainerd),
 	}
 
 	generateContainerdService := &task.RemoteTask{
@@ -183,6 +189,7 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 			Dst:      filepath.Join("/etc/systemd/system", templates.ContainerdService.Name()),
 		},
 		Parallel: true,
+		Rollback: new(RollbackContainerd),
 	}
 
 	generateContainerdConfig := &task.RemoteTask{
@@ -205,6 +212,7 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 			},
 		},
 		Parallel: true,
+		Rollback: new(RollbackContainerd),
 	}
 
 	generateCrictlConfig := &task.RemoteTask{
@@ -223,6 +231,7 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 			},
 		},
 		Parallel: true,
+		Rollback: new(RollbackContainerd),
 	}
 
 	enableContainerd := &task.RemoteTask{
@@ -235,6 +244,7 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 		},
 		Action:   new(EnableContainerd),
 		Parallel: true,
+		Rollback: new(RollbackContainerd),
 	}
 
 	return []task.Interface{
@@ -247,6 +257,175 @@ func InstallContainerd(m *InstallContainerModule) []task.Interface {
 	}
 }
 
+func InstallCrio(m *InstallContainerModule) []task.Interface {
+	auths := registry.DockerRegistryAuthEntries(m.KubeConf.Cluster.Registry.Auths)
+
+	syncCrio := &task.RemoteTask{
+		Name:  "SyncCrio",
+		Desc:  "Sync crio binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action:   new(SyncCrio),
+		Parallel: true,
+		Retry:    2,
+		Rollback: new(RollbackCrio),
+	}
+
+	syncCrictlBinaries := &task.RemoteTask{
+		Name:  "SyncCrictlBinaries",
+		Desc:  "Sync crictl binaries",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrictlExist{Not: true},
+		},
+		Action:   new(SyncCrictlBinaries),
+		Parallel: true,
+		Retry:    2,
+		Rollback: new(RollbackCrio),
+	}
+
+	generateCrioService := &task.RemoteTask{
+		Name:  "GenerateCrioService",
+		Desc:  "Generate crio service",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioService,
+			Dst:      filepath.Join("/etc/systemd/system", templates.CrioService.Name()),
+		},
+		Parallel: true,
+		Rollback: new(RollbackCrio),
+	}
+
+	generateCrioConfig := &task.RemoteTask{
+		Name:  "GenerateCrioConfig",
+		Desc:  "Generate crio config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioConfig,
+			Dst:      filepath.Join("/etc/crio", templates.CrioConfig.Name()),
+			Data: util.Data{
+				"DataRoot":     templates.DataRoot(m.KubeConf),
+				"Listen":       crioListen(m.KubeConf),
+				"SandBoxImage": images.GetImage(m.Runtime, m.KubeConf, "pause").ImageName(),
+				"AuthFile":     crioAuthFile(auths),
+			},
+		},
+		Parallel: true,
+		Rollback: new(RollbackCrio),
+	}
+
+	generateCrioRegistriesConfig := &task.RemoteTask{
+		Name:  "GenerateCrioRegistriesConfig",
+		Desc:  "Generate crio registries config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioRegistriesConfig,
+			Dst:      filepath.Join("/etc/containers", templates.CrioRegistriesConfig.Name()),
+			Data: util.Data{
+				"Registries": templates.CrioRegistries(m.KubeConf, auths),
+			},
+		},
+		Parallel: true,
+		Rollback: new(RollbackCrio),
+	}
+
+	generateCrioPolicy := &task.RemoteTask{
+		Name:  "GenerateCrioPolicy",
+		Desc:  "Generate crio signature policy",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrioPolicy,
+			Dst:      filepath.Join("/etc/containers", templates.CrioPolicy.Name()),
+		},
+		Parallel: true,
+		Rollback: new(RollbackCrio),
+	}
+
+	generateCrioAuthConfig := &task.RemoteTask{
+		Name:  "GenerateCrioAuthConfig",
+		Desc:  "Generate crio auth config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+			&PrivateRegistryAuth{},
+		},
+		Action: &action.Template{
+			Template: templates.CrioAuthConfig,
+			Dst:      templates.CrioAuthFile,
+			Data: util.Data{
+				"Auths": templates.CrioAuths(auths),
+			},
+		},
+		Parallel: true,
+		Rollback: new(RollbackCrio),
+	}
+
+	generateCrictlConfig := &task.RemoteTask{
+		Name:  "GenerateCrictlConfig",
+		Desc:  "Generate crictl config",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action: &action.Template{
+			Template: templates.CrictlConfig,
+			Dst:      filepath.Join("/etc/", templates.CrictlConfig.Name()),
+			Data: util.Data{
+				"Endpoint": "unix://" + crioListen(m.KubeConf),
+			},
+		},
+		Parallel: true,
+		Rollback: new(RollbackCrio),
+	}
+
+	enableCrio := &task.RemoteTask{
+		Name:  "EnableCrio",
+		Desc:  "Enable crio",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&kubernetes.NodeInCluster{Not: true},
+			&CrioExist{Not: true},
+		},
+		Action:   new(EnableCrio),
+		Parallel: true,
+		Rollback: new(RollbackCrio),
+	}
+
+	return []task.Interface{
+		syncCrio,
+		syncCrictlBinaries,
+		generateCrioService,
+		generateCrioConfig,
+		generateCrioRegistriesConfig,
+		generateCrioPolicy,
+		generateCrioAuthConfig,
+		generateCrictlConfig,
+		enableCrio,
+	}
+}
+
 type UninstallContainerModule struct {
 	common.KubeModule
 	Skip bool
@@ -266,7 +445,7 @@ func (i *UninstallContainerModule) Init() {
 	case common.Containerd:
 		i.Tasks = UninstallContainerd(i)
 	case common.Crio:
-		// TODO: Add the steps of cri-o's installation.
+		i.Tasks = UninstallCrio(i)
 	case common.Isula:
 		// TODO: Add the steps of iSula's installation.
 	default:
@@ -309,6 +488,23 @@ func UninstallContainerd(m *UninstallContainerModule) []task.Interface {
 	}
 }
 
+func UninstallCrio(m *UninstallContainerModule) []task.Interface {
+	disableCrio := &task.RemoteTask{
+		Name:  "UninstallCrio",
+		Desc:  "Uninstall crio",
+		Hosts: m.Runtime.GetHostsByRole(common.K8s),
+		Prepare: &prepare.PrepareCollection{
+			&CrioExist{Not: false},
+		},
+		Action:   new(DisableCrio),
+		Parallel: true,
+	}
+
+	return []task.Interface{
+		disableCrio,
+	}
+}
+
 type CriMigrateModule struct {
 	common.KubeModule
 
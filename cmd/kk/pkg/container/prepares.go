@@ -17,6 +17,7 @@
 package container
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
@@ -80,6 +81,25 @@ func (c *ContainerdExist) PreCheck(runtime connector.Runtime) (bool, error) {
 	return !c.Not, nil
 }
 
+type CrioExist struct {
+	common.KubePrepare
+	Not bool
+}
+
+func (c *CrioExist) PreCheck(runtime connector.Runtime) (bool, error) {
+	output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf(
+		"if [ -z $(which crio) ] || [ ! -e %s ]; "+
+			"then echo 'not exist'; "+
+			"fi", crioListen(c.KubeConf)), false)
+	if err != nil {
+		return false, err
+	}
+	if strings.Contains(output, "not exist") {
+		return c.Not, nil
+	}
+	return !c.Not, nil
+}
+
 type PrivateRegistryAuth struct {
 	common.KubePrepare
 }
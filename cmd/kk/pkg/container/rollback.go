@@ -0,0 +1,77 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
+)
+
+// RollbackDocker stops docker and removes its binaries and configs from a new node which failed to install docker,
+// so that docker is installed again in the next execution. The data root is kept, and the nodes which were already
+// in the cluster are never touched, their container runtime is still in use.
+type RollbackDocker struct {
+	common.KubeRollback
+}
+
+func (r *RollbackDocker) Execute(runtime connector.Runtime, result *ending.ActionResult) error {
+	if result.Status != ending.FAILED || !kubernetes.NotInCluster(runtime, r.PipelineCache) {
+		return nil
+	}
+	uninstall(runtime, "docker", dockerFiles())
+	return nil
+}
+
+// RollbackContainerd stops containerd and removes its binaries and configs from a new node which failed to install
+// containerd, so that containerd is installed again in the next execution. The data root is kept.
+type RollbackContainerd struct {
+	common.KubeRollback
+}
+
+func (r *RollbackContainerd) Execute(runtime connector.Runtime, result *ending.ActionResult) error {
+	if result.Status != ending.FAILED || !kubernetes.NotInCluster(runtime, r.PipelineCache) {
+		return nil
+	}
+	uninstall(runtime, "containerd", containerdFiles())
+	return nil
+}
+
+// RollbackCrio stops crio and removes its binaries and configs from a new node which failed to install crio, so that
+// crio is installed again in the next execution. The storage root is kept.
+type RollbackCrio struct {
+	common.KubeRollback
+}
+
+func (r *RollbackCrio) Execute(runtime connector.Runtime, result *ending.ActionResult) error {
+	if result.Status != ending.FAILED || !kubernetes.NotInCluster(runtime, r.PipelineCache) {
+		return nil
+	}
+	uninstall(runtime, "crio", crioFiles())
+	return nil
+}
+
+func uninstall(runtime connector.Runtime, service string, files []string) {
+	_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("systemctl disable %s && systemctl stop %s", service, service), false)
+	for _, file := range files {
+		_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s", file), false)
+	}
+	_, _ = runtime.GetRunner().SudoCmd("systemctl daemon-reload", false)
+}
@@ -0,0 +1,169 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/lithammer/dedent"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/registry"
+)
+
+const (
+	// CrioAuthFile is the global auth file of CRI-O, it is in the format of containers-auth.json.
+	CrioAuthFile = "/etc/crio/auth.json"
+	// CrioSocket is the default socket of CRI-O.
+	CrioSocket = "/var/run/crio/crio.sock"
+)
+
+var CrioConfig = template.Must(template.New("crio.conf").Parse(
+	dedent.Dedent(`[crio]
+{{- if .DataRoot }}
+root = {{ .DataRoot }}
+{{- else }}
+root = "/var/lib/containers/storage"
+{{- end }}
+runroot = "/var/run/containers/storage"
+storage_driver = "overlay"
+
+[crio.api]
+listen = "{{ .Listen }}"
+
+[crio.runtime]
+default_runtime = "runc"
+cgroup_manager = "systemd"
+conmon_cgroup = "pod"
+
+[crio.runtime.runtimes.runc]
+runtime_path = ""
+runtime_type = "oci"
+runtime_root = "/run/runc"
+
+[crio.image]
+pause_image = "{{ .SandBoxImage }}"
+{{- if .AuthFile }}
+global_auth_file = "{{ .AuthFile }}"
+{{- end }}
+signature_policy = "/etc/containers/policy.json"
+
+[crio.network]
+network_dir = "/etc/cni/net.d/"
+plugin_dirs = ["/opt/cni/bin/"]
+    `)))
+
+var CrioRegistriesConfig = template.Must(template.New("registries.conf").Parse(
+	dedent.Dedent(`unqualified-search-registries = ["docker.io"]
+{{- range .Registries }}
+
+[[registry]]
+prefix = "{{ .Prefix }}"
+location = "{{ .Location }}"
+insecure = {{ .Insecure }}
+{{- range .Mirrors }}
+
+[[registry.mirror]]
+location = "{{ .Location }}"
+insecure = {{ .Insecure }}
+{{- end }}
+{{- end }}
+    `)))
+
+// CrioPolicy accepts all the images, it is the default policy of the containers tools.
+var CrioPolicy = template.Must(template.New("policy.json").Parse(
+	dedent.Dedent(`{
+  "default": [
+    {
+      "type": "insecureAcceptAnything"
+    }
+  ]
+}
+    `)))
+
+var CrioAuthConfig = template.Must(template.New("auth.json").Parse("{{ .Auths }}\n"))
+
+// CrioRegistry is a registry in the registries.conf of CRI-O.
+type CrioRegistry struct {
+	Prefix   string
+	Location string
+	Insecure bool
+	Mirrors  []CrioRegistry
+}
+
+// CrioRegistries returns the registries of CRI-O. The registry mirrors are the mirrors of docker.io, and the insecure
+// registries and the registries which skip the TLS verification are marked as insecure.
+func CrioRegistries(kubeConf *common.KubeConf, auths map[string]*registry.DockerRegistryEntry) []CrioRegistry {
+	dockerHub := CrioRegistry{Prefix: "docker.io", Location: "registry-1.docker.io"}
+	for _, mirror := range kubeConf.Cluster.Registry.RegistryMirrors {
+		dockerHub.Mirrors = append(dockerHub.Mirrors, CrioRegistry{
+			Location: trimScheme(mirror),
+			Insecure: strings.HasPrefix(mirror, "http://"),
+		})
+	}
+
+	insecure := make(map[string]struct{})
+	for _, r := range kubeConf.Cluster.Registry.InsecureRegistries {
+		insecure[trimScheme(r)] = struct{}{}
+	}
+	for r, entry := range auths {
+		if entry.SkipTLSVerify {
+			insecure[trimScheme(r)] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(insecure))
+	for r := range insecure {
+		names = append(names, r)
+	}
+	sort.Strings(names)
+
+	registries := []CrioRegistry{dockerHub}
+	for _, r := range names {
+		registries = append(registries, CrioRegistry{Prefix: r, Location: r, Insecure: true})
+	}
+	return registries
+}
+
+// CrioAuths returns the content of the auth file of CRI-O.
+func CrioAuths(auths map[string]*registry.DockerRegistryEntry) string {
+	type auth struct {
+		Auth string `json:"auth"`
+	}
+	config := struct {
+		Auths map[string]auth `json:"auths"`
+	}{Auths: make(map[string]auth)}
+	for r, entry := range auths {
+		if entry.Username == "" {
+			continue
+		}
+		config.Auths[trimScheme(r)] = auth{
+			Auth: base64.StdEncoding.EncodeToString([]byte(entry.Username + ":" + entry.Password)),
+		}
+	}
+	data, _ := json.MarshalIndent(config, "", "  ")
+	return string(data)
+}
+
+func trimScheme(r string) string {
+	r = strings.TrimPrefix(r, "https://")
+	r = strings.TrimPrefix(r, "http://")
+	return strings.TrimSuffix(r, "/")
+}
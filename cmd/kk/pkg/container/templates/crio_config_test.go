@@ -0,0 +1,98 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/registry"
+)
+
+func TestCrioRegistries(t *testing.T) {
+	kubeConf := &common.KubeConf{Cluster: &kubekeyapiv1alpha2.ClusterSpec{
+		Registry: kubekeyapiv1alpha2.RegistryConfig{
+			RegistryMirrors:    []string{"https://mirror.example.com/", "http://10.0.0.1:5000"},
+			InsecureRegistries: []string{"http://insecure.example.com"},
+		},
+	}}
+	auths := map[string]*registry.DockerRegistryEntry{
+		"dockerhub.kubekey.local": {Username: "admin", Password: "Harbor12345", SkipTLSVerify: true},
+		"secure.example.com":      {Username: "user", Password: "pass"},
+	}
+
+	var buf bytes.Buffer
+	if err := CrioRegistriesConfig.Execute(&buf, map[string]interface{}{
+		"Registries": CrioRegistries(kubeConf, auths),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := `unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+prefix = "docker.io"
+location = "registry-1.docker.io"
+insecure = false
+
+[[registry.mirror]]
+location = "mirror.example.com"
+insecure = false
+
+[[registry.mirror]]
+location = "10.0.0.1:5000"
+insecure = true
+
+[[registry]]
+prefix = "dockerhub.kubekey.local"
+location = "dockerhub.kubekey.local"
+insecure = true
+
+[[registry]]
+prefix = "insecure.example.com"
+location = "insecure.example.com"
+insecure = true
+`
+	if got := buf.String(); strings.TrimSpace(got) != strings.TrimSpace(want) {
+		t.Errorf("registries.conf:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCrioAuths(t *testing.T) {
+	got := CrioAuths(map[string]*registry.DockerRegistryEntry{
+		"https://dockerhub.kubekey.local": {Username: "admin", Password: "Harbor12345"},
+		"anonymous.example.com":           {},
+	})
+
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(got), &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Auths) != 1 {
+		t.Fatalf("auths = %v, want only dockerhub.kubekey.local", config.Auths)
+	}
+	if auth := config.Auths["dockerhub.kubekey.local"].Auth; auth != "YWRtaW46SGFyYm9yMTIzNDU=" {
+		t.Errorf("auth = %s, want the base64 of admin:Harbor12345", auth)
+	}
+}
@@ -0,0 +1,33 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+)
+
+// PostHook writes the result of a module into the checkpoint journal.
+type PostHook struct {
+	module.PostHook
+	Journal *Journal
+	Index   int
+	Kind    string
+}
+
+func (p *PostHook) Try() error {
+	return p.Journal.Record(p.Index, p.Kind, p.Module.GetName(), p.Result)
+}
@@ -0,0 +1,160 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+)
+
+// Journal records the result of every module executed by a pipeline, so that a failed pipeline
+// can be resumed from the first module which has not completed successfully.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+
+	Pipeline   string          `json:"pipeline"`
+	ConfigHash string          `json:"configHash"`
+	Modules    []*ModuleRecord `json:"modules"`
+}
+
+type ModuleRecord struct {
+	Index     int               `json:"index"`
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	Hosts     map[string]string `json:"hosts,omitempty"`
+	Tasks     []*TaskRecord     `json:"tasks,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	StartTime time.Time         `json:"startTime"`
+	EndTime   time.Time         `json:"endTime"`
+}
+
+type TaskRecord struct {
+	Name   string            `json:"name"`
+	Status string            `json:"status"`
+	Hosts  map[string]string `json:"hosts,omitempty"`
+}
+
+// NewJournal returns a journal stored in the given path. When resume is true and the stored journal
+// belongs to the same pipeline and config hash, its records are kept. Otherwise, a new journal is started.
+func NewJournal(path, pipeline, configHash string, resume bool) (*Journal, error) {
+	j := &Journal{
+		path:       path,
+		Pipeline:   pipeline,
+		ConfigHash: configHash,
+		Modules:    make([]*ModuleRecord, 0),
+	}
+
+	if !resume || !util.IsExist(path) {
+		return j, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read checkpoint journal %s failed", path)
+	}
+	stored := &Journal{}
+	if err := json.Unmarshal(content, stored); err != nil {
+		return nil, errors.Wrapf(err, "parse checkpoint journal %s failed", path)
+	}
+	if stored.Pipeline != pipeline || stored.ConfigHash != configHash {
+		return j, nil
+	}
+	j.Modules = stored.Modules
+	return j, nil
+}
+
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Completed returns true if the module at the given position of the pipeline has been recorded as successful.
+func (j *Journal) Completed(index int, kind string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, m := range j.Modules {
+		if m.Index == index && m.Kind == kind {
+			return m.Status == ending.SUCCESS.String()
+		}
+	}
+	return false
+}
+
+// Record stores the result of a module and saves the journal.
+func (j *Journal) Record(index int, kind, name string, result *ending.ModuleResult) error {
+	r := &ModuleRecord{
+		Index:     index,
+		Kind:      kind,
+		Name:      name,
+		Status:    result.Status.String(),
+		Hosts:     make(map[string]string, len(result.HostResults)),
+		Tasks:     make([]*TaskRecord, 0, len(result.TaskResults)),
+		StartTime: result.StartTime,
+		EndTime:   result.EndTime,
+	}
+	if result.CombineResult != nil {
+		r.Error = result.CombineResult.Error()
+	}
+	for host, res := range result.HostResults {
+		r.Hosts[host] = res.GetStatus().String()
+	}
+	for _, t := range result.TaskResults {
+		tr := &TaskRecord{
+			Name:   t.Name,
+			Status: t.Status.String(),
+			Hosts:  make(map[string]string, len(t.ActionResults)),
+		}
+		for _, ac := range t.ActionResults {
+			tr.Hosts[ac.Host.GetName()] = ac.Status.String()
+		}
+		r.Tasks = append(r.Tasks, tr)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	replaced := false
+	for i := range j.Modules {
+		if j.Modules[i].Index == index {
+			j.Modules[i] = r
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		j.Modules = append(j.Modules, r)
+	}
+	return j.save()
+}
+
+func (j *Journal) save() error {
+	content, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal checkpoint journal failed")
+	}
+	if err := util.WriteFile(j.path, content); err != nil {
+		return errors.Wrapf(err, "write checkpoint journal %s failed", j.path)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+)
+
+func TestJournal_Resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints", "test.json")
+
+	j, err := NewJournal(path, "CreateClusterPipeline", "hash", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := &connector.BaseHost{Name: "node1"}
+	success := ending.NewModuleResult()
+	task := ending.NewTaskResult()
+	task.AppendSuccess(host)
+	task.NormalResult()
+	success.AppendTaskResult("Install binaries", task)
+	success.AppendHostResult(task.ActionResults[0])
+	success.NormalResult()
+	if err := j.Record(0, "*binaries.NodeBinariesModule", "NodeBinariesModule", success); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := ending.NewModuleResult()
+	failed.ErrResult(errors.New("join failed"))
+	if err := j.Record(1, "*kubernetes.JoinNodesModule", "JoinNodesModule", failed); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		pipeline   string
+		configHash string
+		resume     bool
+		index      int
+		kind       string
+		want       bool
+	}{
+		{
+			name:       "successful module is completed",
+			pipeline:   "CreateClusterPipeline",
+			configHash: "hash",
+			resume:     true,
+			index:      0,
+			kind:       "*binaries.NodeBinariesModule",
+			want:       true,
+		},
+		{
+			name:       "failed module is not completed",
+			pipeline:   "CreateClusterPipeline",
+			configHash: "hash",
+			resume:     true,
+			index:      1,
+			kind:       "*kubernetes.JoinNodesModule",
+			want:       false,
+		},
+		{
+			name:       "module kind mismatch",
+			pipeline:   "CreateClusterPipeline",
+			configHash: "hash",
+			resume:     true,
+			index:      0,
+			kind:       "*kubernetes.JoinNodesModule",
+			want:       false,
+		},
+		{
+			name:       "config hash changed",
+			pipeline:   "CreateClusterPipeline",
+			configHash: "changed",
+			resume:     true,
+			index:      0,
+			kind:       "*binaries.NodeBinariesModule",
+			want:       false,
+		},
+		{
+			name:       "not resumed",
+			pipeline:   "CreateClusterPipeline",
+			configHash: "hash",
+			resume:     false,
+			index:      0,
+			kind:       "*binaries.NodeBinariesModule",
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewJournal(path, tt.pipeline, tt.configHash, tt.resume)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if completed := got.Completed(tt.index, tt.kind); completed != tt.want {
+				t.Errorf("Completed() = %v, want %v", completed, tt.want)
+			}
+		})
+	}
+}
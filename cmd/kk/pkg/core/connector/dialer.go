@@ -24,8 +24,9 @@ import (
 )
 
 type Dialer struct {
-	lock        sync.Mutex
-	connections map[string]Connection
+	lock           sync.Mutex
+	connections    map[string]Connection
+	hostKeyChecker *HostKeyChecker
 }
 
 func NewDialer() *Dialer {
@@ -50,6 +51,10 @@ func (d *Dialer) Connect(host Host) (Connection, error) {
 			PrivateKey: host.GetPrivateKey(),
 			KeyFile:    host.GetPrivateKeyPath(),
 			Timeout:    time.Duration(host.GetTimeout()) * time.Second,
+
+			HostKey:            host.GetHostKey(),
+			HostKeyFingerprint: host.GetHostKeyFingerprint(),
+			HostKeyChecker:     d.hostKeyChecker,
 		}
 		conn, err = NewConnection(opts)
 		if err != nil {
@@ -61,6 +66,13 @@ func (d *Dialer) Connect(host Host) (Connection, error) {
 	return conn, nil
 }
 
+// SetHostKeyChecker sets the checker used to verify the host keys of the new connections.
+func (d *Dialer) SetHostKeyChecker(c *HostKeyChecker) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.hostKeyChecker = c
+}
+
 func (d *Dialer) Close(host Host) {
 	conn, ok := d.connections[host.GetName()]
 	if !ok {
@@ -0,0 +1,180 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	PlanCommand = "command"
+	PlanFile    = "file"
+	PlanFetch   = "fetch"
+	PlanLocal   = "local"
+	PlanSkip    = "skip"
+	PlanNote    = "note"
+)
+
+// PlanStep is an operation that would be executed on a host.
+type PlanStep struct {
+	Module string
+	Task   string
+	Kind   string
+	Detail string
+}
+
+// DryRun records the operations of a pipeline instead of executing them.
+// The modules and the pre-checks which only read the state of the hosts are still executed,
+// so that the following tasks can be evaluated with the real cluster state.
+type DryRun struct {
+	mu        sync.Mutex
+	executing bool
+	module    string
+	task      string
+	modules   []string
+	hosts     []string
+	steps     map[string][]*PlanStep
+}
+
+func NewDryRun() *DryRun {
+	return &DryRun{
+		modules: make([]string, 0),
+		hosts:   make([]string, 0),
+		steps:   make(map[string][]*PlanStep),
+	}
+}
+
+// SetExecuting marks whether the current module is really executed.
+func (d *DryRun) SetExecuting(executing bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.executing = executing
+}
+
+func (d *DryRun) IsExecuting() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.executing
+}
+
+// SetStep sets the module and the task to which the following records belong.
+func (d *DryRun) SetStep(module, task string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.module = module
+	d.task = task
+}
+
+// RecordModule records how a module of the pipeline is handled, e.g. skip, execute or plan.
+func (d *DryRun) RecordModule(module, status string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.modules = append(d.modules, fmt.Sprintf("[%s] %s", status, module))
+}
+
+// Record appends an operation to the plan of the host.
+func (d *DryRun) Record(host, kind, detail string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.steps[host]; !ok {
+		d.hosts = append(d.hosts, host)
+	}
+	d.steps[host] = append(d.steps[host], &PlanStep{
+		Module: d.module,
+		Task:   d.task,
+		Kind:   kind,
+		Detail: detail,
+	})
+}
+
+// Connection returns a connection which records the operations of the host instead of executing them.
+func (d *DryRun) Connection(host Host) Connection {
+	return &dryRunConnection{dryRun: d, host: host.GetName()}
+}
+
+// Print writes the modules of the pipeline and the operations of each host.
+func (d *DryRun) Print(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fmt.Fprintln(w, "\nModules:")
+	for _, m := range d.modules {
+		fmt.Fprintf(w, "  %s\n", m)
+	}
+
+	for _, host := range d.hosts {
+		fmt.Fprintf(w, "\nHost [%s]:\n", host)
+		var module, task string
+		for _, s := range d.steps[host] {
+			if s.Module != module || s.Task != task {
+				module, task = s.Module, s.Task
+				fmt.Fprintf(w, "  [%s] %s\n", module, task)
+			}
+			fmt.Fprintf(w, "    %s: %s\n", s.Kind, s.Detail)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+type dryRunConnection struct {
+	dryRun *DryRun
+	host   string
+}
+
+func (c *dryRunConnection) Exec(cmd string, _ Host) (string, int, error) {
+	c.dryRun.Record(c.host, PlanCommand, cmd)
+	return "", 0, nil
+}
+
+func (c *dryRunConnection) PExec(cmd string, _ io.Reader, _ io.Writer, _ io.Writer, _ Host) (int, error) {
+	c.dryRun.Record(c.host, PlanCommand, cmd)
+	return 0, nil
+}
+
+func (c *dryRunConnection) Fetch(local, remote string, _ Host) error {
+	c.dryRun.Record(c.host, PlanFetch, fmt.Sprintf("%s -> %s", remote, local))
+	return nil
+}
+
+func (c *dryRunConnection) Scp(local, remote string, _ Host) error {
+	c.dryRun.Record(c.host, PlanFile, fmt.Sprintf("%s <- %s", remote, local))
+	return nil
+}
+
+func (c *dryRunConnection) RemoteFileExist(_ string, _ Host) bool {
+	return false
+}
+
+func (c *dryRunConnection) RemoteDirExist(_ string, _ Host) (bool, error) {
+	return false, nil
+}
+
+func (c *dryRunConnection) MkDirAll(path string, _ string, _ Host) error {
+	c.dryRun.Record(c.host, PlanCommand, fmt.Sprintf("mkdir -p %s", path))
+	return nil
+}
+
+func (c *dryRunConnection) Chmod(path string, mode os.FileMode) error {
+	c.dryRun.Record(c.host, PlanCommand, fmt.Sprintf("chmod %o %s", mode, path))
+	return nil
+}
+
+func (c *dryRunConnection) Close() {
+}
@@ -0,0 +1,57 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDryRun_Record(t *testing.T) {
+	d := NewDryRun()
+	d.RecordModule("*kubernetes.InitKubernetesModule", "plan")
+
+	node1 := &BaseHost{Name: "node1"}
+	node2 := &BaseHost{Name: "node2"}
+	d.SetStep("InitKubernetesModule", "Generate kubeadm config")
+	conn := d.Connection(node1)
+	if _, _, err := conn.Exec("systemctl restart kubelet", node1); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Scp("/tmp/kubeadm-config.yaml", "/etc/kubernetes/kubeadm-config.yaml", node1); err != nil {
+		t.Fatal(err)
+	}
+	d.SetStep("InitKubernetesModule", "Init cluster using kubeadm")
+	d.Record(node2.GetName(), PlanSkip, "pre-check is not satisfied")
+
+	var out bytes.Buffer
+	d.Print(&out)
+	for _, want := range []string{
+		"[plan] *kubernetes.InitKubernetesModule",
+		"Host [node1]:",
+		"[InitKubernetesModule] Generate kubeadm config",
+		"command: systemctl restart kubelet",
+		"file: /etc/kubernetes/kubeadm-config.yaml <- /tmp/kubeadm-config.yaml",
+		"Host [node2]:",
+		"skip: pre-check is not satisfied",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Print() = %s, want it contains %q", out.String(), want)
+		}
+	}
+}
@@ -21,16 +21,21 @@ import (
 )
 
 type BaseHost struct {
-	Name            string `yaml:"name,omitempty" json:"name,omitempty"`
-	Address         string `yaml:"address,omitempty" json:"address,omitempty"`
-	InternalAddress string `yaml:"internalAddress,omitempty" json:"internalAddress,omitempty"`
-	Port            int    `yaml:"port,omitempty" json:"port,omitempty"`
-	User            string `yaml:"user,omitempty" json:"user,omitempty"`
-	Password        string `yaml:"password,omitempty" json:"password,omitempty"`
-	PrivateKey      string `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
-	PrivateKeyPath  string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
-	Arch            string `yaml:"arch,omitempty" json:"arch,omitempty"`
-	Timeout         int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Name               string `yaml:"name,omitempty" json:"name,omitempty"`
+	Address            string `yaml:"address,omitempty" json:"address,omitempty"`
+	InternalAddress    string `yaml:"internalAddress,omitempty" json:"internalAddress,omitempty"`
+	Port               int    `yaml:"port,omitempty" json:"port,omitempty"`
+	User               string `yaml:"user,omitempty" json:"user,omitempty"`
+	Password           string `yaml:"password,omitempty" json:"password,omitempty"`
+	PrivateKey         string `yaml:"privateKey,omitempty" json:"privateKey,omitempty"`
+	PrivateKeyPath     string `yaml:"privateKeyPath,omitempty" json:"privateKeyPath,omitempty"`
+	HostKey            string `yaml:"hostKey,omitempty" json:"hostKey,omitempty"`
+	HostKeyFingerprint string `yaml:"hostKeyFingerprint,omitempty" json:"hostKeyFingerprint,omitempty"`
+	Arch               string `yaml:"arch,omitempty" json:"arch,omitempty"`
+	Timeout            int64  `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// InternalIPv6Address is the IPv6 address of the host in a dual-stack cluster.
+	InternalIPv6Address string `yaml:"internalIPv6Address,omitempty" json:"internalIPv6Address,omitempty"`
 
 	Roles     []string        `json:"-"`
 	RoleTable map[string]bool `json:"-"`
@@ -69,6 +74,14 @@ func (b *BaseHost) SetInternalAddress(str string) {
 	b.InternalAddress = str
 }
 
+func (b *BaseHost) GetInternalIPv6Address() string {
+	return b.InternalIPv6Address
+}
+
+func (b *BaseHost) SetInternalIPv6Address(str string) {
+	b.InternalIPv6Address = str
+}
+
 func (b *BaseHost) GetPort() int {
 	return b.Port
 }
@@ -109,6 +122,22 @@ func (b *BaseHost) SetPrivateKeyPath(path string) {
 	b.PrivateKeyPath = path
 }
 
+func (b *BaseHost) GetHostKey() string {
+	return b.HostKey
+}
+
+func (b *BaseHost) SetHostKey(key string) {
+	b.HostKey = key
+}
+
+func (b *BaseHost) GetHostKeyFingerprint() string {
+	return b.HostKeyFingerprint
+}
+
+func (b *BaseHost) SetHostKeyFingerprint(fingerprint string) {
+	b.HostKeyFingerprint = fingerprint
+}
+
 func (b *BaseHost) GetArch() string {
 	return b.Arch
 }
@@ -0,0 +1,200 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	"github.com/kubesphere/kubekey/v3/util/hostkey"
+)
+
+const (
+	// HostKeyCheckingInsecure accepts any host key.
+	HostKeyCheckingInsecure = "insecure"
+	// HostKeyCheckingTOFU records the host key on first contact and aborts when a known key mismatches.
+	HostKeyCheckingTOFU = "tofu"
+	// HostKeyCheckingStrict aborts when the host key is unknown or mismatches.
+	HostKeyCheckingStrict = "strict"
+)
+
+// HostKeyChecker verifies the ssh host keys against the pinned key of a host, the per-cluster
+// known_hosts file and the user's ~/.ssh/known_hosts.
+type HostKeyChecker struct {
+	mu                 sync.Mutex
+	mode               string
+	knownHostsFile     string
+	userKnownHostsFile string
+}
+
+// NewHostKeyChecker returns a HostKeyChecker. The knownHostsFile is the per-cluster file where the
+// keys accepted in TOFU mode are recorded.
+func NewHostKeyChecker(mode, knownHostsFile string) (*HostKeyChecker, error) {
+	switch mode {
+	case "":
+		mode = HostKeyCheckingTOFU
+	case HostKeyCheckingInsecure, HostKeyCheckingTOFU, HostKeyCheckingStrict:
+	default:
+		return nil, fmt.Errorf("unsupported host key checking mode [%s], it should be one of %s, %s or %s",
+			mode, HostKeyCheckingInsecure, HostKeyCheckingTOFU, HostKeyCheckingStrict)
+	}
+
+	c := &HostKeyChecker{
+		mode:           mode,
+		knownHostsFile: knownHostsFile,
+	}
+	if home, err := util.Home(); err == nil {
+		c.userKnownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	return c, nil
+}
+
+// Callback returns the ssh.HostKeyCallback for a host. The hostKey is a public key in the authorized_keys
+// format and the fingerprint is a SHA256 or MD5 fingerprint, both of them are optional. A pinned key is always
+// verified, even if the checker is nil or the mode is insecure.
+func (c *HostKeyChecker) Callback(hostKey, fingerprint string) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if hostKey != "" || fingerprint != "" {
+			return hostkey.Verify(hostname, key, hostKey, fingerprint)
+		}
+		if c == nil || c.mode == HostKeyCheckingInsecure {
+			return nil
+		}
+		return c.verifyKnownHosts(hostname, remote, key)
+	}
+}
+
+// HostKeyAlgorithms returns the host key algorithms of the key pinned or known for the host, so that the server
+// presents a key which can be verified instead of another type, as ssh does. The address is the host and port which
+// is dialed. It is nil if no key is known, the default algorithms of the ssh client are used then.
+func (c *HostKeyChecker) HostKeyAlgorithms(address, hostKey, fingerprint string) []string {
+	if hostKey != "" || fingerprint != "" {
+		return hostkey.PinnedAlgorithms(hostKey)
+	}
+	if c == nil || c.mode == HostKeyCheckingInsecure {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files := c.knownHostsFiles()
+	if len(files) == 0 {
+		return nil
+	}
+	callback, err := knownhosts.New(files...)
+	if err != nil {
+		return nil
+	}
+	// the lookup key is never known, so the error lists all the keys known for the host
+	var keyErr *knownhosts.KeyError
+	if err := callback(address, &net.TCPAddr{}, lookupKey{}); !errors.As(err, &keyErr) || len(keyErr.Want) == 0 {
+		return nil
+	}
+	keys := make([]ssh.PublicKey, 0, len(keyErr.Want))
+	for _, want := range keyErr.Want {
+		keys = append(keys, want.Key)
+	}
+	return hostkey.Algorithms(keys...)
+}
+
+func (c *HostKeyChecker) knownHostsFiles() []string {
+	files := make([]string, 0, 2)
+	for _, f := range []string{c.knownHostsFile, c.userKnownHostsFile} {
+		if f != "" && util.IsExist(f) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (c *HostKeyChecker) verifyKnownHosts(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if files := c.knownHostsFiles(); len(files) > 0 {
+		callback, err := knownhosts.New(files...)
+		if err != nil {
+			return errors.Wrap(err, "failed to load known_hosts files")
+		}
+		err = callback(hostname, remote, key)
+		if err == nil {
+			return nil
+		}
+
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			want := keyErr.Want[0]
+			return fmt.Errorf("host key mismatch for %s: got %s, want %s (%s:%d), the host may have been reinstalled or "+
+				"the connection is intercepted", hostname, ssh.FingerprintSHA256(key), ssh.FingerprintSHA256(want.Key),
+				want.Filename, want.Line)
+		}
+	}
+
+	if c.mode == HostKeyCheckingStrict {
+		return fmt.Errorf("unknown host key %s for %s, add it to %s or set the hostKeyFingerprint of the host",
+			ssh.FingerprintSHA256(key), hostname, c.knownHostsFile)
+	}
+	return c.record(hostname, key)
+}
+
+func (c *HostKeyChecker) record(hostname string, key ssh.PublicKey) error {
+	if c.knownHostsFile == "" {
+		return nil
+	}
+	if err := util.MkFileFullPathDir(c.knownHostsFile); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.knownHostsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, common.FileMode0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open known_hosts file %s", c.knownHostsFile)
+	}
+	defer f.Close()
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return errors.Wrapf(err, "failed to record the host key of %s", hostname)
+	}
+	return nil
+}
+
+// lookupKey is a host key which is never known, it is used to look up the keys known for a host.
+type lookupKey struct{}
+
+func (lookupKey) Type() string {
+	return ""
+}
+
+func (lookupKey) Marshal() []byte {
+	return nil
+}
+
+func (lookupKey) Verify(_ []byte, _ *ssh.Signature) error {
+	return errors.New("the lookup key can not verify a signature")
+}
@@ -0,0 +1,144 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package connector
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func newTestHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestHostKeyChecker_Callback(t *testing.T) {
+	key := newTestHostKey(t)
+	other := newTestHostKey(t)
+	remote := &net.TCPAddr{IP: net.ParseIP("192.168.0.2"), Port: 22}
+	hostname := "192.168.0.2:22"
+
+	t.Run("pinned key", func(t *testing.T) {
+		var c *HostKeyChecker
+		pinned := string(ssh.MarshalAuthorizedKey(key))
+		if err := c.Callback(pinned, "")(hostname, remote, key); err != nil {
+			t.Errorf("Callback() unexpected error: %v", err)
+		}
+		if err := c.Callback(pinned, "")(hostname, remote, other); err == nil {
+			t.Errorf("Callback() expected a mismatch error")
+		}
+	})
+
+	t.Run("pinned fingerprint", func(t *testing.T) {
+		var c *HostKeyChecker
+		if err := c.Callback("", ssh.FingerprintSHA256(key))(hostname, remote, key); err != nil {
+			t.Errorf("Callback() unexpected error: %v", err)
+		}
+		if err := c.Callback("", ssh.FingerprintLegacyMD5(key))(hostname, remote, key); err != nil {
+			t.Errorf("Callback() unexpected error: %v", err)
+		}
+		if err := c.Callback("", ssh.FingerprintSHA256(key))(hostname, remote, other); err == nil {
+			t.Errorf("Callback() expected a mismatch error")
+		}
+	})
+
+	t.Run("tofu", func(t *testing.T) {
+		c, err := NewHostKeyChecker(HostKeyCheckingTOFU, filepath.Join(t.TempDir(), "known_hosts", "cluster"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.userKnownHostsFile = ""
+
+		if err := c.Callback("", "")(hostname, remote, key); err != nil {
+			t.Errorf("Callback() unexpected error on first contact: %v", err)
+		}
+		if err := c.Callback("", "")(hostname, remote, key); err != nil {
+			t.Errorf("Callback() unexpected error with the recorded key: %v", err)
+		}
+		if err := c.Callback("", "")(hostname, remote, other); err == nil {
+			t.Errorf("Callback() expected a mismatch error")
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		c, err := NewHostKeyChecker(HostKeyCheckingStrict, filepath.Join(t.TempDir(), "known_hosts", "cluster"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.userKnownHostsFile = ""
+
+		if err := c.Callback("", "")(hostname, remote, key); err == nil {
+			t.Errorf("Callback() expected an unknown host key error")
+		}
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		c, err := NewHostKeyChecker(HostKeyCheckingInsecure, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Callback("", "")(hostname, remote, key); err != nil {
+			t.Errorf("Callback() unexpected error: %v", err)
+		}
+	})
+}
+
+func TestHostKeyChecker_HostKeyAlgorithms(t *testing.T) {
+	key := newTestHostKey(t)
+	address := "192.168.0.2:22"
+
+	c, err := NewHostKeyChecker(HostKeyCheckingTOFU, filepath.Join(t.TempDir(), "known_hosts", "cluster"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.userKnownHostsFile = filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(address)}, key)
+	if err := os.WriteFile(c.userKnownHostsFile, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.HostKeyAlgorithms(address, "", ""); !reflect.DeepEqual(got, []string{ssh.KeyAlgoED25519}) {
+		t.Errorf("HostKeyAlgorithms() = %v, want the algorithm of the known ed25519 key", got)
+	}
+	if got := c.HostKeyAlgorithms("192.168.0.3:22", "", ""); got != nil {
+		t.Errorf("HostKeyAlgorithms() = %v for an unknown host, want nil", got)
+	}
+	if got := c.HostKeyAlgorithms(address, "", ssh.FingerprintSHA256(key)); got != nil {
+		t.Errorf("HostKeyAlgorithms() = %v for a pinned fingerprint, want nil", got)
+	}
+
+	var insecure *HostKeyChecker
+	pinned := string(ssh.MarshalAuthorizedKey(key))
+	if got := insecure.HostKeyAlgorithms(address, pinned, ""); !reflect.DeepEqual(got, []string{ssh.KeyAlgoED25519}) {
+		t.Errorf("HostKeyAlgorithms() = %v, want the algorithm of the pinned key", got)
+	}
+}
@@ -18,6 +18,7 @@ package connector
 
 import (
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/cache"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/event"
 	"io"
 	"os"
 )
@@ -52,6 +53,12 @@ type ModuleRuntime interface {
 	DeleteHost(host Host)
 	HostIsDeprecated(host Host) bool
 	InitLogger() error
+	GetDryRun() *DryRun
+	SetDryRun(d *DryRun)
+	GetEvents() *event.Recorder
+	SetEvents(r *event.Recorder)
+	GetNoRollback() bool
+	SetNoRollback(noRollback bool)
 }
 
 type Runtime interface {
@@ -71,6 +78,8 @@ type Host interface {
 	SetAddress(str string)
 	GetInternalAddress() string
 	SetInternalAddress(str string)
+	GetInternalIPv6Address() string
+	SetInternalIPv6Address(str string)
 	GetPort() int
 	SetPort(port int)
 	GetUser() string
@@ -81,6 +90,10 @@ type Host interface {
 	SetPrivateKey(privateKey string)
 	GetPrivateKeyPath() string
 	SetPrivateKeyPath(path string)
+	GetHostKey() string
+	SetHostKey(key string)
+	GetHostKeyFingerprint() string
+	SetHostKeyFingerprint(fingerprint string)
 	GetArch() string
 	SetArch(arch string)
 	GetTimeout() int64
@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/event"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
 )
@@ -38,6 +39,9 @@ type BaseRuntime struct {
 	allHosts        []Host
 	roleHosts       map[string][]Host
 	deprecatedHosts map[string]string
+	dryRun          *DryRun
+	events          *event.Recorder
+	noRollback      bool
 }
 
 func NewBaseRuntime(name string, connector Connector, verbose bool, ignoreErr bool) BaseRuntime {
@@ -182,6 +186,35 @@ func (b *BaseRuntime) InitLogger() error {
 	return nil
 }
 
+// GetDryRun returns the recorder of the dry-run mode, it is nil if the pipeline is really executed.
+func (b *BaseRuntime) GetDryRun() *DryRun {
+	return b.dryRun
+}
+
+func (b *BaseRuntime) SetDryRun(d *DryRun) {
+	b.dryRun = d
+}
+
+// GetEvents returns the recorder of the pipeline events, it is nil if neither the events nor the summary report
+// are required.
+func (b *BaseRuntime) GetEvents() *event.Recorder {
+	return b.events
+}
+
+func (b *BaseRuntime) SetEvents(r *event.Recorder) {
+	b.events = r
+}
+
+// GetNoRollback reports whether the rollbacks of the failed tasks are disabled, the failed hosts are kept
+// as they are for debugging.
+func (b *BaseRuntime) GetNoRollback() bool {
+	return b.noRollback
+}
+
+func (b *BaseRuntime) SetNoRollback(noRollback bool) {
+	b.noRollback = noRollback
+}
+
 func (b *BaseRuntime) Copy() Runtime {
 	runtime := *b
 	return &runtime
@@ -53,6 +53,10 @@ type Cfg struct {
 	Bastion     string
 	BastionPort int
 	BastionUser string
+
+	HostKey            string
+	HostKeyFingerprint string
+	HostKeyChecker     *HostKeyChecker
 }
 
 const socketEnvPrefix = "env:"
@@ -116,19 +120,23 @@ func NewConnection(cfg Cfg) (Connection, error) {
 		User:            cfg.Username,
 		Timeout:         cfg.Timeout,
 		Auth:            authMethods,
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		HostKeyCallback: cfg.HostKeyChecker.Callback(cfg.HostKey, cfg.HostKeyFingerprint),
 	}
 
 	targetHost := cfg.Address
 	targetPort := strconv.Itoa(cfg.Port)
+	targetHostKey, targetFingerprint := cfg.HostKey, cfg.HostKeyFingerprint
 
 	if cfg.Bastion != "" {
 		targetHost = cfg.Bastion
 		targetPort = strconv.Itoa(cfg.BastionPort)
+		targetHostKey, targetFingerprint = "", ""
 		sshConfig.User = cfg.BastionUser
+		sshConfig.HostKeyCallback = cfg.HostKeyChecker.Callback("", "")
 	}
 
 	endpoint := net.JoinHostPort(targetHost, targetPort)
+	sshConfig.HostKeyAlgorithms = cfg.HostKeyChecker.HostKeyAlgorithms(endpoint, targetHostKey, targetFingerprint)
 
 	client, err := ssh.Dial("tcp", endpoint, sshConfig)
 	if err != nil {
@@ -159,6 +167,8 @@ func NewConnection(cfg Cfg) (Connection, error) {
 	}
 
 	sshConfig.User = cfg.Username
+	sshConfig.HostKeyCallback = cfg.HostKeyChecker.Callback(cfg.HostKey, cfg.HostKeyFingerprint)
+	sshConfig.HostKeyAlgorithms = cfg.HostKeyChecker.HostKeyAlgorithms(endpointBehindBastion, cfg.HostKey, cfg.HostKeyFingerprint)
 	ncc, chans, reqs, err := ssh.NewClientConn(conn, endpointBehindBastion, sshConfig)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not establish connection to %s", endpointBehindBastion)
@@ -28,6 +28,7 @@ import (
 
 type TaskResult struct {
 	mu            sync.Mutex
+	Name          string
 	ActionResults []*ActionResult
 	Status        ResultStatus
 	StartTime     time.Time
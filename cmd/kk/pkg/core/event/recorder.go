@@ -0,0 +1,242 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package event
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+)
+
+const (
+	PipelineStart = "pipeline_start"
+	PipelineEnd   = "pipeline_end"
+	ModuleStart   = "module_start"
+	ModuleEnd     = "module_end"
+	TaskResult    = "task_result"
+	Result        = "result"
+
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+
+	// maxErrorLength is the max length of the error excerpt in an event, the tail of the error is kept
+	// because the stderr of the failed command is appended to the end.
+	maxErrorLength = 2048
+)
+
+// Event is a structured progress event of a pipeline, it is written as a JSON line.
+type Event struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Pipeline string    `json:"pipeline"`
+	Module   string    `json:"module,omitempty"`
+	Task     string    `json:"task,omitempty"`
+	Host     string    `json:"host,omitempty"`
+	Status   string    `json:"status,omitempty"`
+	Duration float64   `json:"durationSeconds,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	// Payload is the result of the command in a result event, such as the status report of a cluster.
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// HostResult is the result of a task on a host.
+type HostResult struct {
+	Host     string  `json:"host"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"durationSeconds"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// Recorder writes the events of the pipelines and collects the summary report of the last pipeline.
+type Recorder struct {
+	mu         sync.Mutex
+	out        io.Writer
+	reportPath string
+	report     *Report
+	module     *ModuleReport
+}
+
+// NewRecorder returns a Recorder. The events are written to out if it is not nil, and the summary report is
+// written to reportPath at the end of the pipeline if it is not empty.
+func NewRecorder(out io.Writer, reportPath string) *Recorder {
+	return &Recorder{
+		out:        out,
+		reportPath: reportPath,
+	}
+}
+
+// Streaming reports whether the events are written, the human-readable output should be suppressed then.
+func (r *Recorder) Streaming() bool {
+	return r != nil && r.out != nil
+}
+
+// PromptOutput returns the writer of the confirmation prompts. They are written to stderr when the events are
+// streamed to stdout, so that the JSON output is not corrupted.
+func (r *Recorder) PromptOutput() io.Writer {
+	if r.Streaming() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func (r *Recorder) PipelineStart(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.report = &Report{
+		Pipeline:  name,
+		StartTime: now,
+		Modules:   make([]*ModuleReport, 0),
+	}
+	r.module = nil
+	r.emit(&Event{Type: PipelineStart, Time: now})
+}
+
+func (r *Recorder) PipelineEnd(err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.report.EndTime = now
+	r.report.Duration = now.Sub(r.report.StartTime).Seconds()
+	r.report.Status, r.report.Error = status(err)
+	r.emit(&Event{
+		Type:     PipelineEnd,
+		Time:     now,
+		Status:   r.report.Status,
+		Duration: r.report.Duration,
+		Error:    r.report.Error,
+	})
+
+	if r.reportPath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(r.report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal the summary report failed")
+	}
+	if err := util.WriteFile(r.reportPath, content); err != nil {
+		return errors.Wrapf(err, "write the summary report %s failed", r.reportPath)
+	}
+	return nil
+}
+
+func (r *Recorder) ModuleStart(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.module = &ModuleReport{
+		Name:      name,
+		StartTime: time.Now(),
+		Tasks:     make([]*TaskReport, 0),
+	}
+	r.report.Modules = append(r.report.Modules, r.module)
+	r.emit(&Event{Type: ModuleStart, Time: r.module.StartTime, Module: name})
+}
+
+func (r *Recorder) ModuleEnd(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	e := &Event{Type: ModuleEnd, Time: now, Module: name}
+	e.Status, e.Error = status(err)
+	if r.module != nil && r.module.Name == name {
+		r.module.Status, r.module.Error = e.Status, e.Error
+		r.module.Duration = now.Sub(r.module.StartTime).Seconds()
+		e.Duration = r.module.Duration
+	}
+	r.emit(e)
+}
+
+// TaskEnd records the result of a task on each host.
+func (r *Recorder) TaskEnd(module, task, status string, duration float64, hosts []HostResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range hosts {
+		hosts[i].Error = excerpt(hosts[i].Error)
+		r.emit(&Event{
+			Type:     TaskResult,
+			Time:     time.Now(),
+			Module:   module,
+			Task:     task,
+			Host:     hosts[i].Host,
+			Status:   hosts[i].Status,
+			Duration: hosts[i].Duration,
+			Error:    hosts[i].Error,
+		})
+	}
+	if r.module != nil {
+		r.module.Tasks = append(r.module.Tasks, &TaskReport{
+			Name:     task,
+			Status:   status,
+			Duration: duration,
+			Hosts:    hosts,
+		})
+	}
+}
+
+// Result writes the result of the command after the pipeline, the payload is printed instead of the human-readable
+// result when the events are streamed.
+func (r *Recorder) Result(payload interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.emit(&Event{Type: Result, Time: time.Now(), Payload: payload})
+}
+
+func (r *Recorder) emit(e *Event) {
+	if r.out == nil {
+		return
+	}
+	if r.report != nil {
+		e.Pipeline = r.report.Pipeline
+	}
+	content, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, _ = r.out.Write(append(content, '\n'))
+}
+
+func status(err error) (string, string) {
+	if err != nil {
+		return StatusFailed, excerpt(err.Error())
+	}
+	return StatusSuccess, ""
+}
+
+func excerpt(s string) string {
+	if len(s) <= maxErrorLength {
+		return s
+	}
+	// the excerpt starts at a rune boundary, so that a multi-byte character is not split
+	i := len(s) - maxErrorLength
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return "..." + s[i:]
+}
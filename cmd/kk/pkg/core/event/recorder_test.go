@@ -0,0 +1,103 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRecorder(t *testing.T) {
+	var out bytes.Buffer
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	r := NewRecorder(&out, reportPath)
+
+	r.PipelineStart("CreateClusterPipeline")
+	r.ModuleStart("JoinNodesModule")
+	r.TaskEnd("JoinNodesModule", "Join node into cluster", StatusFailed, 1.5, []HostResult{
+		{Host: "node1", Status: StatusSuccess, Duration: 1},
+		{Host: "node2", Status: StatusFailed, Duration: 1.5, Error: strings.Repeat("x", maxErrorLength) + "stderr"},
+	})
+	r.ModuleEnd("JoinNodesModule", errors.New("join failed"))
+	if err := r.PipelineEnd(errors.New("join failed")); err != nil {
+		t.Fatal(err)
+	}
+	r.Result(map[string]bool{"healthy": false})
+
+	var events []Event
+	scanner := bufio.NewScanner(&out)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid event %s: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+
+	wantTypes := []string{PipelineStart, ModuleStart, TaskResult, TaskResult, ModuleEnd, PipelineEnd, Result}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d", len(events), len(wantTypes))
+	}
+	for i, e := range events {
+		if e.Type != wantTypes[i] {
+			t.Errorf("events[%d].Type = %s, want %s", i, e.Type, wantTypes[i])
+		}
+		if e.Pipeline != "CreateClusterPipeline" {
+			t.Errorf("events[%d].Pipeline = %s, want CreateClusterPipeline", i, e.Pipeline)
+		}
+	}
+	if failed := events[3]; failed.Host != "node2" || failed.Status != StatusFailed ||
+		!strings.HasSuffix(failed.Error, "stderr") || len(failed.Error) != maxErrorLength+3 {
+		t.Errorf("unexpected task result event: %+v", failed)
+	}
+	if payload, ok := events[6].Payload.(map[string]interface{}); !ok || payload["healthy"] != false {
+		t.Errorf("unexpected result event: %+v", events[6])
+	}
+
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &Report{}
+	if err := json.Unmarshal(content, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusFailed || len(report.Modules) != 1 || len(report.Modules[0].Tasks) != 1 ||
+		len(report.Modules[0].Tasks[0].Hosts) != 2 {
+		t.Errorf("unexpected report: %s", content)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	// the cut falls in the middle of the 3-byte characters
+	s := strings.Repeat("错", maxErrorLength)
+	got := excerpt(s)
+	if !utf8.ValidString(got) {
+		t.Errorf("excerpt() = %q is not valid UTF-8", got)
+	}
+	if len(got) > maxErrorLength+3 || !strings.HasSuffix(s, strings.TrimPrefix(got, "...")) {
+		t.Errorf("excerpt() = %q is not the tail of the error", got)
+	}
+}
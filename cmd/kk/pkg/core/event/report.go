@@ -0,0 +1,48 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package event
+
+import (
+	"time"
+)
+
+// Report is the summary report of a pipeline.
+type Report struct {
+	Pipeline  string          `json:"pipeline"`
+	Status    string          `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	StartTime time.Time       `json:"startTime"`
+	EndTime   time.Time       `json:"endTime"`
+	Duration  float64         `json:"durationSeconds"`
+	Modules   []*ModuleReport `json:"modules"`
+}
+
+type ModuleReport struct {
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	StartTime time.Time     `json:"startTime"`
+	Duration  float64       `json:"durationSeconds"`
+	Tasks     []*TaskReport `json:"tasks"`
+}
+
+type TaskReport struct {
+	Name     string       `json:"name"`
+	Status   string       `json:"status"`
+	Duration float64      `json:"durationSeconds"`
+	Hosts    []HostResult `json:"hosts"`
+}
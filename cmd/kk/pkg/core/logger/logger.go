@@ -18,6 +18,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 	"time"
 
@@ -74,6 +75,13 @@ func NewLogger(outputPath string, verbose bool) *KubeKeyLog {
 	return &KubeKeyLog{logger, outputPath, verbose}
 }
 
+// SetConsoleOutput sets the writer of the console output, the log file is not affected.
+func (k *KubeKeyLog) SetConsoleOutput(w io.Writer) {
+	if l, ok := k.FieldLogger.(*logrus.Logger); ok {
+		l.SetOutput(w)
+	}
+}
+
 func (k *KubeKeyLog) Message(node, str string) {
 	Log.Infof("message: [%s]\n%s", node, str)
 }
@@ -35,6 +35,10 @@ type BaseModule struct {
 	PostHook      []PostHookInterface
 }
 
+func (b *BaseModule) GetName() string {
+	return b.Name
+}
+
 func (b *BaseModule) IsSkip() bool {
 	return b.Skip
 }
@@ -23,6 +23,7 @@ import (
 )
 
 type Module interface {
+	GetName() string
 	IsSkip() bool
 	Default(runtime connector.Runtime, pipelineCache *cache.Cache, moduleCache *cache.Cache)
 	Init()
@@ -34,3 +35,23 @@ type Module interface {
 	AppendPostHook(h PostHookInterface)
 	CallPostHook(result *ending.ModuleResult) error
 }
+
+// Collector is implemented by the modules which only collect state (e.g. cluster status, pre-check results)
+// into the pipeline or host cache. These modules are always executed, even when a resumed pipeline has
+// recorded them as successful.
+type Collector interface {
+	IsCollector() bool
+}
+
+// CacheProducer is implemented by the modules which fill the pipeline cache for the following modules, such as the
+// binaries modules. The pipeline cache is not kept in the checkpoint journal, so these modules are executed again
+// when a resumed pipeline has recorded them as successful. Unlike the collectors, they are only planned by a dry-run.
+type CacheProducer interface {
+	IsCacheProducer() bool
+}
+
+// Keyed is implemented by the modules which are created from the configuration, such as the hooks, so that they
+// are not told apart by their type. The key is used instead of the type to record them in the checkpoint journal.
+type Keyed interface {
+	Key() string
+}
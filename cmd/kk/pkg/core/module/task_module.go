@@ -21,6 +21,7 @@ import (
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/event"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
 )
@@ -44,9 +45,13 @@ func (b *BaseTaskModule) Run(result *ending.ModuleResult) {
 	for i := range b.Tasks {
 		t := b.Tasks[i]
 		t.Init(b.Runtime.(connector.Runtime), b.ModuleCache, b.PipelineCache)
+		if d := b.Runtime.GetDryRun(); d != nil {
+			d.SetStep(b.Name, t.GetDesc())
+		}
 
 		logger.Log.Infof("[%s] %s", b.Name, t.GetDesc())
 		res := t.Execute()
+		result.AppendTaskResult(t.GetDesc(), res)
 		for j := range res.ActionResults {
 			ac := res.ActionResults[j]
 			logger.Log.Infof("%s: [%s]", ac.Status.String(), ac.Host.GetName())
@@ -67,11 +72,43 @@ func (b *BaseTaskModule) Run(result *ending.ModuleResult) {
 			}
 		}
 
+		if r := b.Runtime.GetEvents(); r != nil {
+			r.TaskEnd(b.Name, t.GetDesc(), res.Status.String(), res.EndTime.Sub(res.StartTime).Seconds(), hostResults(res))
+		}
+
 		if res.IsFailed() {
-			t.ExecuteRollback()
+			if b.Runtime.GetNoRollback() {
+				logger.Log.Warnf("[%s] %s: rollback is disabled, the failed hosts are left as they are", b.Name, t.GetDesc())
+			} else {
+				b.rollback(i, res)
+			}
 			result.ErrResult(errors.Wrapf(res.CombineErr(), "Module[%s] exec failed", b.Name))
 			return
 		}
 	}
 	result.NormalResult()
 }
+
+// rollback rolls back the failed task and then the tasks completed before it in the reverse order, so that every step
+// of the module is undone on the failed hosts.
+func (b *BaseTaskModule) rollback(failed int, res *ending.TaskResult) {
+	for i := failed; i >= 0; i-- {
+		b.Tasks[i].ExecuteRollback(res)
+	}
+}
+
+func hostResults(res *ending.TaskResult) []event.HostResult {
+	hosts := make([]event.HostResult, 0, len(res.ActionResults))
+	for _, ac := range res.ActionResults {
+		h := event.HostResult{
+			Host:     ac.Host.GetName(),
+			Status:   ac.Status.String(),
+			Duration: ac.EndTime.Sub(ac.StartTime).Seconds(),
+		}
+		if ac.Error != nil {
+			h.Error = ac.Error.Error()
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
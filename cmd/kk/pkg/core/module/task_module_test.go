@@ -0,0 +1,84 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package module
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/cache"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
+)
+
+type fakeTask struct {
+	desc       string
+	fail       bool
+	rolledBack *[]string
+}
+
+func (f *fakeTask) GetDesc() string {
+	return f.desc
+}
+
+func (f *fakeTask) Init(_ connector.Runtime, _ *cache.Cache, _ *cache.Cache) {
+}
+
+func (f *fakeTask) Execute() *ending.TaskResult {
+	res := ending.NewTaskResult()
+	host := &connector.BaseHost{Name: "node1"}
+	if f.fail {
+		res.AppendErr(host, errors.New("failed"))
+		res.ErrResult()
+	} else {
+		res.AppendSuccess(host)
+		res.NormalResult()
+	}
+	return res
+}
+
+func (f *fakeTask) ExecuteRollback(failed *ending.TaskResult) {
+	if failed.IsFailed() {
+		*f.rolledBack = append(*f.rolledBack, f.desc)
+	}
+}
+
+func TestBaseTaskModule_Rollback(t *testing.T) {
+	runtime := connector.NewBaseRuntime("test", nil, false, false)
+
+	var rolledBack []string
+	m := &BaseTaskModule{}
+	m.Default(&runtime, cache.NewCache(), cache.NewCache())
+	m.Tasks = []task.Interface{
+		&fakeTask{desc: "first", rolledBack: &rolledBack},
+		&fakeTask{desc: "second", rolledBack: &rolledBack},
+		&fakeTask{desc: "third", fail: true, rolledBack: &rolledBack},
+		&fakeTask{desc: "fourth", rolledBack: &rolledBack},
+	}
+
+	result := ending.NewModuleResult()
+	m.Run(result)
+	if !result.IsFailed() {
+		t.Fatal("the module is expected to fail")
+	}
+	if want := []string{"third", "second", "first"}; !reflect.DeepEqual(rolledBack, want) {
+		t.Errorf("the rolled back tasks are %v, want %v", rolledBack, want)
+	}
+}
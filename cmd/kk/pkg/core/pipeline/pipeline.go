@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/cache"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/checkpoint"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
@@ -51,10 +52,13 @@ type Pipeline struct {
 	PipelineCache   *cache.Cache
 	ModuleCachePool sync.Pool
 	ModulePostHooks []module.PostHookInterface
+	Checkpoint      *checkpoint.Journal
 }
 
 func (p *Pipeline) Init() error {
-	fmt.Print(logo)
+	if !p.Runtime.GetEvents().Streaming() {
+		fmt.Print(logo)
+	}
 	p.PipelineCache = cache.NewCache()
 	p.SpecHosts = len(p.Runtime.GetAllHosts())
 	//if err := p.Runtime.GenerateWorkDir(); err != nil {
@@ -66,13 +70,34 @@ func (p *Pipeline) Init() error {
 	return nil
 }
 
-func (p *Pipeline) Start() error {
+func (p *Pipeline) Start() (err error) {
+	if events := p.Runtime.GetEvents(); events != nil {
+		events.PipelineStart(p.Name)
+		defer func() {
+			if reportErr := events.PipelineEnd(err); reportErr != nil {
+				logger.Log.Warnf("Pipeline[%s] %v", p.Name, reportErr)
+			}
+		}()
+	}
+
 	if err := p.Init(); err != nil {
 		return errors.Wrapf(err, "Pipeline[%s] execute failed", p.Name)
 	}
+	dryRun := p.Runtime.GetDryRun()
 	for i := range p.Modules {
 		m := p.Modules[i]
+		kind := moduleKind(m)
 		if m.IsSkip() {
+			if dryRun != nil {
+				dryRun.RecordModule(kind, "skip")
+			}
+			continue
+		}
+		if p.isCompleted(i, kind, m) {
+			logger.Log.Infof("[%s] skipped: completed in the previous execution", kind)
+			if dryRun != nil {
+				dryRun.RecordModule(kind, "completed")
+			}
 			continue
 		}
 
@@ -83,9 +108,32 @@ func (p *Pipeline) Start() error {
 		for j := range p.ModulePostHooks {
 			m.AppendPostHook(p.ModulePostHooks[j])
 		}
+		if dryRun != nil {
+			// the modules which only collect the state of the cluster are really executed,
+			// so that the following modules are planned with the real state.
+			collector := isCollector(m)
+			dryRun.SetExecuting(collector)
+			if collector {
+				dryRun.RecordModule(kind, "execute")
+			} else {
+				dryRun.RecordModule(kind, "plan")
+			}
+		} else if p.Checkpoint != nil {
+			m.AppendPostHook(&checkpoint.PostHook{Journal: p.Checkpoint, Index: i, Kind: kind})
+		}
 
+		if events := p.Runtime.GetEvents(); events != nil {
+			events.ModuleStart(m.GetName())
+		}
 		res := p.RunModule(m)
 		err := m.CallPostHook(res)
+		if events := p.Runtime.GetEvents(); events != nil {
+			if res.IsFailed() {
+				events.ModuleEnd(m.GetName(), res.CombineResult)
+			} else {
+				events.ModuleEnd(m.GetName(), err)
+			}
+		}
 		if res.IsFailed() {
 			return errors.Wrapf(res.CombineResult, "Pipeline[%s] execute failed", p.Name)
 		}
@@ -104,6 +152,16 @@ func (p *Pipeline) Start() error {
 	if p.SpecHosts != len(p.Runtime.GetAllHosts()) {
 		return errors.Errorf("Pipeline[%s] execute failed: there are some error in your spec hosts", p.Name)
 	}
+	if dryRun != nil {
+		if p.Runtime.GetEvents().Streaming() {
+			dryRun.Print(os.Stderr)
+		} else {
+			dryRun.Print(os.Stdout)
+		}
+		logger.Log.Infof("Pipeline[%s] dry-run finished, no changes have been made, the rendered files are kept in %s",
+			p.Name, p.Runtime.GetWorkDir())
+		return nil
+	}
 	logger.Log.Infof("Pipeline[%s] execute successfully", p.Name)
 	return nil
 }
@@ -139,13 +197,43 @@ func (p *Pipeline) RunModule(m module.Module) *ending.ModuleResult {
 			result.LocalErrResult(err)
 			return result
 		}
-		if stop == nil || *stop {
+		// a dry-run never changes the state which the loop waits for, so the module is planned only once.
+		if stop == nil || *stop || p.Runtime.GetDryRun() != nil {
 			break
 		}
 	}
 	return result
 }
 
+// isCompleted reports whether the module has been recorded as successful by the checkpoint journal
+// of a previous execution. Collector and cache producer modules are never treated as completed.
+func (p *Pipeline) isCompleted(index int, kind string, m module.Module) bool {
+	if p.Checkpoint == nil {
+		return false
+	}
+	if isCollector(m) || isCacheProducer(m) {
+		return false
+	}
+	return p.Checkpoint.Completed(index, kind)
+}
+
+func moduleKind(m module.Module) string {
+	if k, ok := m.(module.Keyed); ok {
+		return k.Key()
+	}
+	return fmt.Sprintf("%T", m)
+}
+
+func isCollector(m module.Module) bool {
+	c, ok := m.(module.Collector)
+	return ok && c.IsCollector()
+}
+
+func isCacheProducer(m module.Module) bool {
+	c, ok := m.(module.CacheProducer)
+	return ok && c.IsCacheProducer()
+}
+
 func (p *Pipeline) newModuleCache() *cache.Cache {
 	moduleCache, ok := p.ModuleCachePool.Get().(*cache.Cache)
 	if ok {
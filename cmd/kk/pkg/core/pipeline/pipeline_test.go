@@ -0,0 +1,120 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/action"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/checkpoint"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
+)
+
+const binariesKey = "binaries"
+
+type downloadBinaries struct {
+	action.BaseAction
+	downloads *int
+}
+
+func (d *downloadBinaries) Execute(_ connector.Runtime) error {
+	*d.downloads++
+	d.PipelineCache.Set(binariesKey, "/kubekey/binaries")
+	return nil
+}
+
+// binariesModule fills the pipeline cache like the binaries modules.
+type binariesModule struct {
+	module.BaseTaskModule
+	downloads *int
+}
+
+func (b *binariesModule) IsCacheProducer() bool {
+	return true
+}
+
+func (b *binariesModule) Init() {
+	b.Name = "NodeBinariesModule"
+	b.Tasks = []task.Interface{
+		&task.LocalTask{Name: "DownloadBinaries", Desc: "Download binaries", Action: &downloadBinaries{downloads: b.downloads}},
+	}
+}
+
+type syncBinaries struct {
+	action.BaseAction
+	fail bool
+}
+
+func (s *syncBinaries) Execute(_ connector.Runtime) error {
+	if _, ok := s.PipelineCache.GetMustString(binariesKey); !ok {
+		return errors.New("get KubeBinary by pipeline cache failed")
+	}
+	if s.fail {
+		return errors.New("sync binaries failed")
+	}
+	return nil
+}
+
+type installModule struct {
+	module.BaseTaskModule
+	fail bool
+}
+
+func (i *installModule) Init() {
+	i.Name = "InstallKubeBinariesModule"
+	i.Tasks = []task.Interface{
+		&task.LocalTask{Name: "SyncBinaries", Desc: "Sync binaries", Action: &syncBinaries{fail: i.fail}},
+	}
+}
+
+func TestPipeline_ResumeCacheProducer(t *testing.T) {
+	runtime := connector.NewBaseRuntime("test", nil, false, false)
+	path := filepath.Join(t.TempDir(), "checkpoints", "test.json")
+
+	var downloads int
+	run := func(resume, fail bool) error {
+		journal, err := checkpoint.NewJournal(path, "TestPipeline", "hash", resume)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := Pipeline{
+			Name: "TestPipeline",
+			Modules: []module.Module{
+				&binariesModule{downloads: &downloads},
+				&installModule{fail: fail},
+			},
+			Runtime:    &runtime,
+			Checkpoint: journal,
+		}
+		return p.Start()
+	}
+
+	if err := run(false, true); err == nil {
+		t.Fatal("the first execution is expected to fail after the binaries module")
+	}
+	if err := run(true, false); err != nil {
+		t.Fatalf("the resumed execution failed: %v", err)
+	}
+	if downloads != 2 {
+		t.Errorf("the binaries module is executed %d times, want 2", downloads)
+	}
+}
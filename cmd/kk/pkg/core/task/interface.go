@@ -26,5 +26,7 @@ type Interface interface {
 	GetDesc() string
 	Init(runtime connector.Runtime, moduleCache *cache.Cache, pipelineCache *cache.Cache)
 	Execute() *ending.TaskResult
-	ExecuteRollback()
+	// ExecuteRollback rolls back the task when the module failed at the failed task. The failed task itself is rolled
+	// back on every host it was executed on, a task completed before it only on the hosts the failed task failed on.
+	ExecuteRollback(failed *ending.TaskResult)
 }
@@ -121,14 +121,24 @@ func (l *LocalTask) RunWithTimeout(runtime connector.Runtime, host connector.Hos
 	go l.Run(runtime, host, resCh)
 	select {
 	case <-ctx.Done():
-		l.TaskResult.AppendErr(host, fmt.Errorf("execute task timeout, Timeout=%s", util.ShortDur(l.Timeout)))
+		l.appendErr(host, fmt.Errorf("execute task timeout, Timeout=%s", util.ShortDur(l.Timeout)))
 	case e := <-resCh:
 		if e != nil {
-			l.TaskResult.AppendErr(host, e)
+			l.appendErr(host, e)
 		}
 	}
 }
 
+// appendErr records the error, it is only logged if the task ignores errors.
+func (l *LocalTask) appendErr(host connector.Host, err error) {
+	if l.IgnoreError {
+		logger.Log.Warnf("[%s] the error is ignored: %v", l.Name, err)
+		l.TaskResult.AppendSkip(host)
+		return
+	}
+	l.TaskResult.AppendErr(host, err)
+}
+
 func (l *LocalTask) Run(runtime connector.Runtime, host connector.Host, resCh chan error) {
 	var res error
 	defer func() {
@@ -142,6 +152,11 @@ func (l *LocalTask) Run(runtime connector.Runtime, host connector.Host, resCh ch
 		Host: host,
 	})
 
+	if d := runtime.GetDryRun(); d != nil && !d.IsExecuting() {
+		l.DryRun(runtime, d, host)
+		return
+	}
+
 	l.Prepare.Init(l.ModuleCache, l.PipelineCache)
 	l.Prepare.AutoAssert(runtime)
 	if ok, err := l.WhenWithRetry(runtime, host); !ok {
@@ -163,6 +178,27 @@ func (l *LocalTask) Run(runtime connector.Runtime, host connector.Host, resCh ch
 	l.TaskResult.AppendSuccess(host)
 }
 
+// DryRun evaluates the pre-check and records the local task instead of executing its action.
+func (l *LocalTask) DryRun(runtime connector.Runtime, d *connector.DryRun, host connector.Host) {
+	var ok bool
+	err := plan(func() (err error) {
+		l.Prepare.Init(l.ModuleCache, l.PipelineCache)
+		l.Prepare.AutoAssert(runtime)
+		ok, err = l.When(runtime)
+		return err
+	})
+	if err != nil {
+		d.Record(host.GetName(), connector.PlanNote, fmt.Sprintf("pre-check can not be evaluated: %v", err))
+	} else if !ok {
+		d.Record(host.GetName(), connector.PlanSkip, "pre-check is not satisfied")
+		l.TaskResult.AppendSkip(host)
+		return
+	}
+
+	d.Record(host.GetName(), connector.PlanLocal, l.Name)
+	l.TaskResult.AppendSuccess(host)
+}
+
 func (l *LocalTask) WhenWithRetry(runtime connector.Runtime, host connector.Host) (bool, error) {
 	pass := false
 	err := fmt.Errorf("pre-check exec failed after %d retries", l.Retry)
@@ -221,11 +257,11 @@ func (l *LocalTask) ExecuteWithRetry(runtime connector.Runtime, host connector.H
 	return err
 }
 
-func (l *LocalTask) ExecuteRollback() {
+func (l *LocalTask) ExecuteRollback(failed *ending.TaskResult) {
 	if l.Rollback == nil {
 		return
 	}
-	if !l.TaskResult.IsFailed() {
+	if failed == nil || !failed.IsFailed() {
 		return
 	}
 }
@@ -52,6 +52,9 @@ type RemoteTask struct {
 	Runtime       connector.Runtime
 	IgnoreError   bool
 	TaskResult    *ending.TaskResult
+
+	// executed records the hosts which the action has been executed on, only these hosts are rolled back.
+	executed *sync.Map
 }
 
 func (t *RemoteTask) GetDesc() string {
@@ -110,10 +113,10 @@ func (t *RemoteTask) RunWithTimeout(ctx context.Context, runtime connector.Runti
 
 	select {
 	case <-ctx.Done():
-		t.TaskResult.AppendErr(host, fmt.Errorf("execute task timeout, Timeout=%s", util.ShortDur(t.Timeout)))
+		t.appendErr(host, fmt.Errorf("execute task timeout, Timeout=%s", util.ShortDur(t.Timeout)))
 	case e := <-resCh:
 		if e != nil {
-			t.TaskResult.AppendErr(host, e)
+			t.appendErr(host, e)
 		}
 	}
 
@@ -121,6 +124,17 @@ func (t *RemoteTask) RunWithTimeout(ctx context.Context, runtime connector.Runti
 	wg.Done()
 }
 
+// appendErr records the error of the host. The error is only logged if the task ignores errors, so that the
+// pipeline goes on.
+func (t *RemoteTask) appendErr(host connector.Host, err error) {
+	if t.IgnoreError {
+		logger.Log.Warnf("[%s] the error on %s is ignored: %v", t.Name, host.GetName(), err)
+		t.TaskResult.AppendSkip(host)
+		return
+	}
+	t.TaskResult.AppendErr(host, err)
+}
+
 func (t *RemoteTask) Run(runtime connector.Runtime, host connector.Host, index int, resCh chan error) {
 	var res error
 	defer func() {
@@ -135,6 +149,11 @@ func (t *RemoteTask) Run(runtime connector.Runtime, host connector.Host, index i
 		return
 	}
 
+	if d := runtime.GetDryRun(); d != nil && !d.IsExecuting() {
+		t.DryRun(runtime, d, host)
+		return
+	}
+
 	t.Prepare.Init(t.ModuleCache, t.PipelineCache)
 	t.Prepare.AutoAssert(runtime)
 	if ok, err := t.WhenWithRetry(runtime); !ok {
@@ -147,6 +166,7 @@ func (t *RemoteTask) Run(runtime connector.Runtime, host connector.Host, index i
 		}
 	}
 
+	t.executed.Store(host.GetName(), struct{}{})
 	t.Action.Init(t.ModuleCache, t.PipelineCache)
 	t.Action.AutoAssert(runtime)
 	if err := t.ExecuteWithRetry(runtime); err != nil {
@@ -158,6 +178,35 @@ func (t *RemoteTask) Run(runtime connector.Runtime, host connector.Host, index i
 	return
 }
 
+// DryRun evaluates the pre-check with the real connection, and records the operations of the action instead of
+// executing them. The errors are recorded in the plan and never fail the task.
+func (t *RemoteTask) DryRun(runtime connector.Runtime, d *connector.DryRun, host connector.Host) {
+	var ok bool
+	err := plan(func() (err error) {
+		t.Prepare.Init(t.ModuleCache, t.PipelineCache)
+		t.Prepare.AutoAssert(runtime)
+		ok, err = t.When(runtime)
+		return err
+	})
+	if err != nil {
+		d.Record(host.GetName(), connector.PlanNote, fmt.Sprintf("pre-check can not be evaluated: %v", err))
+	} else if !ok {
+		d.Record(host.GetName(), connector.PlanSkip, "pre-check is not satisfied")
+		t.TaskResult.AppendSkip(host)
+		return
+	}
+
+	runtime.GetRunner().Conn = d.Connection(host)
+	if err := plan(func() error {
+		t.Action.Init(t.ModuleCache, t.PipelineCache)
+		t.Action.AutoAssert(runtime)
+		return t.Action.Execute(runtime)
+	}); err != nil {
+		d.Record(host.GetName(), connector.PlanNote, fmt.Sprintf("the remaining operations can not be planned: %v", err))
+	}
+	t.TaskResult.AppendSuccess(host)
+}
+
 func (t *RemoteTask) ConfigureSelfRuntime(runtime connector.Runtime, host connector.Host, index int) error {
 	conn, err := runtime.GetConnector().Connect(host)
 	if err != nil {
@@ -232,11 +281,11 @@ func (t *RemoteTask) ExecuteWithRetry(runtime connector.Runtime) error {
 	return err
 }
 
-func (t *RemoteTask) ExecuteRollback() {
+func (t *RemoteTask) ExecuteRollback(failed *ending.TaskResult) {
 	if t.Rollback == nil {
 		return
 	}
-	if !t.TaskResult.IsFailed() {
+	if failed == nil || !failed.IsFailed() {
 		return
 	}
 
@@ -250,18 +299,39 @@ func (t *RemoteTask) ExecuteRollback() {
 		if ar.Host == nil || t.Runtime.HostIsDeprecated(ar.Host) {
 			continue
 		}
+		if _, ok := t.executed.Load(ar.Host.GetName()); !ok {
+			continue
+		}
+		result := ar
+		if failed != t.TaskResult {
+			// the rollback of a completed task is given the result of the failed task, so that it cleans up the
+			// failed host as if this task had failed on it
+			if result = failedResult(failed, ar.Host); result == nil {
+				continue
+			}
+		}
 		selfRuntime := t.Runtime.Copy()
 
+		rwg.Add(1)
 		if t.Parallel {
-			go t.RollbackWithTimeout(ctx, selfRuntime, ar.Host, i, ar, rwg, routinePool)
+			go t.RollbackWithTimeout(ctx, selfRuntime, ar.Host, i, result, rwg, routinePool)
 		} else {
-			t.RollbackWithTimeout(ctx, selfRuntime, ar.Host, i, ar, rwg, routinePool)
+			t.RollbackWithTimeout(ctx, selfRuntime, ar.Host, i, result, rwg, routinePool)
 		}
-		rwg.Add(1)
 	}
 	rwg.Wait()
 }
 
+// failedResult returns the result of the host in the failed task, it is nil if the task did not fail on the host.
+func failedResult(failed *ending.TaskResult, host connector.Host) *ending.ActionResult {
+	for _, ar := range failed.ActionResults {
+		if ar.Host != nil && ar.Host.GetName() == host.GetName() && ar.Status == ending.FAILED {
+			return ar
+		}
+	}
+	return nil
+}
+
 func (t *RemoteTask) RollbackWithTimeout(ctx context.Context, runtime connector.Runtime, host connector.Host, index int,
 	result *ending.ActionResult, wg *sync.WaitGroup, pool chan struct{}) {
 
@@ -312,6 +382,7 @@ func (t *RemoteTask) RunRollback(runtime connector.Runtime, host connector.Host,
 
 func (t *RemoteTask) Default() {
 	t.TaskResult = ending.NewTaskResult()
+	t.executed = new(sync.Map)
 	if t.Name == "" {
 		t.Name = DefaultTaskName
 	}
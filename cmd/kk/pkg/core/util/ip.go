@@ -18,6 +18,7 @@ package util
 
 import (
 	"encoding/binary"
+	"math/big"
 	"net"
 	"strconv"
 	"strings"
@@ -90,6 +91,56 @@ func GetAvailableIP(ipAndMask string) []string {
 	return availableIPs
 }
 
+// ParseCIDRs parses the comma-separated CIDRs, such as "10.233.64.0/18,fd85:ee78:d8a6:8607::1:0/112" of a
+// dual-stack network.
+func ParseCIDRs(cidrs string) ([]*net.IPNet, error) {
+	var ipNets []*net.IPNet
+	for _, cidr := range strings.Split(cidrs, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CIDR %s", cidr)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+// SplitCIDRs returns the IPv4 CIDR and the IPv6 CIDR of the comma-separated CIDRs. The one which is not found is
+// empty, and the invalid CIDRs are ignored.
+func SplitCIDRs(cidrs string) (string, string) {
+	var ipv4, ipv6 string
+	for _, cidr := range strings.Split(cidrs, ",") {
+		cidr = strings.TrimSpace(cidr)
+		ip, _, err := net.ParseCIDR(cidr)
+		switch {
+		case err != nil:
+		case ip.To4() != nil && ipv4 == "":
+			ipv4 = cidr
+		case ip.To4() == nil && ipv6 == "":
+			ipv6 = cidr
+		}
+	}
+	return ipv4, ipv6
+}
+
+// GetIndexedIP returns the address at the index of the CIDR, such as 10.233.0.1 for the index 1 of 10.233.0.0/18.
+// Both IPv4 and IPv6 are supported.
+func GetIndexedIP(cidr string, index int) (string, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid CIDR %s", cidr)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if big.NewInt(int64(index)).Cmp(new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))) >= 0 {
+		return "", errors.Errorf("the index %d is out of the range of CIDR %s", index, cidr)
+	}
+
+	n := new(big.Int).Add(new(big.Int).SetBytes(ipNet.IP), big.NewInt(int64(index)))
+	ip := make(net.IP, len(ipNet.IP))
+	n.FillBytes(ip)
+	return ip.String(), nil
+}
+
 func IPAddressToCIDR(ipAddress string) string {
 	if strings.Contains(ipAddress, "/") == true {
 		ipAndMask := strings.Split(ipAddress, "/")
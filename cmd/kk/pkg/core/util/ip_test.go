@@ -0,0 +1,60 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import "testing"
+
+func TestSplitCIDRs(t *testing.T) {
+	tests := []struct {
+		cidrs string
+		ipv4  string
+		ipv6  string
+	}{
+		{cidrs: "10.233.64.0/18", ipv4: "10.233.64.0/18"},
+		{cidrs: "fd85:ee78:d8a6:8607::1:0/112", ipv6: "fd85:ee78:d8a6:8607::1:0/112"},
+		{cidrs: "10.233.64.0/18, fd85:ee78:d8a6:8607::1:0/112", ipv4: "10.233.64.0/18", ipv6: "fd85:ee78:d8a6:8607::1:0/112"},
+		{cidrs: "fd85:ee78:d8a6:8607::1:0/112,10.233.64.0/18", ipv4: "10.233.64.0/18", ipv6: "fd85:ee78:d8a6:8607::1:0/112"},
+		{cidrs: "invalid"},
+	}
+	for _, tt := range tests {
+		ipv4, ipv6 := SplitCIDRs(tt.cidrs)
+		if ipv4 != tt.ipv4 || ipv6 != tt.ipv6 {
+			t.Errorf("SplitCIDRs(%s) = %s, %s, want %s, %s", tt.cidrs, ipv4, ipv6, tt.ipv4, tt.ipv6)
+		}
+	}
+}
+
+func TestGetIndexedIP(t *testing.T) {
+	tests := []struct {
+		cidr  string
+		index int
+		want  string
+		err   bool
+	}{
+		{cidr: "10.233.0.0/18", index: 1, want: "10.233.0.1"},
+		{cidr: "10.233.0.0/18", index: 3, want: "10.233.0.3"},
+		{cidr: "10.233.0.0/30", index: 4, err: true},
+		{cidr: "fd85:ee78:d8a6:8607::1000/116", index: 10, want: "fd85:ee78:d8a6:8607::100a"},
+		{cidr: "invalid", index: 1, err: true},
+	}
+	for _, tt := range tests {
+		got, err := GetIndexedIP(tt.cidr, tt.index)
+		if tt.err != (err != nil) || got != tt.want {
+			t.Errorf("GetIndexedIP(%s, %d) = %s, %v, want %s", tt.cidr, tt.index, got, err, tt.want)
+		}
+	}
+}
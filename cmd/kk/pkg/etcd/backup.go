@@ -0,0 +1,197 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils"
+)
+
+const (
+	// BackupDir is the directory in the work dir where the snapshots taken by "kk backup etcd" are kept.
+	BackupDir = "etcd-backup"
+
+	localSnapshot      = "localSnapshot"
+	snapshotPrefix     = "snapshot-"
+	snapshotSuffix     = ".db"
+	kubeadmEtcdDataDir = "/var/lib/etcd"
+	kubeadmEtcdPKIDir  = "/etc/kubernetes/pki/etcd"
+)
+
+// snapshotName returns the file name of a snapshot taken at t, the names are sorted in the order of time.
+func snapshotName(t time.Time) string {
+	return snapshotPrefix + t.Format("2006-01-02-15-04-05") + snapshotSuffix
+}
+
+// SnapshotETCD takes a snapshot from the first healthy member and fetches it to the local work dir.
+type SnapshotETCD struct {
+	common.KubeAction
+}
+
+func (s *SnapshotETCD) Execute(runtime connector.Runtime) error {
+	if _, ok := s.ModuleCache.GetMustString(localSnapshot); ok {
+		return nil
+	}
+
+	host := runtime.RemoteHost()
+	etcdctl, dataDir, err := Etcdctl(runtime, s.KubeConf.Cluster.Etcd.Type)
+	if err != nil {
+		return err
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s endpoint health", etcdctl), false); err != nil {
+		logger.Log.Warningf("etcd on %s is unhealthy, try the next member: %v", host.GetName(), err)
+		return nil
+	}
+
+	name := snapshotName(time.Now())
+	// the snapshot is saved in the etcd data dir when etcdctl runs in the container of kubeadm, the dir is mounted
+	// from the host
+	snapshotDir := common.TmpDir
+	if dataDir != "" {
+		snapshotDir = dataDir
+	} else if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+	remote := filepath.Join(snapshotDir, name)
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s snapshot save %s", etcdctl, remote), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "take the etcd snapshot on %s failed", host.GetName())
+	}
+	defer func() {
+		_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -f %s", remote), false)
+	}()
+
+	local := filepath.Join(runtime.GetWorkDir(), BackupDir, name)
+	if err := runtime.GetRunner().Fetch(local, remote); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "fetch the etcd snapshot %s failed", remote)
+	}
+	logger.Log.Messagef(host.GetName(), "the etcd snapshot is saved to %s", local)
+	s.ModuleCache.Set(localSnapshot, local)
+	return nil
+}
+
+// Etcdctl returns the etcdctl command of the member on the remote host. The data dir is returned if etcdctl runs
+// in the etcd container.
+func Etcdctl(runtime connector.Runtime, etcdType string) (string, string, error) {
+	host := runtime.RemoteHost()
+	if etcdType != kubekeyapiv1alpha2.Kubeadm {
+		return fmt.Sprintf("export ETCDCTL_API=3;%s/etcdctl --endpoints=https://%s:2379 "+
+			"--cacert=/etc/ssl/etcd/ssl/ca.pem --cert=/etc/ssl/etcd/ssl/admin-%s.pem --key=/etc/ssl/etcd/ssl/admin-%s-key.pem",
+			common.BinDir, host.GetInternalAddress(), host.GetName(), host.GetName()), "", nil
+	}
+
+	certs := fmt.Sprintf("--endpoints=https://127.0.0.1:2379 --cacert=%s/ca.crt "+
+		"--cert=%s/healthcheck-client.crt --key=%s/healthcheck-client.key", kubeadmEtcdPKIDir, kubeadmEtcdPKIDir, kubeadmEtcdPKIDir)
+	if exist, err := runtime.GetRunner().FileExist(filepath.Join(common.BinDir, "etcdctl")); err != nil {
+		return "", "", err
+	} else if exist {
+		return fmt.Sprintf("export ETCDCTL_API=3;%s/etcdctl %s", common.BinDir, certs), "", nil
+	}
+
+	// etcdctl is not installed on the masters of kubeadm, it is executed in the etcd static pod instead.
+	output, err := runtime.GetRunner().SudoCmd(
+		"crictl ps -q --state running --label io.kubernetes.container.name=etcd", false)
+	if err != nil {
+		return "", "", errors.Wrapf(errors.WithStack(err), "find the etcd container on %s failed", host.GetName())
+	}
+	id := strings.TrimSpace(strings.SplitN(strings.TrimSpace(output), "\n", 2)[0])
+	if id == "" {
+		return "", "", errors.Errorf("no running etcd container is found on %s", host.GetName())
+	}
+	return fmt.Sprintf("crictl exec %s etcdctl %s", id, certs), kubeadmEtcdDataDir, nil
+}
+
+// PruneLocalSnapshots removes the local snapshots beyond KeepBackupNumber.
+type PruneLocalSnapshots struct {
+	common.KubeAction
+}
+
+func (p *PruneLocalSnapshots) Execute(runtime connector.Runtime) error {
+	if _, ok := p.ModuleCache.GetMustString(localSnapshot); !ok {
+		return errors.New("no healthy etcd member is found to take the snapshot")
+	}
+	return pruneSnapshots(&localStore{dir: filepath.Join(runtime.GetWorkDir(), BackupDir)}, p.KubeConf.Cluster.Etcd.KeepBackupNumber)
+}
+
+// UploadSnapshot uploads the snapshot to the backup target, and removes the remote snapshots beyond KeepBackupNumber.
+type UploadSnapshot struct {
+	common.KubeAction
+}
+
+func (u *UploadSnapshot) Execute(runtime connector.Runtime) error {
+	local, ok := u.ModuleCache.GetMustString(localSnapshot)
+	if !ok {
+		return errors.New("get the etcd snapshot path by module cache failed")
+	}
+
+	checker, err := connector.NewHostKeyChecker(u.KubeConf.Arg.HostKeyChecking,
+		filepath.Join(runtime.GetWorkDir(), common.KnownHostsDir, u.KubeConf.ClusterName))
+	if err != nil {
+		return err
+	}
+	store, err := newBackupStore(u.KubeConf.Cluster.Etcd.BackupTarget, checker)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	name := filepath.Base(local)
+	if err := store.Put(local, name); err != nil {
+		return errors.Wrapf(err, "upload the etcd snapshot %s failed", name)
+	}
+	logger.Log.Messagef(common.LocalHost, "the etcd snapshot %s is uploaded", name)
+	return pruneSnapshots(store, u.KubeConf.Cluster.Etcd.KeepBackupNumber)
+}
+
+// pruneSnapshots removes the oldest snapshots in the store and keeps the latest ones. The other files in the store
+// are left untouched.
+func pruneSnapshots(store backupStore, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	names, err := store.List()
+	if err != nil {
+		return errors.Wrap(err, "list the etcd snapshots failed")
+	}
+
+	snapshots := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, snapshotPrefix) && strings.HasSuffix(name, snapshotSuffix) {
+			snapshots = append(snapshots, name)
+		}
+	}
+	if len(snapshots) <= keep {
+		return nil
+	}
+	sort.Strings(snapshots)
+	for _, name := range snapshots[:len(snapshots)-keep] {
+		if err := store.Delete(name); err != nil {
+			return errors.Wrapf(err, "remove the etcd snapshot %s failed", name)
+		}
+	}
+	return nil
+}
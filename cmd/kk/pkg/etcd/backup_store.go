@@ -0,0 +1,319 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	defaultSFTPPort = "22"
+)
+
+// backupStore is a flat directory of the etcd snapshots.
+type backupStore interface {
+	Put(local, name string) error
+	List() ([]string, error)
+	Delete(name string) error
+	Close() error
+}
+
+// ValidateBackupTarget checks that exactly one kind of the backup target is configured.
+func ValidateBackupTarget(target *kubekeyapiv1alpha2.EtcdBackupTarget) error {
+	if target == nil {
+		return nil
+	}
+	switch {
+	case target.S3 != nil && target.SFTP != nil:
+		return errors.New("s3 and sftp of the etcd backup target are mutually exclusive")
+	case target.S3 != nil:
+		if target.S3.Bucket == "" {
+			return errors.New("the bucket of the s3 backup target is required")
+		}
+	case target.SFTP != nil:
+		if target.SFTP.Address == "" || target.SFTP.User == "" {
+			return errors.New("the address and user of the sftp backup target are required")
+		}
+		if target.SFTP.Password == "" && target.SFTP.PrivateKeyPath == "" {
+			return errors.New("the password or privateKeyPath of the sftp backup target is required")
+		}
+	default:
+		return errors.New("one of s3 and sftp of the etcd backup target is required")
+	}
+	return nil
+}
+
+func newBackupStore(target *kubekeyapiv1alpha2.EtcdBackupTarget, checker *connector.HostKeyChecker) (backupStore, error) {
+	if err := ValidateBackupTarget(target); err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, errors.New("the etcd backup target is not configured")
+	}
+	if target.S3 != nil {
+		return newS3Store(target.S3)
+	}
+	return newSFTPStore(target.SFTP, checker)
+}
+
+// localStore keeps the snapshots in a local directory.
+type localStore struct {
+	dir string
+}
+
+func (l *localStore) Put(local, name string) error {
+	if err := os.MkdirAll(l.dir, os.ModePerm); err != nil {
+		return errors.WithStack(err)
+	}
+	return copyFile(local, filepath.Join(l.dir, name))
+}
+
+func (l *localStore) List() ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func (l *localStore) Delete(name string) error {
+	return errors.WithStack(os.Remove(filepath.Join(l.dir, name)))
+}
+
+func (l *localStore) Close() error {
+	return nil
+}
+
+// s3Store keeps the snapshots in an S3-compatible bucket.
+type s3Store struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+func newS3Store(target *kubekeyapiv1alpha2.S3BackupTarget) (*s3Store, error) {
+	region := target.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	cfg := &aws.Config{
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(target.ForcePathStyle),
+		DisableSSL:       aws.Bool(target.Insecure),
+	}
+	if target.Endpoint != "" {
+		cfg.Endpoint = aws.String(target.Endpoint)
+	}
+	// the default credential chain, e.g. the environment AWS_ACCESS_KEY_ID, is used if the keys are not configured
+	if target.AccessKeyID != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(target.AccessKeyID, target.SecretAccessKey, "")
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "create the s3 session failed")
+	}
+	return &s3Store{client: s3.New(sess), bucket: target.Bucket, prefix: target.Prefix}, nil
+}
+
+func (s *s3Store) Put(local, name string) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	_, err = s3manager.NewUploaderWithClient(s.client).Upload(&s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + name),
+		Body:   f,
+	})
+	return errors.WithStack(err)
+}
+
+func (s *s3Store) List() ([]string, error) {
+	var names []string
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, o := range page.Contents {
+			// the objects in the sub directories of the prefix are not snapshots of this cluster
+			if name := strings.TrimPrefix(aws.StringValue(o.Key), s.prefix); !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		return true
+	})
+	return names, errors.WithStack(err)
+}
+
+func (s *s3Store) Delete(name string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + name),
+	})
+	return errors.WithStack(err)
+}
+
+func (s *s3Store) Close() error {
+	return nil
+}
+
+// sftpStore keeps the snapshots in a directory of an SFTP server.
+type sftpStore struct {
+	sshClient  *ssh.Client
+	sftpClient *sftp.Client
+	dir        string
+}
+
+func newSFTPStore(target *kubekeyapiv1alpha2.SFTPBackupTarget, checker *connector.HostKeyChecker) (*sftpStore, error) {
+	var auth []ssh.AuthMethod
+	if target.Password != "" {
+		auth = append(auth, ssh.Password(target.Password))
+	}
+	if target.PrivateKeyPath != "" {
+		key, err := os.ReadFile(target.PrivateKeyPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read the private key %s failed", target.PrivateKeyPath)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse the private key %s failed", target.PrivateKeyPath)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	addr := target.Address
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultSFTPPort)
+	}
+	sshClient, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            target.User,
+		Auth:            auth,
+		HostKeyCallback: checker.Callback(target.HostKey, ""),
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not establish connection to %s", addr)
+	}
+	sftpClient, err := sftp.NewClient(sshClient)
+	if err != nil {
+		_ = sshClient.Close()
+		return nil, errors.Wrapf(err, "new sftp client failed")
+	}
+
+	dir := target.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if err := sftpClient.MkdirAll(dir); err != nil {
+		_ = sftpClient.Close()
+		_ = sshClient.Close()
+		return nil, errors.Wrapf(err, "create the directory %s on %s failed", dir, addr)
+	}
+	return &sftpStore{sshClient: sshClient, sftpClient: sftpClient, dir: dir}, nil
+}
+
+func (s *sftpStore) Put(local, name string) error {
+	src, err := os.Open(local)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer src.Close()
+
+	// the snapshot is renamed when it is completed, so that a broken upload is never taken as a snapshot
+	part := path.Join(s.dir, name+".part")
+	dst, err := s.sftpClient.Create(part)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return errors.WithStack(err)
+	}
+	if err := dst.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(s.sftpClient.Rename(part, path.Join(s.dir, name)))
+}
+
+func (s *sftpStore) List() ([]string, error) {
+	infos, err := s.sftpClient.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+func (s *sftpStore) Delete(name string) error {
+	return errors.WithStack(s.sftpClient.Remove(path.Join(s.dir, name)))
+}
+
+func (s *sftpStore) Close() error {
+	_ = s.sftpClient.Close()
+	return s.sshClient.Close()
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(out.Close())
+}
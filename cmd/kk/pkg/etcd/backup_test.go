@@ -0,0 +1,100 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+)
+
+func TestPruneSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	var snapshots []string
+	for i := 0; i < 5; i++ {
+		snapshots = append(snapshots, snapshotName(start.Add(time.Duration(i)*time.Hour)))
+	}
+	for _, name := range append([]string{"README"}, snapshots...) {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := &localStore{dir: dir}
+	if err := pruneSnapshots(store, 3); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	want := append([]string{"README"}, snapshots[2:]...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValidateBackupTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  *kubekeyapiv1alpha2.EtcdBackupTarget
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{
+			name:   "s3",
+			target: &kubekeyapiv1alpha2.EtcdBackupTarget{S3: &kubekeyapiv1alpha2.S3BackupTarget{Bucket: "etcd"}},
+		},
+		{
+			name: "sftp",
+			target: &kubekeyapiv1alpha2.EtcdBackupTarget{SFTP: &kubekeyapiv1alpha2.SFTPBackupTarget{
+				Address: "192.168.0.10", User: "backup", PrivateKeyPath: "/root/.ssh/id_rsa"}},
+		},
+		{
+			name:    "empty",
+			target:  &kubekeyapiv1alpha2.EtcdBackupTarget{},
+			wantErr: true,
+		},
+		{
+			name: "both",
+			target: &kubekeyapiv1alpha2.EtcdBackupTarget{
+				S3:   &kubekeyapiv1alpha2.S3BackupTarget{Bucket: "etcd"},
+				SFTP: &kubekeyapiv1alpha2.SFTPBackupTarget{Address: "192.168.0.10", User: "backup", Password: "P@88w0rd"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "sftp without credentials",
+			target:  &kubekeyapiv1alpha2.EtcdBackupTarget{SFTP: &kubekeyapiv1alpha2.SFTPBackupTarget{Address: "192.168.0.10", User: "backup"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateBackupTarget(tt.target); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBackupTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -143,10 +143,58 @@ type GenerateCerts struct {
 }
 
 func (g *GenerateCerts) Execute(runtime connector.Runtime) error {
+	pkiPath := fmt.Sprintf("%s/pki/etcd", runtime.GetWorkDir())
+
+	files, err := generateCerts(g.KubeConf, runtime, pkiPath)
+	if err != nil {
+		return err
+	}
+
+	g.ModuleCache.Set(LocalCertsDir, pkiPath)
+	g.ModuleCache.Set(CertsFileList, files)
 
+	return nil
+}
+
+// RenewCerts regenerates the admin, member and node certs from the existing etcd CA fetched from the cluster.
+type RenewCerts struct {
+	common.KubeAction
+}
+
+func (r *RenewCerts) Execute(runtime connector.Runtime) error {
 	pkiPath := fmt.Sprintf("%s/pki/etcd", runtime.GetWorkDir())
 
-	altName := GenerateAltName(g.KubeConf, &runtime)
+	if !certs.CertOrKeyExist(pkiPath, KubekeyCertEtcdCA().BaseName) {
+		return errors.Errorf("the etcd CA is not found in %s", pkiPath)
+	}
+	// the existing certs are reused by GenerateCerts, so they are removed and signed again
+	leafs, err := filepath.Glob(filepath.Join(pkiPath, "*.pem"))
+	if err != nil {
+		return err
+	}
+	for _, leaf := range leafs {
+		if !strings.HasPrefix(filepath.Base(leaf), "ca") {
+			if err := os.Remove(leaf); err != nil {
+				return errors.Wrapf(err, "remove the etcd cert %s failed", leaf)
+			}
+		}
+	}
+
+	files, err := generateCerts(r.KubeConf, runtime, pkiPath)
+	if err != nil {
+		return err
+	}
+
+	r.ModuleCache.Set(LocalCertsDir, pkiPath)
+	r.ModuleCache.Set(CertsFileList, files)
+
+	return nil
+}
+
+// generateCerts generates the etcd CA and the certs of the etcd nodes and masters which do not exist in the pki path,
+// and returns the names of the files.
+func generateCerts(kubeConf *common.KubeConf, runtime connector.Runtime, pkiPath string) ([]string, error) {
+	altName := GenerateAltName(kubeConf, &runtime)
 
 	files := []string{"ca.pem", "ca-key.pem"}
 
@@ -170,23 +218,19 @@ func (g *GenerateCerts) Execute(runtime connector.Runtime) error {
 	var lastCACert *certs.KubekeyCert
 	for _, c := range certsList {
 		if c.CAName == "" {
-			err := certs.GenerateCA(c, pkiPath, g.KubeConf)
+			err := certs.GenerateCA(c, pkiPath, kubeConf)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			lastCACert = c
 		} else {
-			err := certs.GenerateCerts(c, lastCACert, pkiPath, g.KubeConf)
+			err := certs.GenerateCerts(c, lastCACert, pkiPath, kubeConf)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
-
-	g.ModuleCache.Set(LocalCertsDir, pkiPath)
-	g.ModuleCache.Set(CertsFileList, files)
-
-	return nil
+	return files, nil
 }
 
 func GenerateAltName(k *common.KubeConf, runtime *connector.Runtime) *cert.AltNames {
@@ -18,6 +18,7 @@ package etcd
 
 import (
 	"path/filepath"
+	"time"
 
 	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
@@ -36,6 +37,10 @@ func (p *PreCheckModule) IsSkip() bool {
 	return p.Skip
 }
 
+func (p *PreCheckModule) IsCollector() bool {
+	return true
+}
+
 func (p *PreCheckModule) Init() {
 	p.Name = "ETCDPreCheckModule"
 	p.Desc = "Get ETCD cluster status"
@@ -48,8 +53,21 @@ func (p *PreCheckModule) Init() {
 		Parallel: false,
 		Retry:    0,
 	}
+
+	// the access addresses are generated by the collector, so that they are set when the install is skipped on resume
+	accessAddress := &task.RemoteTask{
+		Name:     "GenerateAccessAddress",
+		Desc:     "Generate access address",
+		Hosts:    p.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   new(GenerateAccessAddress),
+		Parallel: true,
+		Retry:    1,
+	}
+
 	p.Tasks = []task.Interface{
 		getStatus,
+		accessAddress,
 	}
 }
 
@@ -140,6 +158,82 @@ func CertsModuleForExternal(c *CertsModule) []task.Interface {
 	}
 }
 
+// RenewCertsModule signs the certs of the etcd nodes and masters again with the existing etcd CA, and restarts the etcd
+// members one by one.
+type RenewCertsModule struct {
+	common.KubeModule
+	Skip bool
+}
+
+func (r *RenewCertsModule) IsSkip() bool {
+	return r.Skip
+}
+
+func (r *RenewCertsModule) Init() {
+	r.Name = "ETCDRenewCertsModule"
+	r.Desc = "Renew etcd certs"
+
+	fetchCerts := &task.RemoteTask{
+		Name:     "FetchETCDCerts",
+		Desc:     "Fetch etcd certs",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Prepare:  new(FirstETCDNode),
+		Action:   new(FetchCerts),
+		Parallel: false,
+	}
+
+	renewCerts := &task.LocalTask{
+		Name:   "RenewETCDCerts",
+		Desc:   "Renew etcd certs",
+		Action: new(RenewCerts),
+	}
+
+	syncCertsFile := &task.RemoteTask{
+		Name:     "SyncCertsFile",
+		Desc:     "Synchronize certs file",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(SyncCertsFile),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	syncCertsToMaster := &task.RemoteTask{
+		Name:     "SyncCertsFileToMaster",
+		Desc:     "Synchronize certs file to master",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  &common.OnlyETCD{Not: true},
+		Action:   new(SyncCertsFile),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	restart := &task.RemoteTask{
+		Name:     "RollingRestartETCD",
+		Desc:     "Restart etcd members one by one",
+		Hosts:    r.Runtime.GetHostsByRole(common.ETCD),
+		Action:   new(RollingRestartETCD),
+		Parallel: false,
+	}
+
+	refreshClientCerts := &task.RemoteTask{
+		Name:     "RefreshKubeSphereETCDClientCerts",
+		Desc:     "Refresh the etcd client certs of KubeSphere",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(RefreshKubeSphereClientCerts),
+		Parallel: true,
+	}
+
+	r.Tasks = []task.Interface{
+		fetchCerts,
+		renewCerts,
+		syncCertsFile,
+		syncCertsToMaster,
+		restart,
+		refreshClientCerts,
+	}
+}
+
 type InstallETCDBinaryModule struct {
 	common.KubeModule
 	Skip bool
@@ -174,20 +268,9 @@ func (i *InstallETCDBinaryModule) Init() {
 		Retry:    1,
 	}
 
-	accessAddress := &task.RemoteTask{
-		Name:     "GenerateAccessAddress",
-		Desc:     "Generate access address",
-		Hosts:    i.Runtime.GetHostsByRole(common.ETCD),
-		Prepare:  new(FirstETCDNode),
-		Action:   new(GenerateAccessAddress),
-		Parallel: true,
-		Retry:    1,
-	}
-
 	i.Tasks = []task.Interface{
 		installETCDBinary,
 		generateETCDService,
-		accessAddress,
 	}
 }
 
@@ -309,6 +392,7 @@ func handleExistCluster(c *ConfigureModule) []task.Interface {
 		Prepare:  &NodeETCDExist{Not: true},
 		Action:   new(JoinMember),
 		Parallel: false,
+		Rollback: new(RemoveETCDMember),
 	}
 
 	newETCDNodeHealthCheck := &task.RemoteTask{
@@ -319,6 +403,7 @@ func handleExistCluster(c *ConfigureModule) []task.Interface {
 		Action:   new(HealthCheck),
 		Parallel: true,
 		Retry:    20,
+		Rollback: new(RemoveETCDMember),
 	}
 
 	checkMember := &task.RemoteTask{
@@ -328,6 +413,7 @@ func handleExistCluster(c *ConfigureModule) []task.Interface {
 		Prepare:  &NodeETCDExist{Not: true},
 		Action:   new(CheckMember),
 		Parallel: true,
+		Rollback: new(RemoveETCDMember),
 	}
 
 	allRefreshETCDConfig := &task.RemoteTask{
@@ -423,3 +509,156 @@ func (b *BackupModule) Init() {
 		enable,
 	}
 }
+
+type RestoreModule struct {
+	common.KubeModule
+}
+
+func (r *RestoreModule) Init() {
+	r.Name = "ETCDRestoreModule"
+	r.Desc = "Restore ETCD cluster data from a snapshot"
+
+	etcdHosts := r.Runtime.GetHostsByRole(common.ETCD)
+
+	fetchLatestSnapshot := &task.RemoteTask{
+		Name:   "FetchLatestETCDSnapshot",
+		Desc:   "Fetch the latest etcd snapshot",
+		Hosts:  etcdHosts[:1],
+		Action: new(FetchLatestSnapshot),
+	}
+
+	stopKubeAPIServer := &task.RemoteTask{
+		Name:     "StopKubeAPIServer",
+		Desc:     "Stop kube-apiserver",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(StopKubeAPIServer),
+		Parallel: true,
+	}
+
+	stopETCD := &task.RemoteTask{
+		Name:     "StopETCD",
+		Desc:     "Stop etcd",
+		Hosts:    etcdHosts,
+		Action:   new(StopETCD),
+		Parallel: true,
+	}
+
+	distributeSnapshot := &task.RemoteTask{
+		Name:     "DistributeETCDSnapshot",
+		Desc:     "Distribute the etcd snapshot",
+		Hosts:    etcdHosts,
+		Action:   new(DistributeSnapshot),
+		Parallel: true,
+	}
+
+	restoreSnapshot := &task.RemoteTask{
+		Name:     "RestoreETCDSnapshot",
+		Desc:     "Restore the etcd snapshot",
+		Hosts:    etcdHosts,
+		Action:   new(RestoreSnapshot),
+		Parallel: true,
+		Retry:    1,
+	}
+
+	// all members have to be started at the same time to reach the quorum
+	startETCD := &task.RemoteTask{
+		Name:     "StartETCD",
+		Desc:     "Start etcd",
+		Hosts:    etcdHosts,
+		Action:   new(RestartETCD),
+		Parallel: true,
+	}
+
+	generateAccessAddress := &task.RemoteTask{
+		Name:   "GenerateAccessAddress",
+		Desc:   "Generate access address",
+		Hosts:  etcdHosts[:1],
+		Action: new(GenerateAccessAddress),
+	}
+
+	healthCheck := &task.RemoteTask{
+		Name:     "ETCDHealthCheck",
+		Desc:     "Health check on all etcd",
+		Hosts:    etcdHosts,
+		Action:   new(HealthCheck),
+		Parallel: true,
+		Retry:    20,
+	}
+
+	startKubeAPIServer := &task.RemoteTask{
+		Name:     "StartKubeAPIServer",
+		Desc:     "Start kube-apiserver",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(StartKubeAPIServer),
+		Parallel: true,
+	}
+
+	kubeAPIServerHealthCheck := &task.RemoteTask{
+		Name:     "KubeAPIServerHealthCheck",
+		Desc:     "Health check on kube-apiserver",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Action:   new(KubeAPIServerHealthCheck),
+		Parallel: true,
+		Retry:    20,
+		Delay:    10 * time.Second,
+	}
+
+	r.Tasks = []task.Interface{}
+	if r.KubeConf.Arg.Snapshot == LatestSnapshot {
+		r.Tasks = append(r.Tasks, fetchLatestSnapshot)
+	}
+	r.Tasks = append(r.Tasks,
+		stopKubeAPIServer,
+		stopETCD,
+		distributeSnapshot,
+		restoreSnapshot,
+		startETCD,
+		generateAccessAddress,
+		healthCheck,
+		startKubeAPIServer,
+		kubeAPIServerHealthCheck,
+	)
+}
+
+type SnapshotModule struct {
+	common.KubeModule
+}
+
+func (s *SnapshotModule) Init() {
+	s.Name = "ETCDSnapshotModule"
+	s.Desc = "Take a snapshot of ETCD cluster data"
+
+	// the etcd members of kubeadm run on the masters as static pods
+	hosts := s.Runtime.GetHostsByRole(common.ETCD)
+	if s.KubeConf.Cluster.Etcd.Type == kubekeyapiv1alpha2.Kubeadm {
+		hosts = s.Runtime.GetHostsByRole(common.Master)
+	}
+
+	snapshotETCD := &task.RemoteTask{
+		Name:   "SnapshotETCD",
+		Desc:   "Take a snapshot from a healthy etcd member",
+		Hosts:  hosts,
+		Action: new(SnapshotETCD),
+	}
+
+	pruneLocalSnapshots := &task.LocalTask{
+		Name:   "PruneLocalETCDSnapshots",
+		Desc:   "Remove the outdated local etcd snapshots",
+		Action: new(PruneLocalSnapshots),
+	}
+
+	uploadSnapshot := &task.LocalTask{
+		Name:   "UploadETCDSnapshot",
+		Desc:   "Upload the etcd snapshot to the backup target",
+		Action: new(UploadSnapshot),
+		Retry:  3,
+	}
+
+	s.Tasks = []task.Interface{
+		snapshotETCD,
+		pruneLocalSnapshots,
+	}
+	if s.KubeConf.Cluster.Etcd.BackupTarget != nil {
+		s.Tasks = append(s.Tasks, uploadSnapshot)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils"
+)
+
+const (
+	// LatestSnapshot means restoring the latest snapshot written by the backup timer on the first etcd node.
+	LatestSnapshot = "latest"
+
+	snapshotPath     = "snapshotPath"
+	apiServerPath    = "/etc/kubernetes/manifests/kube-apiserver.yaml"
+	apiServerBackup  = "/etc/kubernetes/kube-apiserver.yaml.restore"
+	remoteSnapshot   = common.TmpDir + "/etcd-snapshot.db"
+	defaultDataDir   = "/var/lib/etcd"
+	initClusterToken = "k8s_etcd"
+)
+
+type FetchLatestSnapshot struct {
+	common.KubeAction
+}
+
+func (f *FetchLatestSnapshot) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	// the backup directories are named by the time, e.g. etcd-2023-01-02-03-04-05
+	output, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("ls -1d %s/etcd-*/snapshot.db 2>/dev/null | sort | tail -n 1", f.KubeConf.Cluster.Etcd.BackupDir), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "find the latest etcd snapshot failed")
+	}
+	remote := strings.TrimSpace(output)
+	if remote == "" {
+		return errors.Errorf("no etcd snapshot is found in %s on %s", f.KubeConf.Cluster.Etcd.BackupDir, host.GetName())
+	}
+
+	local := filepath.Join(runtime.GetWorkDir(), "etcd-snapshot", host.GetName(), filepath.Base(filepath.Dir(remote))+".db")
+	if err := runtime.GetRunner().Fetch(local, remote); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "fetch the etcd snapshot %s failed", remote)
+	}
+	f.ModuleCache.Set(snapshotPath, local)
+	return nil
+}
+
+type StopKubeAPIServer struct {
+	common.KubeAction
+}
+
+func (s *StopKubeAPIServer) Execute(runtime connector.Runtime) error {
+	// kube-apiserver is a static pod, kubelet stops it when the manifest is moved out of the manifests dir.
+	if _, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("if [ -f %s ]; then mv -f %s %s; fi", apiServerPath, apiServerPath, apiServerBackup), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "stop kube-apiserver failed")
+	}
+	return nil
+}
+
+type StartKubeAPIServer struct {
+	common.KubeAction
+}
+
+func (s *StartKubeAPIServer) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("if [ -f %s ]; then mv -f %s %s; fi && systemctl restart kubelet", apiServerBackup, apiServerBackup, apiServerPath), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "start kube-apiserver failed")
+	}
+	return nil
+}
+
+type KubeAPIServerHealthCheck struct {
+	common.KubeAction
+}
+
+func (k *KubeAPIServerHealthCheck) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"/usr/local/bin/kubectl --kubeconfig /etc/kubernetes/admin.conf get --raw /healthz", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "kube-apiserver health check failed")
+	}
+	return nil
+}
+
+type StopETCD struct {
+	common.KubeAction
+}
+
+func (s *StopETCD) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd("systemctl stop etcd", false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "stop etcd failed")
+	}
+	return nil
+}
+
+type DistributeSnapshot struct {
+	common.KubeAction
+}
+
+func (d *DistributeSnapshot) Execute(runtime connector.Runtime) error {
+	local := d.KubeConf.Arg.Snapshot
+	if local == LatestSnapshot {
+		path, ok := d.ModuleCache.GetMustString(snapshotPath)
+		if !ok {
+			return errors.New("get the etcd snapshot path by module cache failed")
+		}
+		local = path
+	}
+
+	if err := utils.ResetTmpDir(runtime); err != nil {
+		return err
+	}
+	if err := runtime.GetRunner().Scp(local, remoteSnapshot); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "sync the etcd snapshot %s failed", local)
+	}
+	return nil
+}
+
+type RestoreSnapshot struct {
+	common.KubeAction
+}
+
+func (r *RestoreSnapshot) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if exist, ok := host.GetCache().GetMustBool(common.ETCDExist); !ok || !exist {
+		return errors.Errorf("etcd is not installed on %s", host.GetName())
+	}
+	etcdName, ok := host.GetCache().GetMustString(common.ETCDName)
+	if !ok {
+		return errors.New("get etcd node status by host label failed")
+	}
+
+	initialCluster, err := restoreInitialCluster(runtime.GetHostsByRole(common.ETCD))
+	if err != nil {
+		return err
+	}
+
+	dataDir := defaultDataDir
+	if r.KubeConf.Cluster.Etcd.DataDir != nil && *r.KubeConf.Cluster.Etcd.DataDir != "" {
+		dataDir = *r.KubeConf.Cluster.Etcd.DataDir
+	}
+	// the current data is kept for the manual recovery
+	backup := fmt.Sprintf("%s-%s.bak", dataDir, time.Now().Format("2006-01-02-15-04-05"))
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("if [ -d %s ]; then mv %s %s; fi", dataDir, dataDir, backup), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "backup etcd data dir %s failed", dataDir)
+	}
+
+	args := fmt.Sprintf("snapshot restore %s --name %s --initial-cluster %s --initial-cluster-token %s "+
+		"--initial-advertise-peer-urls https://%s:2380 --data-dir %s",
+		remoteSnapshot, etcdName, initialCluster, initClusterToken, host.GetInternalAddress(), dataDir)
+	// etcdutl is shipped since etcd v3.5, etcdctl is used for the older versions.
+	restoreCmd := fmt.Sprintf("if [ -x %s/etcdutl ]; then %s/etcdutl %s; else ETCDCTL_API=3 %s/etcdctl %s; fi",
+		common.BinDir, common.BinDir, args, common.BinDir, args)
+	if _, err := runtime.GetRunner().SudoCmd(restoreCmd, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "restore etcd snapshot failed")
+	}
+	return nil
+}
+
+// restoreInitialCluster returns the members of the restored etcd cluster, the member names are the ones in etcd.env
+// so that the restored cluster matches the existing configuration.
+func restoreInitialCluster(hosts []connector.Host) (string, error) {
+	members := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		name, ok := h.GetCache().GetMustString(common.ETCDName)
+		if !ok {
+			return "", errors.Errorf("get etcd name of %s by host label failed", h.GetName())
+		}
+		members = append(members, fmt.Sprintf("%s=https://%s:2380", name, h.GetInternalAddress()))
+	}
+	return strings.Join(members, ","), nil
+}
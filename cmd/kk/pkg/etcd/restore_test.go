@@ -0,0 +1,54 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+)
+
+func newETCDHost(name, address, etcdName string) connector.Host {
+	h := connector.NewHost()
+	h.SetName(name)
+	h.SetInternalAddress(address)
+	if etcdName != "" {
+		h.GetCache().Set(common.ETCDName, etcdName)
+	}
+	return h
+}
+
+func TestRestoreInitialCluster(t *testing.T) {
+	hosts := []connector.Host{
+		newETCDHost("node1", "192.168.0.2", "etcd-node1"),
+		newETCDHost("node2", "192.168.0.3", "etcd-old-name"),
+	}
+	got, err := restoreInitialCluster(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "etcd-node1=https://192.168.0.2:2380,etcd-old-name=https://192.168.0.3:2380"
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	hosts = append(hosts, newETCDHost("node3", "192.168.0.4", ""))
+	if _, err := restoreInitialCluster(hosts); err == nil {
+		t.Error("expected an error when the etcd name is unknown")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+)
+
+// RemoveETCDMember removes a new etcd node which failed to join from the etcd cluster, and cleans up its data,
+// so that the node can join again in the next execution.
+type RemoveETCDMember struct {
+	common.KubeRollback
+}
+
+func (r *RemoveETCDMember) Execute(runtime connector.Runtime, result *ending.ActionResult) error {
+	if result.Status != ending.FAILED {
+		return nil
+	}
+
+	host := runtime.RemoteHost()
+	_, _ = runtime.GetRunner().SudoCmd("systemctl stop etcd && systemctl disable etcd", false)
+
+	v, ok := r.PipelineCache.Get(common.ETCDCluster)
+	if !ok {
+		return errors.New("get etcd cluster status by pipeline cache failed")
+	}
+	cluster := v.(*EtcdCluster)
+	etcdctl := fmt.Sprintf("export ETCDCTL_API=2;"+
+		"export ETCDCTL_CERT_FILE='/etc/ssl/etcd/ssl/admin-%s.pem';"+
+		"export ETCDCTL_KEY_FILE='/etc/ssl/etcd/ssl/admin-%s-key.pem';"+
+		"export ETCDCTL_CA_FILE='/etc/ssl/etcd/ssl/ca.pem';"+
+		"%s/etcdctl --endpoints=%s", host.GetName(), host.GetName(), common.BinDir, cluster.accessAddresses)
+
+	memberList, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s member list", etcdctl), false)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "list etcd member failed")
+	}
+	// e.g. "8211f1d0f64f3269[unstarted]: peerURLs=https://192.168.0.3:2380"
+	peerURL := fmt.Sprintf("https://%s:2380", host.GetInternalAddress())
+	for _, line := range strings.Split(memberList, "\n") {
+		if !strings.Contains(line, peerURL) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimSpace(strings.SplitN(line, ":", 2)[0]), "[unstarted]")
+		if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s member remove %s", etcdctl, id), false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "remove etcd member %s failed", id)
+		}
+	}
+
+	dataDir := "/var/lib/etcd"
+	if r.KubeConf.Cluster.Etcd.DataDir != nil && *r.KubeConf.Cluster.Etcd.DataDir != "" {
+		dataDir = *r.KubeConf.Cluster.Etcd.DataDir
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s", dataDir), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "remove etcd data dir %s failed", dataDir)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -395,6 +396,55 @@ func (r *RestartETCD) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// RollingRestartETCD restarts the etcd member and waits until it is healthy, so that the quorum is kept when the
+// members are restarted one by one.
+type RollingRestartETCD struct {
+	common.KubeAction
+}
+
+func (r *RollingRestartETCD) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd("systemctl restart etcd", false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "restart etcd on %s failed", host.GetName())
+	}
+
+	etcdctl, _, err := Etcdctl(runtime, r.KubeConf.Cluster.Etcd.Type)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < 10; i++ {
+		if _, err = runtime.GetRunner().SudoCmd(fmt.Sprintf("%s endpoint health", etcdctl), false); err == nil {
+			return nil
+		}
+		time.Sleep(6 * time.Second)
+	}
+	return errors.Wrapf(errors.WithStack(err), "etcd on %s is not healthy after the restart", host.GetName())
+}
+
+// RefreshKubeSphereClientCerts updates the etcd client certs used by the monitoring of KubeSphere if it exists.
+type RefreshKubeSphereClientCerts struct {
+	common.KubeAction
+}
+
+func (r *RefreshKubeSphereClientCerts) Execute(runtime connector.Runtime) error {
+	if _, err := runtime.GetRunner().SudoCmd(
+		"/usr/local/bin/kubectl -n kubesphere-monitoring-system get secret kube-etcd-client-certs", false); err != nil {
+		return nil
+	}
+
+	host := runtime.RemoteHost()
+	if _, err := runtime.GetRunner().SudoCmd(
+		fmt.Sprintf("/usr/local/bin/kubectl -n kubesphere-monitoring-system create secret generic kube-etcd-client-certs "+
+			"--from-file=etcd-client-ca.crt=%s/ca.pem "+
+			"--from-file=etcd-client.crt=%s/node-%s.pem "+
+			"--from-file=etcd-client.key=%s/node-%s-key.pem "+
+			"--dry-run=client -o yaml | /usr/local/bin/kubectl apply -f -",
+			common.ETCDCertDir, common.ETCDCertDir, host.GetName(), common.ETCDCertDir, host.GetName()), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "update the etcd client certs of KubeSphere failed")
+	}
+	return nil
+}
+
 type BackupETCD struct {
 	common.KubeAction
 }
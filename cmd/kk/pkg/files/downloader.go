@@ -0,0 +1,306 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package files
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDownloadRetry   = 5
+	defaultDownloadBackoff = 2 * time.Second
+	maxDownloadBackoff     = 30 * time.Second
+	progressInterval       = 3 * time.Second
+)
+
+var (
+	downloaderMu      sync.RWMutex
+	defaultDownloader = &Downloader{
+		Client:   &http.Client{Transport: newTransport(http.ProxyFromEnvironment, nil)},
+		Retry:    defaultDownloadRetry,
+		Backoff:  defaultDownloadBackoff,
+		Progress: os.Stderr,
+	}
+)
+
+// errChecksum means that the downloaded file is corrupted, the partial file can not be resumed.
+var errChecksum = errors.New("SHA256 no match")
+
+// DownloaderOptions is the configuration of the built-in downloader.
+type DownloaderOptions struct {
+	// Proxy is the URL of the HTTP proxy, the environment HTTPS_PROXY, HTTP_PROXY and NO_PROXY are used if it is empty.
+	Proxy string
+	// CABundle is the path of a PEM file of the extra CA certificates to trust besides the system ones.
+	CABundle string
+}
+
+// Downloader downloads files by HTTP. The downloads are resumed from the partial files left by the last attempt,
+// and the SHA256 checksum is computed while downloading.
+type Downloader struct {
+	Client   *http.Client
+	Retry    int
+	Backoff  time.Duration
+	Progress io.Writer
+}
+
+// NewDownloader returns a Downloader with the given proxy and CA bundle.
+func NewDownloader(opts DownloaderOptions) (*Downloader, error) {
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		u, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid download proxy %s", opts.Proxy)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	var pool *x509.CertPool
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read the CA bundle %s failed", opts.CABundle)
+		}
+		if pool, err = x509.SystemCertPool(); err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificate is found in the CA bundle %s", opts.CABundle)
+		}
+	}
+
+	return &Downloader{
+		Client:   &http.Client{Transport: newTransport(proxy, pool)},
+		Retry:    defaultDownloadRetry,
+		Backoff:  defaultDownloadBackoff,
+		Progress: os.Stderr,
+	}, nil
+}
+
+// ConfigureDownloader replaces the downloader used by the KubeBinary.
+func ConfigureDownloader(opts DownloaderOptions) error {
+	d, err := NewDownloader(opts)
+	if err != nil {
+		return err
+	}
+	downloaderMu.Lock()
+	defaultDownloader = d
+	downloaderMu.Unlock()
+	return nil
+}
+
+// DefaultDownloader returns the downloader used by the KubeBinary.
+func DefaultDownloader() *Downloader {
+	downloaderMu.RLock()
+	defer downloaderMu.RUnlock()
+	return defaultDownloader
+}
+
+func newTransport(proxy func(*http.Request) (*url.URL, error), pool *x509.CertPool) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxy
+	t.ResponseHeaderTimeout = time.Minute
+	if pool != nil {
+		t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return t
+}
+
+// Download downloads the url to the path. The file is verified against the checksum if it is not empty, and it is
+// written to the path only if the verification passes.
+func (d *Downloader) Download(rawURL, path, checksum string) error {
+	part := path + ".part"
+	backoff := d.Backoff
+
+	var err error
+	for i := 0; i < d.Retry; i++ {
+		if i > 0 {
+			d.printf("retry to download %s after %s: %v\n", rawURL, backoff, err)
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > maxDownloadBackoff {
+				backoff = maxDownloadBackoff
+			}
+		}
+
+		if err = d.fetch(rawURL, part, checksum); err == nil {
+			return errors.Wrapf(os.Rename(part, path), "rename %s failed", part)
+		}
+		if errors.Is(err, errChecksum) {
+			_ = os.Remove(part)
+		}
+	}
+	return errors.Wrapf(err, "download %s failed after %d retries", rawURL, d.Retry)
+}
+
+// fetch downloads the rest of the url into the partial file.
+func (d *Downloader) fetch(rawURL, part, checksum string) error {
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "open %s failed", part)
+	}
+	defer f.Close()
+
+	// the checksum of the downloaded part is computed again, it might be left by another kk process
+	h := sha256.New()
+	offset, err := io.Copy(h, f)
+	if err != nil {
+		return errors.Wrapf(err, "read %s failed", part)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the partial file is complete already
+		return verify(h, checksum)
+	case resp.StatusCode == http.StatusOK:
+		// the server does not support the ranged requests, download it from the beginning
+		if err := f.Truncate(0); err != nil {
+			return errors.Wrapf(err, "truncate %s failed", part)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.Wrapf(err, "seek %s failed", part)
+		}
+		h.Reset()
+		offset = 0
+	default:
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	p := &progress{out: d.Progress, name: filepath.Base(part[:len(part)-len(".part")]), done: offset, total: total}
+	if _, err := io.Copy(io.MultiWriter(f, h, p), resp.Body); err != nil {
+		return errors.Wrapf(err, "download %s failed", rawURL)
+	}
+	p.print()
+	return verify(h, checksum)
+}
+
+func (d *Downloader) printf(format string, a ...interface{}) {
+	if d.Progress != nil {
+		_, _ = fmt.Fprintf(d.Progress, format, a...)
+	}
+}
+
+func verify(h hash.Hash, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != checksum {
+		return errors.Wrapf(errChecksum, "%s not equal %s", checksum, sum)
+	}
+	return nil
+}
+
+// progress prints the progress of a download periodically.
+type progress struct {
+	out   io.Writer
+	name  string
+	done  int64
+	total int64
+	last  time.Time
+}
+
+func (p *progress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if time.Since(p.last) >= progressInterval {
+		p.print()
+	}
+	return len(b), nil
+}
+
+func (p *progress) print() {
+	p.last = time.Now()
+	if p.out == nil {
+		return
+	}
+	if p.total > 0 {
+		_, _ = fmt.Fprintf(p.out, "%s: %.1f/%.1f MiB (%d%%)\n", p.name, mib(p.done), mib(p.total), p.done*100/p.total)
+		return
+	}
+	_, _ = fmt.Fprintf(p.out, "%s: %.1f MiB\n", p.name, mib(p.done))
+}
+
+// extractFile extracts the file with the name in the gzipped tarball to the path.
+func extractFile(tarball, name, path string) error {
+	fr, err := os.Open(tarball)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer fr.Close()
+
+	gr, err := gzip.NewReader(fr)
+	if err != nil {
+		return errors.Wrapf(err, "read %s failed", tarball)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return errors.Errorf("%s is not found in %s", name, tarball)
+		} else if err != nil {
+			return errors.Wrapf(err, "read %s failed", tarball)
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Name != name {
+			continue
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer f.Close()
+		_, err = io.Copy(f, tr)
+		return errors.Wrapf(err, "extract %s failed", name)
+	}
+}
+
+func mib(n int64) float64 {
+	return float64(n) / (1 << 20)
+}
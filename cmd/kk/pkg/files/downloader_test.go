@@ -0,0 +1,113 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package files
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestDownloader(server *httptest.Server) *Downloader {
+	return &Downloader{Client: server.Client(), Retry: 3, Backoff: time.Millisecond}
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloaderResume(t *testing.T) {
+	content := bytes.Repeat([]byte("kubekey"), 4096)
+	var ranged int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			atomic.AddInt32(&ranged, 1)
+		}
+		http.ServeContent(w, r, "kubeadm", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "kubeadm")
+	if err := os.WriteFile(path+".part", content[:1000], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newTestDownloader(server).Download(server.URL, path, checksum(content)); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if ranged != 1 {
+		t.Errorf("expected a ranged request, got %d", ranged)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("the downloaded file is not equal to the content")
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("the partial file should be removed")
+	}
+}
+
+func TestDownloaderRetry(t *testing.T) {
+	content := []byte("kubelet")
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "kubelet")
+	if err := newTestDownloader(server).Download(server.URL, path, checksum(content)); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestDownloaderChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("corrupted"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "kubectl")
+	err := newTestDownloader(server).Download(server.URL, path, checksum([]byte("kubectl")))
+	if err == nil || !strings.Contains(err.Error(), "SHA256 no match") {
+		t.Fatalf("expected a checksum error, got %v", err)
+	}
+	for _, p := range []string{path, path + ".part"} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist", p)
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
 	"github.com/kubesphere/kubekey/v3/version"
@@ -51,6 +52,7 @@ const (
 	containerd = "containerd"
 	runc       = "runc"
 	calicoctl  = "calicoctl"
+	crio       = "crio"
 )
 
 // KubeBinary Type field const
@@ -64,8 +66,13 @@ const (
 	REGISTRY   = "registry"
 	CONTAINERD = "containerd"
 	RUNC       = "runc"
+	CRIO       = "crio"
 )
 
+// checksumSuffix is the suffix of the checksum files published along with the binaries which are not in
+// 'version/components.json'.
+const checksumSuffix = ".sha256sum"
+
 var (
 	// FileSha256 is a hash table the storage the checksum of the binary files. It is parsed from 'version/components.json'.
 	FileSha256 = map[string]map[string]map[string]string{}
@@ -85,6 +92,11 @@ type KubeBinary struct {
 	BaseDir  string
 	Zone     string
 	getCmd   func(path, url string) string
+	// fallbacks are the URLs tried in order if it fails to download from the Url.
+	fallbacks []string
+	// publishedChecksum means that the binary is verified by the checksum published along with it, e.g. the CRI-O
+	// bundles, which follow the Kubernetes releases and are not tracked in 'version/components.json'.
+	publishedChecksum bool
 }
 
 func NewKubeBinary(name, arch, version, prePath string, getCmd func(path, url string) string) *KubeBinary {
@@ -216,6 +228,11 @@ func NewKubeBinary(name, arch, version, prePath string, getCmd func(path, url st
 		if component.Zone == "cn" {
 			component.Url = fmt.Sprintf("https://kubernetes-release.pek3b.qingstor.com/projectcalico/calico/releases/download/%s/calicoctl-linux-%s", version, arch)
 		}
+	case crio:
+		component.Type = CRIO
+		component.FileName = fmt.Sprintf("cri-o.%s.%s.tar.gz", arch, version)
+		component.Url = fmt.Sprintf("https://storage.googleapis.com/cri-o/artifacts/cri-o.%s.%s.tar.gz", arch, version)
+		component.publishedChecksum = true
 	default:
 		logger.Log.Fatalf("unsupported kube binaries %s", name)
 	}
@@ -238,11 +255,19 @@ func (b *KubeBinary) Path() string {
 	return filepath.Join(b.BaseDir, b.FileName)
 }
 
+// GetCmd returns the download command of the binary, or the URL if the built-in downloader is used.
 func (b *KubeBinary) GetCmd() string {
-	cmd := b.getCmd(b.Path(), b.Url)
+	return b.getCmdOf(b.Url)
+}
+
+func (b *KubeBinary) getCmdOf(url string) string {
+	if b.getCmd == nil {
+		return url
+	}
+	cmd := b.getCmd(b.Path(), url)
 
-	if b.ID == helm && b.Zone != "cn" {
-		get := b.getCmd(filepath.Join(b.BaseDir, fmt.Sprintf("helm-%s-linux-%s.tar.gz", b.Version, b.Arch)), b.Url)
+	if b.ID == helm && isTarball(url) {
+		get := b.getCmd(filepath.Join(b.BaseDir, fmt.Sprintf("helm-%s-linux-%s.tar.gz", b.Version, b.Arch)), url)
 		cmd = fmt.Sprintf("%s && cd %s && tar -zxf helm-%s-linux-%s.tar.gz && mv linux-%s/helm . && rm -rf *linux-%s*",
 			get, b.BaseDir, b.Version, b.Arch, b.Arch, b.Arch)
 	}
@@ -251,12 +276,102 @@ func (b *KubeBinary) GetCmd() string {
 
 func (b *KubeBinary) GetSha256() string {
 	s := FileSha256[b.ID][b.Arch][b.Version]
+	if s == "" && b.publishedChecksum {
+		// the published checksum is kept with the binary, so that the binary can be verified offline
+		if data, err := os.ReadFile(b.checksumPath()); err == nil {
+			s = parseChecksum(string(data))
+		}
+	}
 	return s
 }
 
+func (b *KubeBinary) checksumPath() string {
+	return b.Path() + checksumSuffix
+}
+
+// fetchChecksum downloads the checksum published along with the binary at the url.
+func (b *KubeBinary) fetchChecksum(url string) error {
+	if !b.publishedChecksum || FileSha256[b.ID][b.Arch][b.Version] != "" {
+		return nil
+	}
+	if b.getCmd == nil {
+		return DefaultDownloader().Download(url+checksumSuffix, b.checksumPath(), "")
+	}
+	if output, err := exec.Command("/bin/sh", "-c", b.getCmd(b.checksumPath(), url+checksumSuffix)).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "download the checksum of %s failed: %s", b.ID, string(output))
+	}
+	return nil
+}
+
+// parseChecksum returns the checksum in the output of sha256sum, e.g. "<checksum>  <file name>".
+func parseChecksum(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// ApplyMirrors renders the URLs of the binary by the download mirrors. The mirrors which provide the binary are
+// tried in order, and the default URL is the last fallback.
+func (b *KubeBinary) ApplyMirrors(mirrors []kubekeyapiv1alpha2.DownloadMirror) error {
+	data := kubekeyapiv1alpha2.DownloadMirrorData{
+		ID:        b.ID,
+		Version:   b.Version,
+		Arch:      b.Arch,
+		ArchAlias: util.ArchAlias(b.Arch),
+		FileName:  b.FileName,
+	}
+	urls := make([]string, 0, len(mirrors)+1)
+	for _, m := range mirrors {
+		url, ok, err := m.URL(data)
+		if err != nil {
+			return err
+		}
+		if ok {
+			urls = append(urls, url)
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	b.fallbacks = append(urls[1:], b.Url)
+	b.Url = urls[0]
+	return nil
+}
+
+// Download downloads the binary by the built-in downloader, or by the user defined download command if it is set.
+// The fallback URLs of the download mirrors are tried in order if it fails.
 func (b *KubeBinary) Download() error {
+	urls := append([]string{b.Url}, b.fallbacks...)
+
+	var err error
+	for i, url := range urls {
+		if i > 0 {
+			logger.Log.Warningf("Failed to download %s from %s, try the next source %s: %v", b.ID, urls[i-1], url, err)
+		}
+		if err = b.fetchChecksum(url); err != nil {
+			continue
+		}
+		if b.getCmd == nil {
+			err = b.download(url)
+		} else {
+			err = b.downloadByCmd(url)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+
+	if len(b.fallbacks) == 0 && b.Zone != "cn" {
+		logger.Log.Warningln("Having a problem with accessing https://storage.googleapis.com? You can try again after setting environment 'export KKZONE=cn'")
+	}
+	return err
+}
+
+func (b *KubeBinary) downloadByCmd(url string) error {
 	for i := 5; i > 0; i-- {
-		cmd := exec.Command("/bin/sh", "-c", b.GetCmd())
+		cmd := exec.Command("/bin/sh", "-c", b.getCmdOf(url))
 		stdout, err := cmd.StdoutPipe()
 		if err != nil {
 			return err
@@ -278,9 +393,6 @@ func (b *KubeBinary) Download() error {
 			}
 		}
 		if err = cmd.Wait(); err != nil {
-			if os.Getenv("KKZONE") != "cn" {
-				logger.Log.Warningln("Having a problem with accessing https://storage.googleapis.com? You can try again after setting environment 'export KKZONE=cn'")
-			}
 			return err
 		}
 
@@ -297,6 +409,30 @@ func (b *KubeBinary) Download() error {
 	return nil
 }
 
+func (b *KubeBinary) download(url string) error {
+	d := DefaultDownloader()
+	if b.ID == helm && isTarball(url) {
+		tarball := filepath.Join(b.BaseDir, fmt.Sprintf("helm-%s-linux-%s.tar.gz", b.Version, b.Arch))
+		if err := d.Download(url, tarball, ""); err != nil {
+			return err
+		}
+		defer os.Remove(tarball)
+		if err := extractFile(tarball, fmt.Sprintf("linux-%s/helm", b.Arch), b.Path()); err != nil {
+			return err
+		}
+		return b.SHA256Check()
+	}
+
+	if strings.TrimSpace(b.GetSha256()) == "" {
+		return errors.New(fmt.Sprintf("No SHA256 found for %s. %s is not supported.", b.ID, b.Version))
+	}
+	return d.Download(url, b.Path(), b.GetSha256())
+}
+
+func isTarball(url string) bool {
+	return strings.HasSuffix(url, ".tar.gz") || strings.HasSuffix(url, ".tgz")
+}
+
 // SHA256Check is used to hash checks on downloaded binary. (sha256)
 func (b *KubeBinary) SHA256Check() error {
 	output, err := sha256sum(b.Path())
@@ -0,0 +1,96 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package files
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+)
+
+func TestApplyMirrors(t *testing.T) {
+	b := NewKubeBinary("containerd", "arm64", "1.6.4", t.TempDir(), nil)
+	defaultURL := b.Url
+
+	mirrors := []kubekeyapiv1alpha2.DownloadMirror{
+		{Name: "helm-only", URLs: map[string]string{"helm": "https://helm.example.com/{{ .Version }}/helm"}},
+		{Name: "nexus", URLs: map[string]string{"containerd": "https://nexus.example.com/{{ .ArchAlias }}/v{{ .Version }}/{{ .FileName }}"}},
+		{Name: "artifactory", URLs: map[string]string{"containerd": "https://artifactory.example.com/{{ .ID }}/{{ .Arch }}"}},
+	}
+	if err := b.ApplyMirrors(mirrors); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Url != "https://nexus.example.com/aarch64/v1.6.4/containerd-1.6.4-linux-arm64.tar.gz" {
+		t.Errorf("unexpected url %s", b.Url)
+	}
+	expected := []string{"https://artifactory.example.com/containerd/arm64", defaultURL}
+	if !reflect.DeepEqual(b.fallbacks, expected) {
+		t.Errorf("expected fallbacks %v, got %v", expected, b.fallbacks)
+	}
+}
+
+func TestApplyMirrorsInvalidTemplate(t *testing.T) {
+	b := NewKubeBinary("kubeadm", "amd64", "v1.24.0", t.TempDir(), nil)
+	mirrors := []kubekeyapiv1alpha2.DownloadMirror{
+		{Name: "broken", URLs: map[string]string{"kubeadm": "https://example.com/{{ .Unknown }}"}},
+	}
+	if err := b.ApplyMirrors(mirrors); err == nil {
+		t.Fatal("expected an error of the invalid template")
+	}
+}
+
+func TestDownloadPublishedChecksum(t *testing.T) {
+	content := []byte("cri-o bundle")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, checksumSuffix) {
+			_, _ = w.Write([]byte(checksum(content) + "  cri-o.amd64.v1.26.1.tar.gz\n"))
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	downloaderMu.Lock()
+	origin := defaultDownloader
+	defaultDownloader = newTestDownloader(server)
+	downloaderMu.Unlock()
+	defer func() {
+		downloaderMu.Lock()
+		defaultDownloader = origin
+		downloaderMu.Unlock()
+	}()
+
+	b := NewKubeBinary("crio", "amd64", "v1.26.1", t.TempDir(), nil)
+	b.Url = server.URL + "/cri-o.amd64.v1.26.1.tar.gz"
+	if err := b.CreateBaseDir(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Download(); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if b.GetSha256() != checksum(content) {
+		t.Errorf("expected the published checksum %s, got %s", checksum(content), b.GetSha256())
+	}
+	if err := b.SHA256Check(); err != nil {
+		t.Errorf("the downloaded binary should be verified offline: %v", err)
+	}
+}
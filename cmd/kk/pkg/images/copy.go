@@ -18,21 +18,39 @@ package images
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/containers/image/v5/copy"
 	"github.com/containers/image/v5/signature"
 	"github.com/containers/image/v5/transports/alltransports"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	sourceImageAnnotation    = "io.kubesphere.kubekey.image.source"
+	verifiedPolicyAnnotation = "io.kubesphere.kubekey.image.policy"
 )
 
 type CopyImageOptions struct {
 	srcImage           *srcImageOptions
 	destImage          *destImageOptions
 	imageListSelection copy.ImageListSelection
+	// policy is the signature policy which the source image is verified with, every image is accepted if it is nil
+	policy *SignaturePolicy
+	// removeSignatures removes the signatures of the source image, the OCI layout can not store them
+	removeSignatures bool
+	// preserveDigests fails the copy if the digest of the image would be changed, so that its signature is kept valid
+	preserveDigests bool
 }
 
 func (c *CopyImageOptions) Copy() error {
-	policyContext, err := getPolicyContext()
+	policyContext, err := c.policy.policyContext()
 	if err != nil {
 		return err
 	}
@@ -55,6 +73,8 @@ func (c *CopyImageOptions) Copy() error {
 		SourceCtx:          srcContext,
 		DestinationCtx:     destContext,
 		ImageListSelection: c.imageListSelection,
+		RemoveSignatures:   c.removeSignatures,
+		PreserveDigests:    c.preserveDigests,
 	})
 	if err != nil {
 		return err
@@ -77,6 +97,42 @@ type Manifest struct {
 
 type annotations struct {
 	RefName string `json:"org.opencontainers.image.ref.name"`
+	// Source is the name of the image which is pulled into the artifact.
+	Source string `json:"io.kubesphere.kubekey.image.source,omitempty"`
+	// Policy is the digest of the signature policy which the image is verified by when it is pulled.
+	Policy string `json:"io.kubesphere.kubekey.image.policy,omitempty"`
+}
+
+// annotateIndex adds the annotations to the images of the refs in the index.json of the OCI layout, the index is
+// decoded as an OCI index so that the other fields are kept.
+func annotateIndex(dir string, refs map[string]annotations) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	path := filepath.Join(dir, "index.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(errors.WithStack(err), "read %s failed", path)
+	}
+	index := new(imgspecv1.Index)
+	if err := json.Unmarshal(data, index); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "unmarshal %s failed", path)
+	}
+	for i := range index.Manifests {
+		m := &index.Manifests[i]
+		a, ok := refs[m.Annotations[imgspecv1.AnnotationRefName]]
+		if !ok {
+			continue
+		}
+		m.Annotations[sourceImageAnnotation] = a.Source
+		if a.Policy != "" {
+			m.Annotations[verifiedPolicyAnnotation] = a.Policy
+		}
+	}
+	if data, err = json.Marshal(index); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.WriteFile(path, data, 0644))
 }
 
 func NewIndex() *Index {
@@ -84,3 +140,39 @@ func NewIndex() *Index {
 		Manifests: []Manifest{},
 	}
 }
+
+// IndexImages returns the images in the OCI index of the images dir of an artifact by platform, such as amd64 or
+// arm/v7, the images are named like kubesphere/kube-apiserver:v1.23.10. The cosign signatures are not returned.
+func IndexImages(data []byte) (map[string][]string, error) {
+	index := NewIndex()
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "unmarshal index.json failed")
+	}
+
+	images := make(map[string][]string)
+	for _, m := range index.Manifests {
+		// Ex:
+		// calico:cni:v3.20.0-amd64
+		ref := m.Annotations.RefName
+		nameArr := strings.Split(ref, ":")
+		if len(nameArr) != 3 {
+			return nil, errors.Errorf("invalid ref name: %s", ref)
+		}
+		if cosignSignatureTagRegexp.MatchString(nameArr[2]) {
+			continue
+		}
+		name, p, err := parseImageWithArchTag(fmt.Sprintf("%s/%s:%s", nameArr[0], nameArr[1], nameArr[2]))
+		if err != nil {
+			return nil, err
+		}
+		platform := p.Architecture
+		if p.Variant != "" {
+			platform = fmt.Sprintf("%s/%s", p.Architecture, p.Variant)
+		}
+		images[platform] = append(images[platform], name)
+	}
+	for _, names := range images {
+		sort.Strings(names)
+	}
+	return images, nil
+}
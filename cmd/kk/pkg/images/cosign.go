@@ -0,0 +1,213 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils/certs"
+)
+
+// cosignSignatureAnnotation is the annotation of the layers of a cosign signature image, it is the base64 signature
+// of the layer which is the simple signing payload.
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// cosignPayload is the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// cosignSignatureTagRegexp matches the tags of the cosign signature images.
+var cosignSignatureTagRegexp = regexp.MustCompile(`^sha256-[0-9a-f]{64}\.sig$`)
+
+// cosignSignatureTag returns the tag of the cosign signature image of the manifest digest, it is stored in the same
+// repository as the image.
+func cosignSignatureTag(d digest.Digest) string {
+	return fmt.Sprintf("%s-%s.sig", d.Algorithm(), d.Hex())
+}
+
+// manifestDigest returns the digest of the manifest of the image, and the digest of the manifest of the platform
+// chosen by the system context if the image is a manifest list.
+func manifestDigest(ctx context.Context, ref types.ImageReference, sys *types.SystemContext) (digest.Digest, digest.Digest, string, error) {
+	src, err := ref.NewImageSource(ctx, sys)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer src.Close()
+
+	data, mimeType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+	d, err := manifest.Digest(data)
+	if err != nil {
+		return "", "", "", err
+	}
+	if !manifest.MIMETypeIsMultiImage(mimeType) {
+		return d, d, mimeType, nil
+	}
+	list, err := manifest.ListFromBlob(data, mimeType)
+	if err != nil {
+		return "", "", "", err
+	}
+	instance, err := list.ChooseInstance(sys)
+	if err != nil {
+		return "", "", "", err
+	}
+	_, instanceType, err := src.GetManifest(ctx, &instance)
+	if err != nil {
+		return "", "", "", err
+	}
+	return d, instance, instanceType, nil
+}
+
+// verifyCosignSignature verifies that one of the signatures in the cosign signature image signs the digest with the
+// public key.
+func verifyCosignSignature(ctx context.Context, sigRef types.ImageReference, sys *types.SystemContext, d digest.Digest, key crypto.PublicKey) error {
+	src, err := sigRef.NewImageSource(ctx, sys)
+	if err != nil {
+		return errors.Wrapf(err, "no cosign signature of %s is found", d)
+	}
+	defer src.Close()
+
+	data, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "no cosign signature of %s is found", d)
+	}
+	m, err := manifest.OCI1FromManifest(data)
+	if err != nil {
+		return errors.Wrapf(err, "invalid cosign signature of %s", d)
+	}
+	for _, layer := range m.Layers {
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+		blob, _, err := src.GetBlob(ctx, types.BlobInfo{Digest: layer.Digest, Size: layer.Size}, none.NoCache)
+		if err != nil {
+			return errors.Wrapf(err, "read the cosign signature of %s failed", d)
+		}
+		payload, err := io.ReadAll(blob)
+		blob.Close()
+		if err != nil {
+			return errors.Wrapf(err, "read the cosign signature of %s failed", d)
+		}
+		if digest.FromBytes(payload) != layer.Digest || certs.VerifySignature(key, payload, sig) != nil {
+			continue
+		}
+		p := new(cosignPayload)
+		if err := json.Unmarshal(payload, p); err == nil && p.Critical.Image.DockerManifestDigest == d.String() {
+			return nil
+		}
+	}
+	return errors.Errorf("no cosign signature of %s is signed by the key", d)
+}
+
+// verifySourceImage verifies the cosign signatures of the image in the registry which are required by the policy. It
+// returns the digest which is verified, the image must be copied by it, so that the image can not be changed after it
+// is verified. It also returns the digest of the platform image if its signatures can be carried into the OCI layout,
+// the digest of an OCI image is kept by the copy, but a Docker image is converted.
+func verifySourceImage(ctx context.Context, policy *SignaturePolicy, name string, sys *types.SystemContext) (digest.Digest, digest.Digest, error) {
+	keys, err := policy.sigstoreKeys(name)
+	if err != nil || len(keys) == 0 {
+		return "", "", err
+	}
+	ref, err := docker.ParseReference("//" + name)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "invalid image %s", name)
+	}
+	top, instance, instanceType, err := manifestDigest(ctx, ref, sys)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "get the manifest of %s failed", name)
+	}
+
+	repo := reference.TrimNamed(ref.DockerReference()).Name()
+	verify := func(d digest.Digest, key crypto.PublicKey) error {
+		sigRef, err := docker.ParseReference("//" + repo + ":" + cosignSignatureTag(d))
+		if err != nil {
+			return err
+		}
+		return verifyCosignSignature(ctx, sigRef, sys, d, key)
+	}
+	carried := instanceType == imgspecv1.MediaTypeImageManifest
+	for _, key := range keys {
+		err := verify(instance, key)
+		if err == nil {
+			continue
+		}
+		// the signature of the manifest list also covers the platform image in it
+		carried = false
+		if top == instance || verify(top, key) != nil {
+			return "", "", errors.Wrapf(err, "the image %s is refused by the signature policy", name)
+		}
+	}
+	if !carried {
+		logger.Log.Warningf("The cosign signature of %s is verified but not carried into the artifact, only the signatures of the OCI platform images are carried", name)
+		return top, "", nil
+	}
+	return top, instance, nil
+}
+
+// verifyLayoutImage enforces the requirements of the scope of the source image on the image with the digest in the
+// OCI layout. The rejections and the sigstoreSigned requirements with the cosign signatures carried in the layout are
+// always verified, the sigRef is nil if no signature is carried. The other requirements, such as signedBy, can not be
+// verified with the layout, they are only accepted if the image is verified by the same policy when it is exported and
+// the signature of the artifact is verified, which the annotations of the layout are bound to.
+func verifyLayoutImage(ctx context.Context, policy *SignaturePolicy, source string, d digest.Digest, sigRef types.ImageReference, verified bool) error {
+	if policy == nil {
+		return nil
+	}
+	reqs, err := policy.requirements(source)
+	if err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		switch {
+		case req.Type == insecureAcceptAnything:
+		case req.Type == reject:
+			return errors.Errorf("the image %s is rejected by the signature policy", source)
+		case req.Type == sigstoreSigned && sigRef != nil:
+			if err := verifyCosignSignature(ctx, sigRef, nil, d, req.key); err != nil {
+				return errors.Wrapf(err, "the image %s is refused by the signature policy", source)
+			}
+		case !verified:
+			return errors.Errorf("the image %s is refused by the signature policy, the requirement %s can not be verified with the artifact "+
+				"unless the image is verified by the policy when the artifact is exported and the artifact is verified by --artifact-key or --artifact-ca", source, req.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package images
+
+import (
+	"sort"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+)
+
+// controlPlaneImages are the images of the control plane created by kubeadm, k3s and k8e run it from the binaries.
+var controlPlaneImages = map[string]bool{
+	"etcd":                    true,
+	"kube-apiserver":          true,
+	"kube-controller-manager": true,
+	"kube-scheduler":          true,
+	"kube-proxy":              true,
+}
+
+// ClusterImages returns the full names of the images deployed by kk to the cluster with the number of Kubernetes
+// nodes. The cluster must be set with the defaults. The images are named by their source registries instead of the
+// private registry of the cluster, so that they can be pulled into an artifact. The images of OpenEBS are only
+// included with localStorage.
+func ClusterImages(cluster *kubekeyv1alpha2.ClusterSpec, k8sNodes int, localStorage bool) []string {
+	source := *cluster
+	source.Registry = kubekeyv1alpha2.RegistryConfig{}
+
+	names := make([]string, 0)
+	for name, image := range imageList(&common.KubeConf{Cluster: &source}, k8sNodes) {
+		switch {
+		case name == "provisioner-localpv" || name == "linux-utils":
+			image.Enable = localStorage
+		case name == "kata-deploy":
+			// kata is only deployed with containerd and cri-o
+			image.Enable = image.Enable && (cluster.Kubernetes.ContainerManager == common.Containerd || cluster.Kubernetes.ContainerManager == common.Crio)
+		case controlPlaneImages[name]:
+			image.Enable = image.Enable && cluster.Kubernetes.Type != common.K3s && cluster.Kubernetes.Type != common.K8e
+		}
+		if !image.Enable {
+			continue
+		}
+		image.RepoAddr, image.NamespaceOverride = image.ImageRegistryAddr(), image.ImageNamespace()
+		names = append(names, image.ImageName())
+	}
+	sort.Strings(names)
+	return names
+}
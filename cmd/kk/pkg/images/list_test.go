@@ -0,0 +1,81 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package images
+
+import (
+	"testing"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+)
+
+func TestClusterImages(t *testing.T) {
+	logger.Log = logger.NewLogger(t.TempDir(), false)
+	tests := []struct {
+		name         string
+		modify       func(cluster *kubekeyv1alpha2.ClusterSpec)
+		localStorage bool
+		include      []string
+		exclude      []string
+	}{
+		{
+			name:    "kubernetes",
+			modify:  func(cluster *kubekeyv1alpha2.ClusterSpec) {},
+			include: []string{"docker.io/kubesphere/kube-apiserver:v1.23.10", "docker.io/calico/node:v3.26.1", "docker.io/coredns/coredns:1.8.6"},
+			exclude: []string{"docker.io/openebs/provisioner-localpv:3.3.0"},
+		},
+		{
+			name: "k3s with the local storage",
+			modify: func(cluster *kubekeyv1alpha2.ClusterSpec) {
+				cluster.Kubernetes.Version = "v1.21.4-k3s"
+				cluster.Network.Plugin = "flannel"
+			},
+			localStorage: true,
+			include:      []string{"docker.io/openebs/provisioner-localpv:3.3.0", "docker.io/flannel/flannel:v0.21.3"},
+			exclude:      []string{"docker.io/kubesphere/kube-apiserver:v1.21.4", "docker.io/calico/node:v3.26.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &kubekeyv1alpha2.ClusterSpec{
+				Hosts:      []kubekeyv1alpha2.HostCfg{{Name: "node1", Address: "172.16.0.2"}},
+				RoleGroups: map[string][]string{"etcd": {"node1"}, "control-plane": {"node1"}, "worker": {"node1"}},
+				Kubernetes: kubekeyv1alpha2.Kubernetes{Version: "v1.23.10", ContainerManager: "containerd"},
+				Registry:   kubekeyv1alpha2.RegistryConfig{PrivateRegistry: "dockerhub.kubekey.local"},
+			}
+			tt.modify(cluster)
+			spec, _, err := cluster.SetDefaultClusterSpec()
+			if err != nil {
+				t.Fatal(err)
+			}
+			images := make(map[string]bool)
+			for _, image := range ClusterImages(spec, 1, tt.localStorage) {
+				images[image] = true
+			}
+			for _, image := range tt.include {
+				if !images[image] {
+					t.Errorf("ClusterImages() does not include %s", image)
+				}
+			}
+			for _, image := range tt.exclude {
+				if images[image] {
+					t.Errorf("ClusterImages() includes %s", image)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package images
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/signature"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils/certs"
+)
+
+const (
+	// SignaturePolicyFile is the signature policy carried in the images dir of an artifact, the keys of it are inlined.
+	SignaturePolicyFile = "signature-policy.json"
+
+	insecureAcceptAnything = "insecureAcceptAnything"
+	reject                 = "reject"
+	// sigstoreSigned is the requirement of the cosign signatures, it is verified by kk instead of containers/image.
+	sigstoreSigned = "sigstoreSigned"
+)
+
+// policyFile is the format of containers-policy.json.
+type policyFile struct {
+	Default    []map[string]interface{}                       `json:"default"`
+	Transports map[string]map[string][]map[string]interface{} `json:"transports,omitempty"`
+}
+
+// requirement is a requirement of the signature policy, the key is only parsed for sigstoreSigned.
+type requirement struct {
+	Type string
+	key  crypto.PublicKey
+}
+
+// SignaturePolicy is a containers-policy.json which the images are verified with when they are copied. Besides the
+// requirements supported by containers/image, such as signedBy with the GPG keys, the sigstoreSigned requirement with
+// the cosign public keys is supported.
+type SignaturePolicy struct {
+	file   policyFile
+	policy *signature.Policy
+	// scopes are the requirements of the docker transport, the default requirements are keyed by ""
+	scopes map[string][]requirement
+}
+
+// LoadSignaturePolicy loads the containers-policy.json, the keys of the requirements are inlined as keyData.
+func LoadSignaturePolicy(path string) (*SignaturePolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	p := &SignaturePolicy{scopes: make(map[string][]requirement)}
+	if err := json.Unmarshal(data, &p.file); err != nil {
+		return nil, errors.Wrapf(err, "decode the signature policy %s failed", path)
+	}
+
+	// containers/image evaluates the policy without sigstoreSigned, it is replaced by insecureAcceptAnything
+	evaluated := policyFile{Transports: make(map[string]map[string][]map[string]interface{})}
+	if evaluated.Default, p.scopes[""], err = parseRequirements(p.file.Default); err != nil {
+		return nil, errors.Wrapf(err, "invalid default requirements of the signature policy %s", path)
+	}
+	for transport, scopes := range p.file.Transports {
+		evaluated.Transports[transport] = make(map[string][]map[string]interface{})
+		for scope, reqs := range scopes {
+			var parsed []requirement
+			if evaluated.Transports[transport][scope], parsed, err = parseRequirements(reqs); err != nil {
+				return nil, errors.Wrapf(err, "invalid requirements of the scope %q of the signature policy %s", scope, path)
+			}
+			// the scope "" of the docker transport overrides the default requirements
+			if transport == docker.Transport.Name() {
+				p.scopes[scope] = parsed
+			}
+		}
+	}
+	data, err = json.Marshal(evaluated)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if p.policy, err = signature.NewPolicyFromBytes(data); err != nil {
+		return nil, errors.Wrapf(err, "invalid signature policy %s", path)
+	}
+	return p, nil
+}
+
+// parseRequirements inlines the keys of the requirements, and returns the requirements evaluated by containers/image
+// and the parsed ones.
+func parseRequirements(reqs []map[string]interface{}) ([]map[string]interface{}, []requirement, error) {
+	evaluated := make([]map[string]interface{}, 0, len(reqs))
+	parsed := make([]requirement, 0, len(reqs))
+	for _, req := range reqs {
+		if path, ok := req["keyPath"].(string); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			delete(req, "keyPath")
+			req["keyData"] = base64.StdEncoding.EncodeToString(data)
+		}
+		r := requirement{}
+		r.Type, _ = req["type"].(string)
+		if r.Type != sigstoreSigned {
+			evaluated = append(evaluated, req)
+			parsed = append(parsed, r)
+			continue
+		}
+
+		keyData, _ := req["keyData"].(string)
+		data, err := base64.StdEncoding.DecodeString(keyData)
+		if err != nil || len(data) == 0 {
+			return nil, nil, errors.Errorf("%s requires the cosign public key in keyPath or keyData", sigstoreSigned)
+		}
+		if r.key, err = certs.ParsePublicKeyPEM(data); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid public key of %s", sigstoreSigned)
+		}
+		evaluated = append(evaluated, map[string]interface{}{"type": insecureAcceptAnything})
+		parsed = append(parsed, r)
+	}
+	return evaluated, parsed, nil
+}
+
+// Save writes the policy with the inlined keys, so that it can be carried with the images.
+func (p *SignaturePolicy) Save(path string) error {
+	data, err := json.MarshalIndent(p.file, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.WriteFile(path, data, 0644))
+}
+
+// digest returns the digest of the policy with the inlined keys, it identifies the policy which an image is verified
+// by when it is exported.
+func (p *SignaturePolicy) digest() (digest.Digest, error) {
+	data, err := json.Marshal(p.file)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return digest.FromBytes(data), nil
+}
+
+// requirements returns the requirements of the docker image, it is looked up like containers/image: the scope of the
+// exact reference, the scopes of the repository and its namespaces, and then the default.
+func (p *SignaturePolicy) requirements(name string) ([]requirement, error) {
+	ref, err := docker.ParseReference("//" + name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image %s", name)
+	}
+	scopes := append([]string{ref.PolicyConfigurationIdentity()}, ref.PolicyConfigurationNamespaces()...)
+	for _, scope := range append(scopes, "") {
+		if reqs, ok := p.scopes[scope]; ok {
+			return reqs, nil
+		}
+	}
+	return nil, nil
+}
+
+// sigstoreKeys returns the cosign public keys which the docker image must be signed with, each of them is required.
+func (p *SignaturePolicy) sigstoreKeys(name string) ([]crypto.PublicKey, error) {
+	if p == nil {
+		return nil, nil
+	}
+	reqs, err := p.requirements(name)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]crypto.PublicKey, 0)
+	for _, req := range reqs {
+		if req.Type == sigstoreSigned {
+			keys = append(keys, req.key)
+		}
+	}
+	return keys, nil
+}
+
+// uncarriedRequirements returns the types of the requirements of the docker image whose signatures can not be carried
+// in the OCI layout, such as signedBy with the GPG signatures.
+func (p *SignaturePolicy) uncarriedRequirements(name string) ([]string, error) {
+	if p == nil {
+		return nil, nil
+	}
+	reqs, err := p.requirements(name)
+	if err != nil {
+		return nil, err
+	}
+	kinds := make([]string, 0)
+	for _, req := range reqs {
+		if req.Type != insecureAcceptAnything && req.Type != reject && req.Type != sigstoreSigned {
+			kinds = append(kinds, req.Type)
+		}
+	}
+	return kinds, nil
+}
+
+// policyContext returns the policy context of containers/image, every image is accepted without the policy.
+func (p *SignaturePolicy) policyContext() (*signature.PolicyContext, error) {
+	if p == nil {
+		return getPolicyContext()
+	}
+	return signature.NewPolicyContext(p.policy)
+}
@@ -0,0 +1,241 @@
+/*
+ Copyright 2022 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func writePublicKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cosign.pub")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func writePolicy(t *testing.T, keyPath string) string {
+	policy := fmt.Sprintf(`{
+  "default": [{"type": "insecureAcceptAnything"}],
+  "transports": {
+    "docker": {
+      "": [{"type": "reject"}],
+      "docker.io/kubesphere": [{"type": "sigstoreSigned", "keyPath": %q}],
+      "docker.io/kubesphere/ks-installer:v3.3.2": [{"type": "insecureAcceptAnything"}],
+      "docker.io/library": [{"type": "signedBy", "keyType": "GPGKeys", "keyData": "Z3Bn"}]
+    },
+    "docker-daemon": {"": [{"type": "insecureAcceptAnything"}]}
+  }
+}`, keyPath)
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSignaturePolicy(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadSignaturePolicy(writePolicy(t, writePublicKey(t, key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		image string
+		want  string
+		keys  int
+	}{
+		{image: "docker.io/kubesphere/ks-apiserver:v3.3.2", want: sigstoreSigned, keys: 1},
+		{image: "docker.io/kubesphere/ks-installer:v3.3.2", want: insecureAcceptAnything},
+		{image: "docker.io/calico/cni:v3.23.2", want: reject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			reqs, err := policy.requirements(tt.image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(reqs) != 1 || reqs[0].Type != tt.want {
+				t.Errorf("requirements() = %v, want %s", reqs, tt.want)
+			}
+			keys, err := policy.sigstoreKeys(tt.image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != tt.keys {
+				t.Errorf("sigstoreKeys() returns %d keys, want %d", len(keys), tt.keys)
+			}
+		})
+	}
+
+	// the saved policy is loaded without the key file
+	saved := filepath.Join(t.TempDir(), SignaturePolicyFile)
+	if err := policy.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSignaturePolicy(saved)
+	if err != nil {
+		t.Fatalf("LoadSignaturePolicy() of the saved policy failed: %v", err)
+	}
+	// the images verified at export are identified by the digest of the carried policy
+	want, err := policy.digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := loaded.digest(); err != nil || got != want {
+		t.Errorf("digest() of the saved policy = %s, want %s", got, want)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"default": [{"type": "sigstoreSigned"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSignaturePolicy(invalid); err == nil {
+		t.Error("LoadSignaturePolicy() of sigstoreSigned without a key succeeded")
+	}
+}
+
+// writeBlob writes the blob into the OCI layout, and returns its descriptor.
+func writeBlob(t *testing.T, dir, mediaType string, data []byte) imgspecv1.Descriptor {
+	d := digest.FromBytes(data)
+	if err := os.WriteFile(filepath.Join(dir, "blobs", "sha256", d.Hex()), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return imgspecv1.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(data))}
+}
+
+// writeSignature writes a cosign signature image of the digest signed by the key into a new OCI layout.
+func writeSignature(t *testing.T, key *ecdsa.PrivateKey, d digest.Digest) string {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion": "1.0.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"docker.io/kubesphere/ks-apiserver"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, d))
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := writeBlob(t, dir, "application/vnd.dev.cosign.simplesigning.v1+json", payload)
+	layer.Annotations = map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)}
+	m := imgspecv1.Manifest{
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config:    writeBlob(t, dir, imgspecv1.MediaTypeImageConfig, []byte(`{"architecture":"","os":"","rootfs":{"type":"layers","diff_ids":[]}}`)),
+		Layers:    []imgspecv1.Descriptor{layer},
+	}
+	m.SchemaVersion = 2
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := writeBlob(t, dir, imgspecv1.MediaTypeImageManifest, data)
+	desc.Annotations = map[string]string{imgspecv1.AnnotationRefName: "kubesphere:ks-apiserver:" + cosignSignatureTag(d)}
+	index := imgspecv1.Index{MediaType: imgspecv1.MediaTypeImageIndex, Manifests: []imgspecv1.Descriptor{desc}}
+	index.SchemaVersion = 2
+	if data, err = json.Marshal(index); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestVerifyLayoutImage(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadSignaturePolicy(writePolicy(t, writePublicKey(t, key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := digest.FromString("ks-apiserver")
+	sigRef := func(key *ecdsa.PrivateKey, d digest.Digest) func(t *testing.T) string {
+		return func(t *testing.T) string {
+			return fmt.Sprintf("oci:%s:kubesphere:ks-apiserver:%s", writeSignature(t, key, d), cosignSignatureTag(d))
+		}
+	}
+	tests := []struct {
+		name     string
+		image    string
+		sig      func(t *testing.T) string
+		verified bool
+		wantErr  bool
+	}{
+		{name: "signed", image: "docker.io/kubesphere/ks-apiserver:v3.3.2", sig: sigRef(key, signed)},
+		{name: "no signature", image: "docker.io/kubesphere/ks-apiserver:v3.3.2", wantErr: true},
+		{name: "no signature verified at export", image: "docker.io/kubesphere/ks-apiserver:v3.3.2", verified: true},
+		{name: "other key", image: "docker.io/kubesphere/ks-apiserver:v3.3.2", sig: sigRef(other, signed), wantErr: true},
+		{name: "other key verified at export", image: "docker.io/kubesphere/ks-apiserver:v3.3.2", sig: sigRef(other, signed), verified: true, wantErr: true},
+		{name: "other digest", image: "docker.io/kubesphere/ks-apiserver:v3.3.2", sig: sigRef(key, digest.FromString("other")), wantErr: true},
+		{name: "accepted", image: "docker.io/kubesphere/ks-installer:v3.3.2"},
+		{name: "rejected", image: "docker.io/calico/cni:v3.23.2", sig: sigRef(key, signed), verified: true, wantErr: true},
+		{name: "gpg", image: "docker.io/library/haproxy:2.3", wantErr: true},
+		{name: "gpg verified at export", image: "docker.io/library/haproxy:2.3", verified: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.sig == nil {
+				err = verifyLayoutImage(context.Background(), policy, tt.image, signed, nil, tt.verified)
+			} else {
+				ref, parseErr := alltransports.ParseImageName(tt.sig(t))
+				if parseErr != nil {
+					t.Fatal(parseErr)
+				}
+				err = verifyLayoutImage(context.Background(), policy, tt.image, signed, ref, tt.verified)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyLayoutImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
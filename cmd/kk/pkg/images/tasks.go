@@ -17,6 +17,7 @@
 package images
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -24,8 +25,11 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
 	manifestregistry "github.com/estesp/manifest-tool/v2/pkg/registry"
 	manifesttypes "github.com/estesp/manifest-tool/v2/pkg/types"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 
@@ -75,7 +79,15 @@ func (p *PullImage) Execute(runtime connector.Runtime) error {
 
 // GetImage defines the list of all images and gets image object by name.
 func GetImage(runtime connector.ModuleRuntime, kubeConf *common.KubeConf, name string) Image {
-	var image Image
+	image := imageList(kubeConf, len(runtime.GetHostsByRole(common.K8s)))[name]
+	if kubeConf.Cluster.Registry.NamespaceOverride != "" {
+		image.NamespaceOverride = kubeConf.Cluster.Registry.NamespaceOverride
+	}
+	return image
+}
+
+// imageList returns all the images of the cluster which has the number of Kubernetes nodes.
+func imageList(kubeConf *common.KubeConf, k8sNodes int) map[string]Image {
 	pauseTag, corednsTag := "3.2", "1.6.9"
 
 	if versionutil.MustParseSemantic(kubeConf.Cluster.Kubernetes.Version).LessThan(versionutil.MustParseSemantic("v1.21.0")) {
@@ -121,14 +133,14 @@ func GetImage(runtime connector.ModuleRuntime, kubeConf *common.KubeConf, name s
 		"calico-cni":              {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "calico", Repo: "cni", Tag: kubekeyv1alpha2.DefaultCalicoVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "calico")},
 		"calico-node":             {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "calico", Repo: "node", Tag: kubekeyv1alpha2.DefaultCalicoVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "calico")},
 		"calico-flexvol":          {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "calico", Repo: "pod2daemon-flexvol", Tag: kubekeyv1alpha2.DefaultCalicoVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "calico")},
-		"calico-typha":            {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "calico", Repo: "typha", Tag: kubekeyv1alpha2.DefaultCalicoVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "calico") && len(runtime.GetHostsByRole(common.K8s)) > 50},
+		"calico-typha":            {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "calico", Repo: "typha", Tag: kubekeyv1alpha2.DefaultCalicoVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "calico") && k8sNodes > 50},
 		"flannel":                 {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "flannel", Repo: "flannel", Tag: kubekeyv1alpha2.DefaultFlannelVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "flannel")},
 		"flannel-cni-plugin":      {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "flannel", Repo: "flannel-cni-plugin", Tag: kubekeyv1alpha2.DefaultFlannelCniPluginVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "flannel")},
 		"cilium":                  {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "cilium", Repo: "cilium", Tag: kubekeyv1alpha2.DefaultCiliumVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "cilium")},
 		"cilium-operator-generic": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "cilium", Repo: "operator-generic", Tag: kubekeyv1alpha2.DefaultCiliumVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "cilium")},
 		"hybridnet":               {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "hybridnetdev", Repo: "hybridnet", Tag: kubekeyv1alpha2.DefaulthybridnetVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "hybridnet")},
 		"kubeovn":                 {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "kubeovn", Repo: "kube-ovn", Tag: kubekeyv1alpha2.DefaultKubeovnVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.EqualFold(kubeConf.Cluster.Network.Plugin, "kubeovn")},
-		"multus":                  {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: kubekeyv1alpha2.DefaultKubeImageNamespace, Repo: "multus-cni", Tag: kubekeyv1alpha2.DefalutMultusVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.Contains(kubeConf.Cluster.Network.Plugin, "multus")},
+		"multus":                  {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: kubekeyv1alpha2.DefaultKubeImageNamespace, Repo: "multus-cni", Tag: kubekeyv1alpha2.DefalutMultusVersion, Group: kubekeyv1alpha2.K8s, Enable: strings.Contains(kubeConf.Cluster.Network.Plugin, "multus") || kubeConf.Cluster.Network.EnableMultusCNI()},
 		// storage
 		"provisioner-localpv": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "openebs", Repo: "provisioner-localpv", Tag: "3.3.0", Group: kubekeyv1alpha2.Worker, Enable: false},
 		"linux-utils":         {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: "openebs", Repo: "linux-utils", Tag: "3.3.0", Group: kubekeyv1alpha2.Worker, Enable: false},
@@ -140,12 +152,7 @@ func GetImage(runtime connector.ModuleRuntime, kubeConf *common.KubeConf, name s
 		// node-feature-discovery
 		"node-feature-discovery": {RepoAddr: kubeConf.Cluster.Registry.PrivateRegistry, Namespace: kubekeyv1alpha2.DefaultKubeImageNamespace, Repo: "node-feature-discovery", Tag: "v0.10.0", Group: kubekeyv1alpha2.K8s, Enable: kubeConf.Cluster.Kubernetes.EnableNodeFeatureDiscovery()},
 	}
-
-	image = ImageList[name]
-	if kubeConf.Cluster.Registry.NamespaceOverride != "" {
-		image.NamespaceOverride = kubeConf.Cluster.Registry.NamespaceOverride
-	}
-	return image
+	return ImageList
 }
 
 type SaveImages struct {
@@ -159,6 +166,30 @@ func (s *SaveImages) Execute(runtime connector.Runtime) error {
 	if err := coreutil.Mkdir(dirName); err != nil {
 		return errors.Wrapf(errors.WithStack(err), "mkdir %s failed", dirName)
 	}
+
+	policyPath := s.Manifest.Spec.SignaturePolicy
+	if s.Manifest.Arg.SignaturePolicy != "" {
+		policyPath = s.Manifest.Arg.SignaturePolicy
+	}
+	var policy *SignaturePolicy
+	var policyDigest digest.Digest
+	if policyPath != "" {
+		var err error
+		if policy, err = LoadSignaturePolicy(policyPath); err != nil {
+			return err
+		}
+		// the policy is carried with the images, so that it is enforced again when they are pushed
+		if err := policy.Save(filepath.Join(dirName, SignaturePolicyFile)); err != nil {
+			return errors.Wrap(err, "save the signature policy failed")
+		}
+		if policyDigest, err = policy.digest(); err != nil {
+			return err
+		}
+	}
+
+	// the images are annotated with their sources and the policy which they are verified by
+	refs := make(map[string]annotations)
+
 	for _, image := range s.Manifest.Spec.Images {
 		if err := validateImageName(image); err != nil {
 			return err
@@ -180,7 +211,8 @@ func (s *SaveImages) Execute(runtime connector.Runtime) error {
 			// Ex:
 			// oci:./kubekey/artifact/images:kubesphere:kube-apiserver:v1.21.5-amd64
 			// oci:./kubekey/artifact/images:kubesphere:kube-apiserver:v1.21.5-arm-v7
-			destName := fmt.Sprintf("oci:%s:%s:%s-%s%s", dirName, imageFullName[1], suffixImageName(imageFullName[2:]), arch, variant)
+			ref := fmt.Sprintf("%s:%s-%s%s", imageFullName[1], suffixImageName(imageFullName[2:]), arch, variant)
+			destName := fmt.Sprintf("oci:%s:%s", dirName, ref)
 			logger.Log.Infof("Source: %s", srcName)
 			logger.Log.Infof("Destination: %s", destName)
 
@@ -207,12 +239,52 @@ func (s *SaveImages) Execute(runtime connector.Runtime) error {
 				},
 			}
 
+			sys := o.srcImage.systemContext()
+			sys.VariantChoice = strings.TrimPrefix(variant, "-")
+			verified, signed, err := verifySourceImage(context.Background(), policy, image, sys)
+			if err != nil {
+				return err
+			}
+			uncarried, err := policy.uncarriedRequirements(image)
+			if err != nil {
+				return err
+			}
+			if len(uncarried) > 0 {
+				logger.Log.Warningf("The %s signatures of %s are verified but not carried into the artifact, the image is only "+
+					"accepted when it is pushed if the artifact is signed", strings.Join(uncarried, ", "), image)
+			}
+			repository := strings.Split(suffixImageName(imageFullName[2:]), ":")[0]
+			if verified != "" {
+				// the image is copied by the verified digest, so that it can not be changed after it is verified
+				o.srcImage.imageName = fmt.Sprintf("docker://%s/%s/%s@%s", imageFullName[0], imageFullName[1], repository, verified)
+			}
+			// the signatures can not be stored in the OCI layout, the cosign signature is copied as an image instead
+			o.policy, o.removeSignatures, o.preserveDigests = policy, true, signed != ""
 			if err := o.Copy(); err != nil {
 				return err
 			}
+			refs[ref] = annotations{Source: image, Policy: policyDigest.String()}
+			if signed == "" {
+				continue
+			}
+
+			sig := &CopyImageOptions{
+				srcImage: &srcImageOptions{
+					imageName:   fmt.Sprintf("docker://%s/%s/%s:%s", imageFullName[0], imageFullName[1], repository, cosignSignatureTag(signed)),
+					dockerImage: o.srcImage.dockerImage,
+				},
+				destImage: &destImageOptions{
+					imageName: fmt.Sprintf("oci:%s:%s:%s:%s", dirName, imageFullName[1], repository, cosignSignatureTag(signed)),
+				},
+				removeSignatures: true,
+			}
+			logger.Log.Infof("Signature: %s", sig.srcImage.imageName)
+			if err := sig.Copy(); err != nil {
+				return errors.Wrapf(err, "copy the cosign signature of %s failed", image)
+			}
 		}
 	}
-	return nil
+	return annotateIndex(dirName, refs)
 }
 
 type CopyImagesToRegistry struct {
@@ -240,6 +312,29 @@ func (c *CopyImagesToRegistry) Execute(runtime connector.Runtime) error {
 
 	auths := registry.DockerRegistryAuthEntries(c.KubeConf.Cluster.Registry.Auths)
 
+	policyPath := c.KubeConf.Arg.SignaturePolicy
+	if policyPath == "" {
+		if _, err := os.Stat(filepath.Join(imagesPath, SignaturePolicyFile)); err == nil {
+			policyPath = filepath.Join(imagesPath, SignaturePolicyFile)
+		}
+	}
+	var policy *SignaturePolicy
+	if policyPath != "" {
+		if policy, err = LoadSignaturePolicy(policyPath); err != nil {
+			return err
+		}
+	}
+	refs := make(map[string]bool, len(index.Manifests))
+	for _, m := range index.Manifests {
+		refs[m.Annotations.RefName] = true
+	}
+	var policyDigest digest.Digest
+	if policy != nil {
+		if policyDigest, err = policy.digest(); err != nil {
+			return err
+		}
+	}
+
 	manifestList := make(map[string][]manifesttypes.ManifestEntry)
 	for _, m := range index.Manifests {
 		ref := m.Annotations.RefName
@@ -250,6 +345,10 @@ func (c *CopyImagesToRegistry) Execute(runtime connector.Runtime) error {
 		if len(nameArr) != 3 {
 			return errors.Errorf("invalid ref name: %s", ref)
 		}
+		// the cosign signatures are pushed with the images they sign
+		if cosignSignatureTagRegexp.MatchString(nameArr[2]) {
+			continue
+		}
 
 		image := Image{
 			RepoAddr:          c.KubeConf.Cluster.Registry.PrivateRegistry,
@@ -317,15 +416,58 @@ func (c *CopyImagesToRegistry) Execute(runtime connector.Runtime) error {
 			},
 		}
 
+		var sig *CopyImageOptions
+		if policy != nil {
+			srcRef, err := alltransports.ParseImageName(srcName)
+			if err != nil {
+				return errors.Wrapf(err, "invalid image %s", srcName)
+			}
+			d, _, _, err := manifestDigest(context.Background(), srcRef, nil)
+			if err != nil {
+				return errors.Wrapf(err, "get the manifest of %s failed", srcName)
+			}
+			var sigRef types.ImageReference
+			if sigName := fmt.Sprintf("%s:%s:%s", nameArr[0], nameArr[1], cosignSignatureTag(d)); refs[sigName] {
+				if sigRef, err = alltransports.ParseImageName(fmt.Sprintf("oci:%s:%s", imagesPath, sigName)); err != nil {
+					return errors.Wrapf(err, "invalid image %s", sigName)
+				}
+				sigImage := image
+				sigImage.Tag = cosignSignatureTag(d)
+				sig = &CopyImageOptions{
+					srcImage:  &srcImageOptions{imageName: fmt.Sprintf("oci:%s:%s", imagesPath, sigName)},
+					destImage: &destImageOptions{imageName: fmt.Sprintf("docker://%s", sigImage.ImageName()), dockerImage: o.destImage.dockerImage},
+				}
+			}
+			// the requirements are looked up by the source of the image, the artifacts of the old versions have no sources
+			source := m.Annotations.Source
+			if source == "" {
+				source = image.ImageName()
+			}
+			// the annotations in index.json can be forged unless the checksums of the artifact are signed and verified
+			signed, _ := c.PipelineCache.GetMustBool(common.ArtifactSigned)
+			verified := signed && m.Annotations.Policy != "" && m.Annotations.Policy == policyDigest.String()
+			if err := verifyLayoutImage(context.Background(), policy, source, d, sigRef, verified); err != nil {
+				return err
+			}
+			// the digest of the signed image is kept, so that the signature is still valid in the private registry
+			o.preserveDigests = sig != nil
+		}
+
 		retry, maxRetry := 0, 5
 		for ; retry < maxRetry; retry++ {
-			if err := o.Copy(); err == nil {
+			if err = o.Copy(); err == nil {
 				break
 			}
 		}
 		if retry >= maxRetry {
 			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("copy image %s to %s failed, retry %d", srcName, destName, maxRetry))
 		}
+		if sig != nil {
+			logger.Log.Infof("Signature: %s", sig.destImage.imageName)
+			if err := sig.Copy(); err != nil {
+				return errors.Wrapf(err, "copy the cosign signature of %s failed", destName)
+			}
+		}
 	}
 
 	c.ModuleCache.Set("manifestList", manifestList)
@@ -102,22 +102,30 @@ func ParseArchVariant(platform string) (string, string) {
 }
 
 func ParseImageWithArchTag(ref string) (string, ocispec.Platform) {
+	name, p, err := parseImageWithArchTag(ref)
+	if err != nil {
+		logger.Log.Fatal(err)
+	}
+	return name, p
+}
+
+func parseImageWithArchTag(ref string) (string, ocispec.Platform, error) {
 	n := strings.LastIndex(ref, "-")
 	if n < 0 {
-		logger.Log.Fatalf("get arch or variant index failed: %s", ref)
+		return "", ocispec.Platform{}, errors.Errorf("get arch or variant index failed: %s", ref)
 	}
 	archOrVariant := ref[n+1:]
 
 	// try to parse the arch-only case
 	specifier := fmt.Sprintf("linux/%s", archOrVariant)
 	if p, err := platforms.Parse(specifier); err == nil && isKnownArch(p.Architecture) {
-		return ref[:n], p
+		return ref[:n], p, nil
 	}
 
 	archStr := ref[:n]
 	a := strings.LastIndex(archStr, "-")
 	if a < 0 {
-		logger.Log.Fatalf("get arch index failed: %s", ref)
+		return "", ocispec.Platform{}, errors.Errorf("get arch index failed: %s", ref)
 	}
 	arch := archStr[a+1:]
 
@@ -125,10 +133,10 @@ func ParseImageWithArchTag(ref string) (string, ocispec.Platform) {
 	specifier = fmt.Sprintf("linux/%s/%s", arch, archOrVariant)
 	p, err := platforms.Parse(specifier)
 	if err != nil {
-		logger.Log.Fatalf("parse image %s failed: %s", ref, err.Error())
+		return "", ocispec.Platform{}, errors.Errorf("parse image %s failed: %s", ref, err.Error())
 	}
 
-	return ref[:a], p
+	return ref[:a], p, nil
 }
 
 func isKnownArch(arch string) bool {
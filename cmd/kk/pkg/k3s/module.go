@@ -30,6 +30,10 @@ type StatusModule struct {
 	common.KubeModule
 }
 
+func (s *StatusModule) IsCollector() bool {
+	return true
+}
+
 func (s *StatusModule) Init() {
 	s.Name = "StatusModule"
 	s.Desc = "Get cluster status"
@@ -38,6 +38,10 @@ type StatusModule struct {
 	common.KubeModule
 }
 
+func (k *StatusModule) IsCollector() bool {
+	return true
+}
+
 func (k *StatusModule) Init() {
 	k.Name = "KubernetesStatusModule"
 	k.Desc = "Get kubernetes cluster status"
@@ -301,6 +305,7 @@ func (j *JoinNodesModule) Init() {
 		Action:   new(JoinNode),
 		Parallel: true,
 		Retry:    5,
+		Rollback: new(ResetJoinedNode),
 	}
 
 	joinWorkerNode := &task.RemoteTask{
@@ -314,6 +319,7 @@ func (j *JoinNodesModule) Init() {
 		Action:   new(JoinNode),
 		Parallel: true,
 		Retry:    5,
+		Rollback: new(ResetJoinedNode),
 	}
 
 	copyKubeConfig := &task.RemoteTask{
@@ -387,6 +393,10 @@ type CompareConfigAndClusterInfoModule struct {
 	common.KubeModule
 }
 
+func (c *CompareConfigAndClusterInfoModule) IsCollector() bool {
+	return true
+}
+
 func (c *CompareConfigAndClusterInfoModule) Init() {
 	c.Name = "CompareConfigAndClusterInfoModule"
 	c.Desc = "Compare config and cluster nodes info"
@@ -409,15 +419,17 @@ type RestartKubeletModule struct {
 	common.KubeModule
 }
 
-func (r *RestartKubeletModule) init() {
+func (r *RestartKubeletModule) Init() {
 	r.Name = "RestartKubeletModule"
 	r.Desc = "restart node kubelet service "
 	restart := &task.RemoteTask{
-		Name:   "RestartKubelet",
-		Desc:   "Restart kubelet service",
-		Hosts:  r.Runtime.GetHostsByRole(common.Master),
-		Action: new(RestartKubelet),
-		Retry:  5,
+		Name:     "RestartKubelet",
+		Desc:     "Restart kubelet service",
+		Hosts:    r.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(KubeletConfigured),
+		Action:   new(RestartKubelet),
+		Parallel: false,
+		Retry:    5,
 	}
 
 	r.Tasks = []task.Interface{
@@ -462,6 +474,10 @@ type SetUpgradePlanModule struct {
 	Step UpgradeStep
 }
 
+func (s *SetUpgradePlanModule) IsCollector() bool {
+	return true
+}
+
 func (s *SetUpgradePlanModule) Init() {
 	s.Name = fmt.Sprintf("SetUpgradePlanModule %d/%d", s.Step, len(UpgradeStepList))
 	s.Desc = "Set upgrade plan"
@@ -687,6 +703,31 @@ func (s *SaveKubeConfigModule) Init() {
 	}
 }
 
+// SaveClusterConfigModule saves the effective cluster configuration into the cluster, so that the day-2 operations
+// can load it by --from-cluster.
+type SaveClusterConfigModule struct {
+	common.KubeModule
+}
+
+func (s *SaveClusterConfigModule) Init() {
+	s.Name = "SaveClusterConfigModule"
+	s.Desc = "Save the cluster configuration into the cluster"
+
+	save := &task.RemoteTask{
+		Name:     "SaveClusterConfig",
+		Desc:     "Save the cluster configuration as a configmap and a secret",
+		Hosts:    s.Runtime.GetHostsByRole(common.Master),
+		Prepare:  new(common.OnlyFirstMaster),
+		Action:   new(SaveClusterConfig),
+		Parallel: true,
+		Retry:    5,
+	}
+
+	s.Tasks = []task.Interface{
+		save,
+	}
+}
+
 type ConfigureKubernetesModule struct {
 	common.KubeModule
 }
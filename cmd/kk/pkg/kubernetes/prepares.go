@@ -17,6 +17,8 @@
 package kubernetes
 
 import (
+	"path/filepath"
+
 	"github.com/pkg/errors"
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
@@ -131,3 +133,13 @@ func (n *NotEqualDesiredVersion) PreCheck(runtime connector.Runtime) (bool, erro
 	}
 	return true, nil
 }
+
+// KubeletConfigured checks whether the node has joined the cluster by the kubeconfig of kubelet, it works before the
+// cluster status is got.
+type KubeletConfigured struct {
+	common.KubePrepare
+}
+
+func (k *KubeletConfigured) PreCheck(runtime connector.Runtime) (bool, error) {
+	return runtime.GetRunner().FileExist(filepath.Join(common.KubeConfigDir, "kubelet.conf"))
+}
@@ -0,0 +1,178 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+)
+
+const (
+	KubeAPIServer         = "kube-apiserver"
+	KubeControllerManager = "kube-controller-manager"
+	KubeScheduler         = "kube-scheduler"
+	ETCD                  = "etcd"
+
+	staticPodManifestDir = "/etc/kubernetes/manifests"
+)
+
+// ControlPlaneComponents are the static pods of the control plane created by kubeadm, the etcd static pod only exists
+// for the kubeadm etcd type.
+var ControlPlaneComponents = []string{KubeAPIServer, KubeControllerManager, KubeScheduler}
+
+// staticPodHealthz returns the local health check endpoint of the static pod.
+func staticPodHealthz(component string) string {
+	switch component {
+	case KubeAPIServer:
+		return fmt.Sprintf("https://127.0.0.1:%d/healthz", kubekeyv1alpha2.DefaultApiserverPort)
+	case KubeControllerManager:
+		return "https://127.0.0.1:10257/healthz"
+	case KubeScheduler:
+		return "https://127.0.0.1:10259/healthz"
+	case ETCD:
+		return "http://127.0.0.1:2381/health"
+	default:
+		return ""
+	}
+}
+
+func staticPodHealthy(component, output string) bool {
+	output = strings.TrimSpace(output)
+	if component == ETCD {
+		return strings.Contains(strings.ReplaceAll(output, " ", ""), `"health":"true"`)
+	}
+	return output == "ok"
+}
+
+// stopStaticPodCmd returns the command which stops the containers of the static pod, kubelet starts them again. The
+// containers are stopped by docker or crictl, and the static pod is recreated by moving the manifest out and back if
+// crictl is not installed with the container manager.
+func stopStaticPodCmd(containerManager, component string) string {
+	switch containerManager {
+	case common.Docker, "":
+		return fmt.Sprintf("docker ps -af name=k8s_%s* -q | xargs --no-run-if-empty docker rm -f", component)
+	case common.Containerd, common.Crio:
+		return fmt.Sprintf("crictl ps -q --label io.kubernetes.container.name=%s | xargs --no-run-if-empty crictl stop", component)
+	default:
+		return ""
+	}
+}
+
+// RestartStaticPod restarts the static pod on the master by the container manager, and waits until it is healthy.
+func RestartStaticPod(runtime connector.Runtime, containerManager, component string) error {
+	host := runtime.RemoteHost()
+	manifest := filepath.Join(staticPodManifestDir, component+".yaml")
+	if exist, err := runtime.GetRunner().FileExist(manifest); err != nil {
+		return err
+	} else if !exist {
+		logger.Log.Messagef(host.GetName(), "%s is not found, skip restarting %s", manifest, component)
+		return nil
+	}
+
+	if cmd := stopStaticPodCmd(containerManager, component); cmd != "" {
+		if _, err := runtime.GetRunner().SudoCmd(cmd, false); err != nil {
+			return errors.Wrapf(errors.WithStack(err), "restart %s on %s failed", component, host.GetName())
+		}
+		return WaitStaticPodHealthy(runtime, component)
+	}
+
+	// kubelet removes the static pod when the manifest is removed, and creates it again when the manifest is back
+	backup := filepath.Join(common.KubeConfigDir, component+".yaml.restart")
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("mv -f %s %s", manifest, backup), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "move the manifest of %s out on %s failed", component, host.GetName())
+	}
+	stopped := waitStaticPod(runtime, component, false)
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("mv -f %s %s", backup, manifest), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "move the manifest of %s back on %s failed", component, host.GetName())
+	}
+	if !stopped {
+		return errors.Errorf("%s on %s is not stopped after its manifest is removed", component, host.GetName())
+	}
+	return WaitStaticPodHealthy(runtime, component)
+}
+
+// WaitStaticPodHealthy waits until the health check of the static pod passes on the master.
+func WaitStaticPodHealthy(runtime connector.Runtime, component string) error {
+	if !waitStaticPod(runtime, component, true) {
+		return errors.Errorf("%s on %s is not healthy", component, runtime.RemoteHost().GetName())
+	}
+	return nil
+}
+
+// WaitControlPlaneHealthy waits until the static pods of the control plane on the master are healthy.
+func WaitControlPlaneHealthy(runtime connector.Runtime) error {
+	for _, component := range []string{KubeAPIServer, KubeControllerManager, KubeScheduler, ETCD} {
+		if exist, err := runtime.GetRunner().FileExist(filepath.Join(staticPodManifestDir, component+".yaml")); err != nil {
+			return err
+		} else if !exist {
+			continue
+		}
+		if err := WaitStaticPodHealthy(runtime, component); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func waitStaticPod(runtime connector.Runtime, component string, healthy bool) bool {
+	healthz := staticPodHealthz(component)
+	if healthz == "" {
+		return true
+	}
+	for i := 0; i < 60; i++ {
+		output, _ := runtime.GetRunner().SudoCmd(fmt.Sprintf("curl -sk --max-time 5 %s || true", healthz), false)
+		if staticPodHealthy(component, output) == healthy {
+			return true
+		}
+		time.Sleep(5 * time.Second)
+	}
+	return false
+}
+
+// RestartControlPlane restarts the static pods of the control plane on the master one by one, each one is healthy
+// before the next one is restarted. The task should not be parallel, so that the masters are restarted one by one.
+type RestartControlPlane struct {
+	common.KubeAction
+	Components []string
+}
+
+func (r *RestartControlPlane) Execute(runtime connector.Runtime) error {
+	components := r.Components
+	if len(components) == 0 {
+		components = ControlPlaneComponents
+	}
+	return RestartStaticPods(runtime, r.KubeConf.Cluster.Kubernetes.ContainerManager, components...)
+}
+
+// RestartStaticPods restarts the static pods on the master one by one.
+func RestartStaticPods(runtime connector.Runtime, containerManager string, components ...string) error {
+	for _, component := range components {
+		if err := RestartStaticPod(runtime, containerManager, component); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/cache"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+)
+
+// ResetJoinedNode resets a node which failed to join the cluster, and deletes its node object from the cluster
+// by the first master.
+type ResetJoinedNode struct {
+	common.KubeRollback
+}
+
+func (r *ResetJoinedNode) Execute(runtime connector.Runtime, result *ending.ActionResult) error {
+	if result.Status != ending.FAILED {
+		return nil
+	}
+
+	host := runtime.RemoteHost()
+	resetCmd := "/usr/local/bin/kubeadm reset -f"
+	if r.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint != "" {
+		resetCmd = resetCmd + " --cri-socket " + r.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
+	}
+	if _, err := runtime.GetRunner().SudoCmd(resetCmd, true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "reset the node failed")
+	}
+
+	masters := runtime.GetHostsByRole(common.Master)
+	if len(masters) == 0 || masters[0].GetName() == host.GetName() {
+		return nil
+	}
+	conn, err := runtime.GetConnector().Connect(masters[0])
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", masters[0].GetAddress())
+	}
+	master := &connector.Runner{Conn: conn, Host: masters[0]}
+	if _, err := master.SudoCmd(fmt.Sprintf("/usr/local/bin/kubectl delete node %s --ignore-not-found",
+		host.GetName()), true); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "delete the node %s failed", host.GetName())
+	}
+	return nil
+}
+
+// NotInCluster reports whether the remote host was not a node of the cluster when the cluster status was collected.
+// It is used by the rollbacks to make sure that only the new nodes are cleaned up.
+func NotInCluster(runtime connector.Runtime, pipelineCache *cache.Cache) bool {
+	p := &NodeInCluster{Not: true}
+	p.Init(nil, pipelineCache)
+	p.AutoAssert(runtime)
+	ok, err := p.PreCheck(runtime)
+	return err == nil && ok
+}
@@ -17,7 +17,11 @@
 package kubernetes
 
 import (
+	"reflect"
 	"testing"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 )
 
 func Test_calculateNextStr(t *testing.T) {
@@ -71,3 +75,19 @@ func Test_calculateNextStr(t *testing.T) {
 		})
 	}
 }
+
+func Test_kubeadmSkipPhases(t *testing.T) {
+	kubeConf := &common.KubeConf{Cluster: &kubekeyv1alpha2.ClusterSpec{Kubernetes: kubekeyv1alpha2.Kubernetes{
+		SkipPhases:       []string{"addon/coredns", "upload-certs", "preflight", "control-plane-join/mark-control-plane"},
+		DisableKubeProxy: true,
+	}}}
+
+	want := []string{"addon/coredns", "upload-certs", "preflight", "control-plane-join/mark-control-plane", "addon/kube-proxy"}
+	if got := kubeadmSkipPhases(kubeConf, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("kubeadmSkipPhases() of kubeadm init = %v, want %v", got, want)
+	}
+	want = []string{"preflight", "control-plane-join/mark-control-plane"}
+	if got := kubeadmSkipPhases(kubeConf, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("kubeadmSkipPhases() of kubeadm join = %v, want %v", got, want)
+	}
+}
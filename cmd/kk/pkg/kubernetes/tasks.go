@@ -51,6 +51,8 @@ import (
 
 type GetClusterStatus struct {
 	common.KubeAction
+	// SkipJoinInfo skips uploading the certs and creating the bootstrap token, the status is read only.
+	SkipJoinInfo bool
 }
 
 func (g *GetClusterStatus) Execute(runtime connector.Runtime) error {
@@ -82,8 +84,10 @@ func (g *GetClusterStatus) Execute(runtime connector.Runtime) error {
 			if err := cluster.SearchNodesInfo(runtime); err != nil {
 				return err
 			}
-			if err := cluster.SearchJoinInfo(runtime); err != nil {
-				return err
+			if !g.SkipJoinInfo {
+				if err := cluster.SearchJoinInfo(runtime); err != nil {
+					return err
+				}
 			}
 
 			g.PipelineCache.Set(common.ClusterStatus, cluster)
@@ -188,7 +192,7 @@ func (g *GenerateKubeletEnv) Execute(runtime connector.Runtime) error {
 		Template: templates.KubeletEnv,
 		Dst:      filepath.Join("/etc/systemd/system/kubelet.service.d", templates.KubeletEnv.Name()),
 		Data: util.Data{
-			"NodeIP":           host.GetInternalAddress(),
+			"NodeIP":           nodeIP(g.KubeConf, host),
 			"Hostname":         host.GetName(),
 			"ContainerRuntime": "",
 			"KubeletArgs":      g.KubeConf.Cluster.Kubernetes.KubeletArgs,
@@ -272,9 +276,30 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 			}
 		}
 
+		version := g.KubeConf.Cluster.Kubernetes.Version
+		kubeadmConfig, err := templates.KubeadmConfig(version)
+		if err != nil {
+			return err
+		}
+		apiVersion, err := templates.KubeadmAPIVersion(version)
+		if err != nil {
+			return err
+		}
+		var skipPhases []string
+		var patchesDir string
+		if apiVersion != templates.KubeadmV1beta2 {
+			skipPhases = kubeadmSkipPhases(g.KubeConf, g.IsInitConfiguration)
+			if len(g.KubeConf.Cluster.Kubernetes.KubeadmPatches) != 0 {
+				if err := syncKubeadmPatches(runtime, g.KubeConf.Cluster.Kubernetes.KubeadmPatches); err != nil {
+					return err
+				}
+				patchesDir = templates.KubeadmPatchesDir
+			}
+		}
+
 		templateAction := action.Template{
-			Template: v1beta2.KubeadmConfig,
-			Dst:      filepath.Join(common.KubeConfigDir, v1beta2.KubeadmConfig.Name()),
+			Template: kubeadmConfig,
+			Dst:      filepath.Join(common.KubeConfigDir, kubeadmConfig.Name()),
 			Data: util.Data{
 				"IsInitCluster":          g.IsInitConfiguration,
 				"ImageRepo":              strings.TrimSuffix(images.GetImage(runtime, g.KubeConf, "kube-apiserver").ImageRepo(), "/kube-apiserver"),
@@ -295,7 +320,10 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 				"CertSANs":               g.KubeConf.Cluster.GenerateCertSANs(),
 				"ExternalEtcd":           externalEtcd,
 				"NodeCidrMaskSize":       g.KubeConf.Cluster.Kubernetes.NodeCidrMaskSize,
-				"CriSock":                g.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint,
+				"NodeCidrMaskSizeIPv6":   g.KubeConf.Cluster.Kubernetes.NodeCidrMaskSizeIPv6,
+				"DualStack":              g.KubeConf.Cluster.Network.EnableDualStack(),
+				"NodeIP":                 nodeIP(g.KubeConf, host),
+				"CriSock":                templates.KubeadmCriSocket(apiVersion, g.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint),
 				"ApiServerArgs":          v1beta2.UpdateFeatureGatesConfiguration(ApiServerArgs, g.KubeConf),
 				"EnableAudit":            g.KubeConf.Cluster.Kubernetes.EnableAudit(),
 				"ControllerManagerArgs":  v1beta2.UpdateFeatureGatesConfiguration(ControllerManagerArgs, g.KubeConf),
@@ -306,6 +334,8 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 				"CgroupDriver":           checkCgroupDriver,
 				"BootstrapToken":         bootstrapToken,
 				"CertificateKey":         certificateKey,
+				"SkipPhases":             skipPhases,
+				"PatchesDir":             patchesDir,
 			},
 		}
 
@@ -317,16 +347,96 @@ func (g *GenerateKubeadmConfig) Execute(runtime connector.Runtime) error {
 	return nil
 }
 
+// nodeIP returns the node IP of the kubelet, it is the IPv4 address and the IPv6 address separated by a comma in a
+// dual-stack cluster.
+func nodeIP(kubeConf *common.KubeConf, host connector.Host) string {
+	if kubeConf.Cluster.Network.EnableDualStack() && host.GetInternalIPv6Address() != "" {
+		return host.GetInternalAddress() + "," + host.GetInternalIPv6Address()
+	}
+	return host.GetInternalAddress()
+}
+
+// kubeadmJoinPhases are the top level phases of kubeadm join, the other phases only exist in kubeadm init.
+var kubeadmJoinPhases = map[string]bool{
+	"preflight":              true,
+	"control-plane-prepare":  true,
+	"kubelet-start":          true,
+	"control-plane-join":     true,
+	"kubelet-wait-bootstrap": true,
+	"wait-control-plane":     true,
+}
+
+// kubeadmSkipPhases returns the phases skipped by kubeadm init or kubeadm join. The phases of kubeadm init, such as
+// addon/coredns and upload-certs, are not passed to kubeadm join which refuses the unknown phases.
+func kubeadmSkipPhases(kubeConf *common.KubeConf, isInit bool) []string {
+	phases := make([]string, 0, len(kubeConf.Cluster.Kubernetes.SkipPhases)+1)
+	for _, phase := range kubeConf.Cluster.Kubernetes.SkipPhases {
+		if isInit || kubeadmJoinPhases[strings.SplitN(phase, "/", 2)[0]] {
+			phases = append(phases, phase)
+		}
+	}
+	if isInit && kubeConf.Cluster.Kubernetes.DisableKubeProxy {
+		phases = append(phases, "addon/kube-proxy")
+	}
+	return phases
+}
+
+// kubeadmSkipPhasesFlag returns the --skip-phases flag, it is only used with the kubeadm v1beta2 configuration which
+// has no skipPhases field.
+func kubeadmSkipPhasesFlag(kubeConf *common.KubeConf, isInit bool) (string, error) {
+	apiVersion, err := templates.KubeadmAPIVersion(kubeConf.Cluster.Kubernetes.Version)
+	if err != nil {
+		return "", err
+	}
+	if apiVersion != templates.KubeadmV1beta2 {
+		return "", nil
+	}
+	if phases := kubeadmSkipPhases(kubeConf, isInit); len(phases) != 0 {
+		return " --skip-phases=" + strings.Join(phases, ","), nil
+	}
+	return "", nil
+}
+
+// syncKubeadmPatches writes the kubeadm patches into the patches directory of the node.
+func syncKubeadmPatches(runtime connector.Runtime, patches map[string]string) error {
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -rf %s && mkdir -p %s", templates.KubeadmPatchesDir, templates.KubeadmPatchesDir), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), "create the kubeadm patches dir failed")
+	}
+
+	names := make([]string, 0, len(patches))
+	for name := range patches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.Contains(name, "/") {
+			return errors.Errorf("invalid kubeadm patch name %s", name)
+		}
+		fileName := filepath.Join(runtime.GetHostWorkDir(), "kubeadm-patch-"+name)
+		if err := util.WriteFile(fileName, []byte(patches[name])); err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("write file %s failed", fileName))
+		}
+		dst := filepath.Join(templates.KubeadmPatchesDir, name)
+		err := runtime.GetRunner().SudoScp(fileName, dst)
+		_ = os.Remove(fileName)
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), fmt.Sprintf("scp file %s to remote %s failed", fileName, dst))
+		}
+	}
+	return nil
+}
+
 type KubeadmInit struct {
 	common.KubeAction
 }
 
 func (k *KubeadmInit) Execute(runtime connector.Runtime) error {
-	initCmd := "/usr/local/bin/kubeadm init --config=/etc/kubernetes/kubeadm-config.yaml --ignore-preflight-errors=FileExisting-crictl,ImagePull"
-
-	if k.KubeConf.Cluster.Kubernetes.DisableKubeProxy {
-		initCmd = initCmd + " --skip-phases=addon/kube-proxy"
+	skipPhasesFlag, err := kubeadmSkipPhasesFlag(k.KubeConf, true)
+	if err != nil {
+		return err
 	}
+	initCmd := "/usr/local/bin/kubeadm init --config=/etc/kubernetes/kubeadm-config.yaml --ignore-preflight-errors=FileExisting-crictl,ImagePull" +
+		skipPhasesFlag
 
 	if _, err := runtime.GetRunner().SudoCmd(initCmd, true); err != nil {
 		// kubeadm reset and then retry
@@ -420,8 +530,12 @@ type JoinNode struct {
 }
 
 func (j *JoinNode) Execute(runtime connector.Runtime) error {
-	if _, err := runtime.GetRunner().SudoCmd("/usr/local/bin/kubeadm join --config=/etc/kubernetes/kubeadm-config.yaml --ignore-preflight-errors=FileExisting-crictl,ImagePull",
-		true); err != nil {
+	skipPhasesFlag, err := kubeadmSkipPhasesFlag(j.KubeConf, false)
+	if err != nil {
+		return err
+	}
+	if _, err := runtime.GetRunner().SudoCmd("/usr/local/bin/kubeadm join --config=/etc/kubernetes/kubeadm-config.yaml --ignore-preflight-errors=FileExisting-crictl,ImagePull"+
+		skipPhasesFlag, true); err != nil {
 		resetCmd := "/usr/local/bin/kubeadm reset -f"
 		if j.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint != "" {
 			resetCmd = resetCmd + " --cri-socket " + j.KubeConf.Cluster.Kubernetes.ContainerRuntimeEndpoint
@@ -695,6 +809,10 @@ func (r *RestartKubelet) Execute(runtime connector.Runtime) error {
 	if _, err := runtime.GetRunner().SudoCmd("systemctl daemon-reload && systemctl restart kubelet", true); err != nil {
 		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("restart kubelet failed: %s", host.GetName()))
 	}
+	if host.IsRole(common.Master) {
+		// the static pods of the control plane are synchronized by kubelet after it is restarted
+		return WaitControlPlaneHealthy(runtime)
+	}
 	time.Sleep(10 * time.Second)
 	return nil
 }
@@ -757,6 +875,10 @@ func (u *UpgradeKubeWorker) Execute(runtime connector.Runtime) error {
 	if _, err := runtime.GetRunner().SudoCmd("systemctl daemon-reload && systemctl restart kubelet", true); err != nil {
 		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("restart kubelet failed: %s", host.GetName()))
 	}
+	if host.IsRole(common.Master) {
+		// the static pods of the control plane are synchronized by kubelet after it is restarted
+		return WaitControlPlaneHealthy(runtime)
+	}
 	time.Sleep(10 * time.Second)
 	return nil
 }
@@ -1238,3 +1360,48 @@ func (n *NodesSecurityEnhancemenAction) Execute(runtime connector.Runtime) error
 
 	return nil
 }
+
+type SaveClusterConfig struct {
+	common.KubeAction
+}
+
+func (s *SaveClusterConfig) Execute(runtime connector.Runtime) error {
+	cluster := &kubekeyv1alpha2.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "kubekey.kubesphere.io/v1alpha2",
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: s.KubeConf.ClusterName},
+		Spec:       *s.KubeConf.Cluster,
+	}
+	manifest, err := common.ClusterConfigManifest(cluster)
+	if err != nil {
+		return err
+	}
+
+	// the manifest contains the credentials of the cluster, it is removed as soon as it is applied
+	// the manifest is only readable by the owner
+	fileName := filepath.Join(runtime.GetHostWorkDir(), common.ClusterConfigName+".yaml")
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("create dir %s failed", filepath.Dir(fileName)))
+	}
+	if err := os.WriteFile(fileName, manifest, 0600); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("write file %s failed", fileName))
+	}
+	defer os.Remove(fileName)
+
+	dst := filepath.Join(common.TmpDir, common.ClusterConfigName+".yaml")
+	if err := runtime.GetRunner().SudoScp(fileName, dst); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("scp file %s to remote %s failed", fileName, dst))
+	}
+	defer func() {
+		_, _ = runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -f %s", dst), false)
+	}()
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("chmod 600 %s", dst), false); err != nil {
+		return errors.Wrap(errors.WithStack(err), fmt.Sprintf("chmod file %s failed", dst))
+	}
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("/usr/local/bin/kubectl apply -f %s", dst), true); err != nil {
+		return errors.Wrap(errors.WithStack(err), "save the cluster configuration into the cluster failed")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes/templates/v1beta2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes/templates/v1beta3"
+)
+
+const (
+	KubeadmV1beta2 = "kubeadm.k8s.io/v1beta2"
+	KubeadmV1beta3 = "kubeadm.k8s.io/v1beta3"
+
+	// KubeadmPatchesDir is the directory of the kubeadm patches on the nodes.
+	KubeadmPatchesDir = "/etc/kubernetes/patches"
+)
+
+// kubeadmConfigs are the templates of the kubeadm configuration in the descending order of the kubernetes version
+// which they are used from. A newer API, such as v1beta4, is supported by adding its template at the front.
+var kubeadmConfigs = []struct {
+	since      *versionutil.Version
+	apiVersion string
+	template   *template.Template
+}{
+	{since: versionutil.MustParseSemantic("v1.22.0"), apiVersion: KubeadmV1beta3, template: v1beta3.KubeadmConfig},
+	{since: versionutil.MustParseSemantic("v0.0.0"), apiVersion: KubeadmV1beta2, template: v1beta2.KubeadmConfig},
+}
+
+func kubeadmConfigIndex(version string) (int, error) {
+	v, err := versionutil.ParseSemantic(version)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid kubernetes version %s", version)
+	}
+	for i, c := range kubeadmConfigs {
+		if v.AtLeast(c.since) {
+			return i, nil
+		}
+	}
+	return len(kubeadmConfigs) - 1, nil
+}
+
+// KubeadmConfig returns the template of the kubeadm configuration for the kubernetes version.
+func KubeadmConfig(version string) (*template.Template, error) {
+	i, err := kubeadmConfigIndex(version)
+	if err != nil {
+		return nil, err
+	}
+	return kubeadmConfigs[i].template, nil
+}
+
+// KubeadmAPIVersion returns the API version of the kubeadm configuration for the kubernetes version.
+func KubeadmAPIVersion(version string) (string, error) {
+	i, err := kubeadmConfigIndex(version)
+	if err != nil {
+		return "", err
+	}
+	return kubeadmConfigs[i].apiVersion, nil
+}
+
+// KubeadmCriSocket returns the cri socket in the kubeadm configuration of the API version. The socket without a URL
+// scheme is deprecated since kubeadm v1beta3, so the unix scheme is added.
+func KubeadmCriSocket(apiVersion, endpoint string) string {
+	if endpoint == "" || apiVersion == KubeadmV1beta2 || strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	return "unix://" + endpoint
+}
@@ -0,0 +1,176 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package templates
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kubekeyv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/version/kubernetes"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func kubeadmConfigData(version string, isInit bool) map[string]interface{} {
+	apiVersion, _ := KubeadmAPIVersion(version)
+	return map[string]interface{}{
+		"IsInitCluster":        isInit,
+		"ImageRepo":            "kubesphere",
+		"EtcdTypeIsKubeadm":    false,
+		"EtcdRepo":             "kubesphere",
+		"EtcdTag":              "v3.4.13",
+		"CorednsRepo":          "coredns",
+		"CorednsTag":           "1.8.6",
+		"Version":              version,
+		"ClusterName":          "cluster.local",
+		"DNSDomain":            "cluster.local",
+		"AdvertiseAddress":     "172.16.0.2",
+		"BindPort":             6443,
+		"ControlPlaneEndpoint": "lb.kubesphere.local:6443",
+		"PodSubnet":            "10.233.64.0/18",
+		"ServiceSubnet":        "10.233.0.0/18",
+		"CertSANs":             []string{"kubernetes", "lb.kubesphere.local", "172.16.0.2"},
+		"ExternalEtcd": kubekeyv1alpha2.ExternalEtcd{
+			Endpoints: []string{"https://172.16.0.2:2379"},
+			CAFile:    "/etc/ssl/etcd/ssl/ca.pem",
+			CertFile:  "/etc/ssl/etcd/ssl/node-node1.pem",
+			KeyFile:   "/etc/ssl/etcd/ssl/node-node1-key.pem",
+		},
+		"NodeCidrMaskSize":      24,
+		"NodeCidrMaskSizeIPv6":  64,
+		"DualStack":             false,
+		"NodeIP":                "172.16.0.2",
+		"CriSock":               KubeadmCriSocket(apiVersion, "/run/containerd/containerd.sock"),
+		"ApiServerArgs":         map[string]string{"bind-address": "0.0.0.0", "feature-gates": "RotateKubeletServerCertificate=true"},
+		"EnableAudit":           false,
+		"ControllerManagerArgs": map[string]string{"bind-address": "0.0.0.0", "cluster-signing-duration": "87600h"},
+		"SchedulerArgs":         map[string]string{"bind-address": "0.0.0.0"},
+		"KubeletConfiguration": map[string]interface{}{
+			"clusterDomain": "cluster.local",
+			"clusterDNS":    []string{"169.254.25.10"},
+			"maxPods":       110,
+			"cgroupDriver":  "systemd",
+		},
+		"KubeProxyConfiguration": map[string]interface{}{
+			"clusterCIDR": "10.233.64.0/18",
+			"mode":        "ipvs",
+		},
+		"IsControlPlane": true,
+		"CgroupDriver":   "systemd",
+		"BootstrapToken": "abcdef.0123456789abcdef",
+		"CertificateKey": "e6a2eb8581237ab72a4f494f30285ec12a9694d750b9785706a83bfcbbbd2204",
+		"SkipPhases":     []string{"addon/kube-proxy"},
+		"PatchesDir":     KubeadmPatchesDir,
+	}
+}
+
+func TestKubeadmConfig(t *testing.T) {
+	for _, v := range kubernetes.VersionList {
+		version := fmt.Sprintf("%s.0", v)
+		for _, phase := range []string{"init", "join"} {
+			name := fmt.Sprintf("kubeadm-config-%s-%s.yaml", v, phase)
+			t.Run(name, func(t *testing.T) {
+				tmpl, err := KubeadmConfig(version)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var buf bytes.Buffer
+				if err := tmpl.Execute(&buf, kubeadmConfigData(version, phase == "init")); err != nil {
+					t.Fatal(err)
+				}
+
+				golden := filepath.Join("testdata", name)
+				if *update {
+					if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := buf.String(); got != string(want) {
+					t.Errorf("the kubeadm configuration of %s is different from %s:\n%s", version, golden, got)
+				}
+			})
+		}
+	}
+}
+
+func TestKubeadmConfigDualStack(t *testing.T) {
+	for _, version := range []string{"v1.20.4", "v1.24.0"} {
+		for _, phase := range []string{"init", "join"} {
+			data := kubeadmConfigData(version, phase == "init")
+			data["DualStack"] = true
+			data["NodeIP"] = "172.16.0.2,fd00::2"
+			data["PodSubnet"] = "10.233.64.0/18,fd00:10:233::/56"
+			data["ServiceSubnet"] = "10.233.0.0/18,fd00:10:96::/108"
+
+			tmpl, err := KubeadmConfig(version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				t.Fatal(err)
+			}
+			want := []string{"node-ip: 172.16.0.2,fd00::2"}
+			if phase == "init" {
+				want = append(want,
+					"podSubnet: 10.233.64.0/18,fd00:10:233::/56",
+					"serviceSubnet: 10.233.0.0/18,fd00:10:96::/108",
+					`node-cidr-mask-size-ipv4: "24"`,
+					`node-cidr-mask-size-ipv6: "64"`)
+			}
+			for _, w := range want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("the %s configuration of %s does not contain %q:\n%s", phase, version, w, buf.String())
+				}
+			}
+		}
+	}
+}
+
+func TestKubeadmAPIVersion(t *testing.T) {
+	tests := map[string]string{
+		"v1.19.8":  KubeadmV1beta2,
+		"v1.21.14": KubeadmV1beta2,
+		"v1.22.0":  KubeadmV1beta3,
+		"v1.26.0":  KubeadmV1beta3,
+	}
+	for version, want := range tests {
+		if got, err := KubeadmAPIVersion(version); err != nil || got != want {
+			t.Errorf("KubeadmAPIVersion(%s) = %s, %v, want %s", version, got, err, want)
+		}
+	}
+	if _, err := KubeadmAPIVersion("latest"); err == nil {
+		t.Error("KubeadmAPIVersion(latest) returns no error")
+	}
+
+	if got := KubeadmCriSocket(KubeadmV1beta2, "/run/containerd/containerd.sock"); got != "/run/containerd/containerd.sock" {
+		t.Errorf("the cri socket of v1beta2 = %s", got)
+	}
+	if got := KubeadmCriSocket(KubeadmV1beta3, "/run/containerd/containerd.sock"); got != "unix:///run/containerd/containerd.sock" {
+		t.Errorf("the cri socket of v1beta3 = %s", got)
+	}
+}
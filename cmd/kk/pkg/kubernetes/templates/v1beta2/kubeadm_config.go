@@ -94,7 +94,12 @@ apiServer:
 {{- end }}
 controllerManager:
   extraArgs:
+{{- if .DualStack }}
+    node-cidr-mask-size-ipv4: "{{ .NodeCidrMaskSize }}"
+    node-cidr-mask-size-ipv6: "{{ .NodeCidrMaskSizeIPv6 }}"
+{{- else }}
     node-cidr-mask-size: "{{ .NodeCidrMaskSize }}"
+{{- end }}
 {{ toYaml .ControllerManagerArgs | indent 4 }}
   extraVolumes:
   - name: host-time
@@ -117,6 +122,9 @@ nodeRegistration:
 {{- end }}
   kubeletExtraArgs:
     cgroup-driver: {{ .CgroupDriver }}
+{{- if .DualStack }}
+    node-ip: {{ .NodeIP }}
+{{- end }}
 ---
 apiVersion: kubeproxy.config.k8s.io/v1alpha1
 kind: KubeProxyConfiguration
@@ -149,6 +157,9 @@ nodeRegistration:
 {{- end }}
   kubeletExtraArgs:
     cgroup-driver: {{ .CgroupDriver }}
+{{- if .DualStack }}
+    node-ip: {{ .NodeIP }}
+{{- end }}
 
 {{- end }}
     `)))
@@ -276,6 +287,10 @@ func UpdateFeatureGatesConfiguration(args map[string]string, kubeConf *common.Ku
 		}
 	}
 
+	if enableDualStackFeatureGate(kubeConf) {
+		featureGates = append(featureGates, "IPv6DualStack=true")
+	}
+
 	args["feature-gates"] = strings.Join(featureGates, ",")
 
 	return args
@@ -385,6 +400,14 @@ func GetKubeletConfiguration(runtime connector.Runtime, kubeConf *common.KubeCon
 				featureGates[k] = v
 			}
 		}
+
+		if enableDualStackFeatureGate(kubeConf) {
+			gates := map[string]bool{"IPv6DualStack": true}
+			for k, v := range featureGates {
+				gates[k] = v
+			}
+			kubeletConfiguration["featureGates"] = gates
+		}
 	}
 
 	if kubeConf.Arg.Debug {
@@ -435,6 +458,10 @@ func GetKubeProxyConfiguration(kubeConf *common.KubeConf) map[string]interface{}
 		},
 	}
 
+	if enableDualStackFeatureGate(kubeConf) {
+		defaultKubeProxyConfiguration["featureGates"] = map[string]bool{"IPv6DualStack": true}
+	}
+
 	customKubeProxyConfiguration := make(map[string]interface{})
 	if len(kubeConf.Cluster.Kubernetes.KubeProxyConfiguration.Raw) != 0 {
 		err := yaml.Unmarshal(kubeConf.Cluster.Kubernetes.KubeProxyConfiguration.Raw, &customKubeProxyConfiguration)
@@ -465,6 +492,18 @@ func GetKubeProxyConfiguration(kubeConf *common.KubeConf) map[string]interface{}
 	return kubeProxyConfiguration
 }
 
+// enableDualStackFeatureGate is used to determine whether to enable the IPv6DualStack feature gate, which is
+// required by the dual-stack clusters earlier than v1.21.
+func enableDualStackFeatureGate(kubeConf *common.KubeConf) bool {
+	if !kubeConf.Cluster.Network.EnableDualStack() {
+		return false
+	}
+	if _, ok := kubeConf.Cluster.Kubernetes.FeatureGates["IPv6DualStack"]; ok {
+		return false
+	}
+	return versionutil.MustParseSemantic(kubeConf.Cluster.Kubernetes.Version).LessThan(versionutil.MustParseSemantic("v1.21.0"))
+}
+
 func copyStringMap(m map[string]string) map[string]string {
 	cp := make(map[string]string)
 	for k, v := range m {
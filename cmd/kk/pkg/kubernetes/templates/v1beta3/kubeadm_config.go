@@ -0,0 +1,177 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	"text/template"
+
+	"github.com/lithammer/dedent"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/utils"
+)
+
+var (
+	// KubeadmConfig defines the template of kubeadm configuration file, it is used by kubeadm v1.22 and later.
+	KubeadmConfig = template.Must(template.New("kubeadm-config.yaml").Funcs(utils.FuncMap).Parse(
+		dedent.Dedent(`
+{{- if .IsInitCluster -}}
+---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+etcd:
+{{- if .EtcdTypeIsKubeadm }}
+  local:
+    imageRepository: {{ .EtcdRepo }}
+    imageTag: {{ .EtcdTag }}
+    serverCertSANs:
+    {{- range .ExternalEtcd.Endpoints }}
+    - {{ . }}
+    {{- end }}
+{{- else }}
+  external:
+    endpoints:
+    {{- range .ExternalEtcd.Endpoints }}
+    - {{ . }}
+    {{- end }}
+{{- if .ExternalEtcd.CAFile }}
+    caFile: {{ .ExternalEtcd.CAFile }}
+{{- end }}
+{{- if .ExternalEtcd.CertFile }}
+    certFile: {{ .ExternalEtcd.CertFile }}
+{{- end }}
+{{- if .ExternalEtcd.KeyFile }}
+    keyFile: {{ .ExternalEtcd.KeyFile }}
+{{- end }}
+{{- end }}
+dns:
+  imageRepository: {{ .CorednsRepo }}
+  imageTag: {{ .CorednsTag }}
+imageRepository: {{ .ImageRepo }}
+kubernetesVersion: {{ .Version }}
+certificatesDir: /etc/kubernetes/pki
+clusterName: {{ .ClusterName }}
+controlPlaneEndpoint: {{ .ControlPlaneEndpoint }}
+networking:
+  dnsDomain: {{ .DNSDomain }}
+  podSubnet: {{ .PodSubnet }}
+  serviceSubnet: {{ .ServiceSubnet }}
+apiServer:
+  extraArgs:
+{{ toYaml .ApiServerArgs | indent 4}}
+  certSANs:
+    {{- range .CertSANs }}
+    - "{{ . }}"
+    {{- end }}
+{{- if .EnableAudit }} 
+  extraVolumes:
+  - name: k8s-audit
+    hostPath: /etc/kubernetes/audit
+    mountPath: /etc/kubernetes/audit
+    pathType: DirectoryOrCreate
+{{- end }}
+controllerManager:
+  extraArgs:
+{{- if .DualStack }}
+    node-cidr-mask-size-ipv4: "{{ .NodeCidrMaskSize }}"
+    node-cidr-mask-size-ipv6: "{{ .NodeCidrMaskSizeIPv6 }}"
+{{- else }}
+    node-cidr-mask-size: "{{ .NodeCidrMaskSize }}"
+{{- end }}
+{{ toYaml .ControllerManagerArgs | indent 4 }}
+  extraVolumes:
+  - name: host-time
+    hostPath: /etc/localtime
+    mountPath: /etc/localtime
+    readOnly: true
+scheduler:
+  extraArgs:
+{{ toYaml .SchedulerArgs | indent 4 }}
+
+---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: InitConfiguration
+localAPIEndpoint:
+  advertiseAddress: {{ .AdvertiseAddress }}
+  bindPort: {{ .BindPort }}
+nodeRegistration:
+{{- if .CriSock }}
+  criSocket: {{ .CriSock }}
+{{- end }}
+  kubeletExtraArgs:
+    cgroup-driver: {{ .CgroupDriver }}
+{{- if .DualStack }}
+    node-ip: {{ .NodeIP }}
+{{- end }}
+{{- if .SkipPhases }}
+skipPhases:
+{{- range .SkipPhases }}
+- {{ . }}
+{{- end }}
+{{- end }}
+{{- if .PatchesDir }}
+patches:
+  directory: {{ .PatchesDir }}
+{{- end }}
+---
+apiVersion: kubeproxy.config.k8s.io/v1alpha1
+kind: KubeProxyConfiguration
+{{ toYaml .KubeProxyConfiguration }}
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+{{ toYaml .KubeletConfiguration }}
+
+{{- else -}}
+---
+apiVersion: kubeadm.k8s.io/v1beta3
+kind: JoinConfiguration
+discovery:
+  bootstrapToken:
+    apiServerEndpoint: {{ .ControlPlaneEndpoint }}
+    token: "{{ .BootstrapToken }}"
+    unsafeSkipCAVerification: true
+  tlsBootstrapToken: "{{ .BootstrapToken }}"
+{{- if .IsControlPlane }}
+controlPlane:
+  localAPIEndpoint:
+    advertiseAddress: {{ .AdvertiseAddress }}
+    bindPort: {{ .BindPort }}
+  certificateKey: {{ .CertificateKey }}
+{{- end }}
+nodeRegistration:
+{{- if .CriSock }}
+  criSocket: {{ .CriSock }}
+{{- end }}
+  kubeletExtraArgs:
+    cgroup-driver: {{ .CgroupDriver }}
+{{- if .DualStack }}
+    node-ip: {{ .NodeIP }}
+{{- end }}
+{{- if .SkipPhases }}
+skipPhases:
+{{- range .SkipPhases }}
+- {{ . }}
+{{- end }}
+{{- end }}
+{{- if .PatchesDir }}
+patches:
+  directory: {{ .PatchesDir }}
+{{- end }}
+
+{{- end }}
+    `)))
+)
@@ -0,0 +1,158 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package kubesphere
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	yamlV3 "gopkg.in/yaml.v3"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/logger"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/files"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/version/kubesphere"
+)
+
+// ImagesListURL is the url of the images list published with each release of ks-installer.
+const ImagesListURL = "https://github.com/kubesphere/ks-installer/releases/download/%s/images-list.txt"
+
+// componentImages are the sections of the images list which are only deployed with the components enabled in the
+// ClusterConfiguration.
+var componentImages = map[string]func(spec map[string]interface{}) bool{
+	"kubesphere-devops-images": enabled("devops"),
+	"kubesphere-logging-images": func(spec map[string]interface{}) bool {
+		return enabled("logging")(spec) || enabled("auditing")(spec) || enabled("events")(spec)
+	},
+	"istio-images": enabled("servicemesh"),
+	"kubeedge-images": func(spec map[string]interface{}) bool {
+		return enabled("kubeedge")(spec) || enabled("edgeruntime")(spec)
+	},
+	"gatekeeper-images": enabled("gatekeeper"),
+	"openpitrix-images": enabled("openpitrix", "store"),
+	"weave-scope-images": func(spec map[string]interface{}) bool {
+		return lookup(spec, "network", "topology", "type") == "weave-scope"
+	},
+	// the images of Kubernetes are computed from the cluster, and the examples are never deployed
+	"k8s-images":     func(map[string]interface{}) bool { return false },
+	"example-images": func(map[string]interface{}) bool { return false },
+}
+
+// Images returns the images of KubeSphere deployed with the ClusterConfiguration. The images list of the released
+// version is downloaded, and the images of the components not enabled are excluded. Only ks-installer is returned for
+// the development versions, whose images list is not published.
+func Images(ks *kubekeyapiv1alpha2.KubeSphere) ([]string, error) {
+	installer := fmt.Sprintf("%s/ks-installer:%s", MirrorRepo(&common.KubeConf{Cluster: &kubekeyapiv1alpha2.ClusterSpec{KubeSphere: *ks}}), ks.Version)
+	if _, stable := kubesphere.StabledVersionSupport(ks.Version); !stable {
+		logger.Log.Warningf("The images list of KubeSphere %s is not published, only ks-installer is included", ks.Version)
+		return []string{installer}, nil
+	}
+
+	spec, err := clusterConfigurationSpec(ks.Configurations)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "kubesphere-images")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "images-list.txt")
+	if err := files.DefaultDownloader().Download(fmt.Sprintf(ImagesListURL, ks.Version), path, ""); err != nil {
+		return nil, errors.Wrapf(err, "download the images list of KubeSphere %s failed", ks.Version)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return append([]string{installer}, parseImagesList(content, spec)...), nil
+}
+
+// parseImagesList returns the images in the sections of the list which are deployed with the ClusterConfiguration,
+// each section starts with a line of ##<name>.
+func parseImagesList(content []byte, spec map[string]interface{}) []string {
+	images := make([]string, 0)
+	deployed := true
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#"):
+			section := strings.TrimLeft(line, "#")
+			deployed = true
+			if f, ok := componentImages[section]; ok {
+				deployed = f(spec)
+			}
+		case deployed:
+			images = append(images, line)
+		}
+	}
+	return images
+}
+
+// clusterConfigurationSpec returns the spec of the ClusterConfiguration in the KubeSphere configurations.
+func clusterConfigurationSpec(configurations string) (map[string]interface{}, error) {
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(strings.NewReader(configurations)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return map[string]interface{}{}, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "read the KubeSphere configurations failed")
+		}
+		obj := make(map[string]interface{})
+		if err := yamlV3.Unmarshal(doc, &obj); err != nil {
+			return nil, errors.Wrap(err, "unmarshal the KubeSphere configurations failed")
+		}
+		if obj["kind"] != "ClusterConfiguration" {
+			continue
+		}
+		spec, _ := obj["spec"].(map[string]interface{})
+		if spec == nil {
+			spec = map[string]interface{}{}
+		}
+		return spec, nil
+	}
+}
+
+func enabled(path ...string) func(spec map[string]interface{}) bool {
+	return func(spec map[string]interface{}) bool {
+		return lookup(spec, append(path, "enabled")...) == true
+	}
+}
+
+func lookup(spec map[string]interface{}, path ...string) interface{} {
+	var value interface{} = spec
+	for _, key := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
@@ -97,3 +97,46 @@ func repo(version string) string {
 	}
 	return r
 }
+
+func Test_parseImagesList(t *testing.T) {
+	content := []byte(`##k8s-images
+kubesphere/kube-apiserver:v1.22.12
+##kubesphere-images
+kubesphere/ks-console:v3.4.0
+kubesphere/ks-controller-manager:v3.4.0
+
+##kubesphere-devops-images
+kubesphere/devops-apiserver:v3.4.0
+##kubesphere-logging-images
+kubesphere/fluent-bit:v1.9.4
+##weave-scope-images
+weaveworks/scope:1.13.0
+##example-images
+kubesphere/examples-bookinfo-productpage-v1:1.16.2
+`)
+	configurations := `
+apiVersion: installer.kubesphere.io/v1alpha1
+kind: ClusterConfiguration
+spec:
+  devops:
+    enabled: false
+  auditing:
+    enabled: true
+  network:
+    topology:
+      type: weave-scope
+`
+	spec, err := clusterConfigurationSpec(configurations)
+	if err != nil {
+		t.Fatalf("clusterConfigurationSpec() error = %v", err)
+	}
+	want := []string{
+		"kubesphere/ks-console:v3.4.0",
+		"kubesphere/ks-controller-manager:v3.4.0",
+		"kubesphere/fluent-bit:v1.9.4",
+		"weaveworks/scope:1.13.0",
+	}
+	if got := parseImagesList(content, spec); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseImagesList() = %v, want %v", got, want)
+	}
+}
@@ -81,6 +81,7 @@ func (h *HaproxyModule) Init() {
 		},
 		Action:   new(GenerateHaproxyManifest),
 		Parallel: true,
+		Rollback: haproxyManifestRollback(),
 	}
 
 	// UpdateKubeletConfig Update server field in kubelet.conf
@@ -174,6 +175,7 @@ func (k *KubevipModule) Init() {
 		Prepare:  new(common.OnlyFirstMaster),
 		Action:   new(GenerateKubevipManifest),
 		Parallel: true,
+		Rollback: kubevipManifestRollback(),
 	}
 
 	kubevipManifestNotFirstMaster := &task.RemoteTask{
@@ -183,6 +185,7 @@ func (k *KubevipModule) Init() {
 		Prepare:  &common.OnlyFirstMaster{Not: true},
 		Action:   new(GenerateKubevipManifest),
 		Parallel: true,
+		Rollback: kubevipManifestRollback(),
 	}
 
 	if exist, _ := k.BaseModule.PipelineCache.GetMustBool(common.ClusterExist); exist {
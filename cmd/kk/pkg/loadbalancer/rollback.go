@@ -0,0 +1,57 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/ending"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/loadbalancer/templates"
+)
+
+// RemoveManifest removes the static pod manifest of the internal load balancer from a new node which failed to
+// generate it. The nodes which were already in the cluster are never touched, their load balancer is still in use.
+type RemoveManifest struct {
+	common.KubeRollback
+	Manifest string
+}
+
+func (r *RemoveManifest) Execute(runtime connector.Runtime, result *ending.ActionResult) error {
+	if result.Status != ending.FAILED || !kubernetes.NotInCluster(runtime, r.PipelineCache) {
+		return nil
+	}
+
+	manifest := filepath.Join(common.KubeManifestDir, r.Manifest)
+	if _, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("rm -f %s", manifest), false); err != nil {
+		return errors.Wrapf(errors.WithStack(err), "remove %s failed", manifest)
+	}
+	return nil
+}
+
+func haproxyManifestRollback() *RemoveManifest {
+	return &RemoveManifest{Manifest: templates.HaproxyManifest.Name()}
+}
+
+func kubevipManifestRollback() *RemoveManifest {
+	return &RemoveManifest{Manifest: templates.KubevipManifest.Name()}
+}
@@ -28,6 +28,7 @@ import (
 func NewArtifactImportPipeline(runtime *common.KubeRuntime) error {
 
 	m := []module.Module{
+		&artifact.UnArchiveBaseModule{Skip: runtime.Arg.BaseArtifact == ""},
 		&artifact.UnArchiveModule{},
 	}
 
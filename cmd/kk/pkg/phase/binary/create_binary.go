@@ -47,11 +47,12 @@ func NewCreateBinaryPipeline(runtime *common.KubeRuntime) error {
 }
 
 func CreateBinary(args common.Argument, downloadCmd string) error {
-	args.DownloadCommand = func(path, url string) string {
-		// this is an extension point for downloading tools, for example users can set the timeout, proxy or retry under
-		// some poor network environment. Or users even can choose another cli, it might be wget.
-		// perhaps we should have a build-in download function instead of totally rely on the external one
-		return fmt.Sprintf(downloadCmd, path, url)
+	if downloadCmd != "" {
+		// the user defined command is an extension point for downloading tools, for example users can choose
+		// another cli, it might be wget. The built-in downloader is used if it is not set.
+		args.DownloadCommand = func(path, url string) string {
+			return fmt.Sprintf(downloadCmd, path, url)
+		}
 	}
 	var loaderType string
 
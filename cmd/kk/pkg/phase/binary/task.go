@@ -60,6 +60,9 @@ func setBinaryPath(kubeConf *common.KubeConf, path, arch string, binaries []stri
 		default:
 			return errors.New(fmt.Sprintf("Unsupported binary name: %s", binary))
 		}
+		if err := kubeBinary.ApplyMirrors(kubeConf.Cluster.DownloadMirrors); err != nil {
+			return err
+		}
 		binariesMap[kubeBinary.ID] = kubeBinary
 	}
 	pipelineCache.Set(common.KubeBinaries+"-"+arch, binariesMap)
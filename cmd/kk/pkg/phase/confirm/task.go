@@ -22,6 +22,7 @@ type UpgradeK8sConfirm struct {
 }
 
 func (u *UpgradeK8sConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	pre := make([]map[string]string, len(runtime.GetAllHosts()), len(runtime.GetAllHosts()))
 	for i, host := range runtime.GetAllHosts() {
 		if v, ok := host.GetCache().Get(common.NodePreCheck); ok {
@@ -37,8 +38,8 @@ func (u *UpgradeK8sConfirm) Execute(runtime connector.Runtime) error {
 		_ = mapstructure.Decode(pre[i], &result)
 		results[i] = result
 	}
-	table.OutputA(results)
-	fmt.Println()
+	fmt.Fprintln(out, table.AsciiTable(results))
+	fmt.Fprintln(out)
 
 	warningFlag := false
 	cmp, err := versionutil.MustParseSemantic(u.KubeConf.Cluster.Kubernetes.Version).Compare("v1.19.0")
@@ -60,13 +61,13 @@ func (u *UpgradeK8sConfirm) Execute(runtime connector.Runtime) error {
 			}
 		}
 		if warningFlag {
-			fmt.Println(`
+			fmt.Fprintln(out, `
 Warning:
 
   An old Docker version may cause the failure of upgrade. It is recommended that you upgrade Docker to 20.10+ beforehand.
 
   Issue: https://github.com/kubernetes/kubernetes/issues/101056`)
-			fmt.Print("\n")
+			fmt.Fprint(out, "\n")
 		}
 	}
 
@@ -74,30 +75,30 @@ Warning:
 	if !ok {
 		return errors.New("get cluster nodes status failed by pipeline cache")
 	}
-	fmt.Println("Cluster nodes status:")
-	fmt.Println(nodeStats + "\n")
+	fmt.Fprintln(out, "Cluster nodes status:")
+	fmt.Fprintln(out, nodeStats+"\n")
 
-	fmt.Println("Upgrade Confirmation:")
+	fmt.Fprintln(out, "Upgrade Confirmation:")
 	currentK8sVersion, ok := u.PipelineCache.GetMustString(common.K8sVersion)
 	if !ok {
 		return errors.New("get current Kubernetes version failed by pipeline cache")
 	}
-	fmt.Printf("kubernetes version: %s to %s\n", currentK8sVersion, u.KubeConf.Cluster.Kubernetes.Version)
+	fmt.Fprintf(out, "kubernetes version: %s to %s\n", currentK8sVersion, u.KubeConf.Cluster.Kubernetes.Version)
 
-	fmt.Println()
+	fmt.Fprintln(out)
 
 	if k8sVersion, err := versionutil.ParseGeneric(u.KubeConf.Cluster.Kubernetes.Version); err == nil {
 		if cri, ok := u.PipelineCache.GetMustString(common.ClusterNodeCRIRuntimes); ok {
 			k8sV124 := versionutil.MustParseSemantic("v1.24.0")
 			if k8sVersion.AtLeast(k8sV124) && versionutil.MustParseSemantic(currentK8sVersion).LessThan(k8sV124) && strings.Contains(cri, "docker") {
-				fmt.Println("[Notice]")
-				fmt.Println("Pre-upgrade check failed. The container runtime of the current cluster is Docker.")
-				fmt.Println("Kubernetes v1.24 and later no longer support dockershim and Docker.")
-				fmt.Println("Make sure you have completed the migration from Docker to other container runtimes that are compatible with the Kubernetes CRI.")
-				fmt.Println("For more information, see:")
-				fmt.Println("https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
-				fmt.Println("https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
-				fmt.Println("")
+				fmt.Fprintln(out, "[Notice]")
+				fmt.Fprintln(out, "Pre-upgrade check failed. The container runtime of the current cluster is Docker.")
+				fmt.Fprintln(out, "Kubernetes v1.24 and later no longer support dockershim and Docker.")
+				fmt.Fprintln(out, "Make sure you have completed the migration from Docker to other container runtimes that are compatible with the Kubernetes CRI.")
+				fmt.Fprintln(out, "For more information, see:")
+				fmt.Fprintln(out, "https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
+				fmt.Fprintln(out, "https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
+				fmt.Fprintln(out, "")
 			}
 		}
 	}
@@ -105,7 +106,7 @@ Warning:
 	reader := bufio.NewReader(os.Stdin)
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Continue upgrading kubernetes? [yes/no]: ")
+		fmt.Fprintf(out, "Continue upgrading kubernetes? [yes/no]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return err
@@ -129,6 +130,7 @@ type UpgradeKsConfirm struct {
 }
 
 func (u *UpgradeKsConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	pre := make([]map[string]string, len(runtime.GetAllHosts()), len(runtime.GetAllHosts()))
 	for i, host := range runtime.GetAllHosts() {
 		if v, ok := host.GetCache().Get(common.NodePreCheck); ok {
@@ -143,14 +145,14 @@ func (u *UpgradeKsConfirm) Execute(runtime connector.Runtime) error {
 		if !ok {
 			return errors.New("get current KubeSphere version failed by pipeline cache")
 		}
-		fmt.Printf("kubesphere version: %s to %s\n", currentKsVersion, u.KubeConf.Cluster.KubeSphere.Version)
+		fmt.Fprintf(out, "kubesphere version: %s to %s\n", currentKsVersion, u.KubeConf.Cluster.KubeSphere.Version)
 	}
-	fmt.Println()
+	fmt.Fprintln(out)
 
 	reader := bufio.NewReader(os.Stdin)
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Continue upgrading KubeSphere? [yes/no]: ")
+		fmt.Fprintf(out, "Continue upgrading KubeSphere? [yes/no]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return err
@@ -174,6 +176,7 @@ type CreateK8sConfirm struct {
 }
 
 func (c *CreateK8sConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	var (
 		results  []confirm.PreCheckResults
 		stopFlag bool
@@ -193,7 +196,7 @@ func (c *CreateK8sConfirm) Execute(runtime connector.Runtime) error {
 		_ = mapstructure.Decode(pre[node], &result)
 		results = append(results, result)
 	}
-	table.OutputA(results)
+	fmt.Fprintln(out, table.AsciiTable(results))
 	reader := bufio.NewReader(os.Stdin)
 
 	if c.KubeConf.Arg.Artifact == "" {
@@ -215,22 +218,22 @@ func (c *CreateK8sConfirm) Execute(runtime connector.Runtime) error {
 		}
 	}
 
-	fmt.Println("")
-	fmt.Println("This is a simple check of your environment.")
-	fmt.Println("Before installation, ensure that your machines meet all requirements specified at")
-	fmt.Println("https://github.com/kubesphere/kubekey#requirements-and-recommendations")
-	fmt.Println("")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "This is a simple check of your environment.")
+	fmt.Fprintln(out, "Before installation, ensure that your machines meet all requirements specified at")
+	fmt.Fprintln(out, "https://github.com/kubesphere/kubekey#requirements-and-recommendations")
+	fmt.Fprintln(out, "")
 
 	if k8sVersion, err := versionutil.ParseGeneric(c.KubeConf.Cluster.Kubernetes.Version); err == nil {
 		if k8sVersion.AtLeast(versionutil.MustParseSemantic("v1.24.0")) && c.KubeConf.Cluster.Kubernetes.ContainerManager == common.Docker {
-			fmt.Println("[Notice]")
-			fmt.Println("Incorrect runtime. Please specify a container runtime other than Docker to install Kubernetes v1.24 or later.")
-			fmt.Println("You can set \"spec.kubernetes.containerManager\" in the configuration file to \"containerd\" or add \"--container-manager containerd\" to the \"./kk create cluster\" command.")
-			fmt.Println("For more information, see:")
-			fmt.Println("https://github.com/kubesphere/kubekey/blob/master/docs/commands/kk-create-cluster.md")
-			fmt.Println("https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
-			fmt.Println("https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
-			fmt.Println("")
+			fmt.Fprintln(out, "[Notice]")
+			fmt.Fprintln(out, "Incorrect runtime. Please specify a container runtime other than Docker to install Kubernetes v1.24 or later.")
+			fmt.Fprintln(out, "You can set \"spec.kubernetes.containerManager\" in the configuration file to \"containerd\" or add \"--container-manager containerd\" to the \"./kk create cluster\" command.")
+			fmt.Fprintln(out, "For more information, see:")
+			fmt.Fprintln(out, "https://github.com/kubesphere/kubekey/blob/master/docs/commands/kk-create-cluster.md")
+			fmt.Fprintln(out, "https://kubernetes.io/docs/setup/production-environment/container-runtimes/#container-runtimes")
+			fmt.Fprintln(out, "https://kubernetes.io/blog/2022/02/17/dockershim-faq/")
+			fmt.Fprintln(out, "")
 			stopFlag = true
 		}
 	}
@@ -241,7 +244,7 @@ func (c *CreateK8sConfirm) Execute(runtime connector.Runtime) error {
 
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Continue this init the cluster? [yes/no]: ")
+		fmt.Fprintf(out, "Continue this init the cluster? [yes/no]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			logger.Log.Fatal(err)
@@ -265,6 +268,7 @@ type CreateKsConfirm struct {
 }
 
 func (u *CreateKsConfirm) Execute(runtime connector.Runtime) error {
+	out := runtime.GetEvents().PromptOutput()
 	pre := make([]map[string]string, len(runtime.GetAllHosts()), len(runtime.GetAllHosts()))
 	for i, host := range runtime.GetAllHosts() {
 		if v, ok := host.GetCache().Get(common.NodePreCheck); ok {
@@ -275,14 +279,14 @@ func (u *CreateKsConfirm) Execute(runtime connector.Runtime) error {
 	}
 
 	if u.KubeConf.Cluster.KubeSphere.Enabled {
-		fmt.Printf("desired kubesphere version: %s\n", u.KubeConf.Cluster.KubeSphere.Version)
+		fmt.Fprintf(out, "desired kubesphere version: %s\n", u.KubeConf.Cluster.KubeSphere.Version)
 	}
-	fmt.Println()
+	fmt.Fprintln(out)
 
 	reader := bufio.NewReader(os.Stdin)
 	confirmOK := false
 	for !confirmOK {
-		fmt.Printf("Continue install KubeSphere? [yes/no]: ")
+		fmt.Fprintf(out, "Continue install KubeSphere? [yes/no]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return err
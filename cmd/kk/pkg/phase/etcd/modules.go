@@ -16,6 +16,10 @@ func (p *PreCheckModule) IsSkip() bool {
 	return p.Skip
 }
 
+func (p *PreCheckModule) IsCollector() bool {
+	return true
+}
+
 func (p *PreCheckModule) Init() {
 	p.Name = "ETCDPreCheckModule"
 	p.Desc = "Get ETCD cluster status"
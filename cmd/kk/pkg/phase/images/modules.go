@@ -50,6 +50,10 @@ type setBinaryCacheModule struct {
 	common.KubeModule
 }
 
+func (p *setBinaryCacheModule) IsCollector() bool {
+	return true
+}
+
 func (p *setBinaryCacheModule) Init() {
 	p.Name = "setBinaryCacheModule"
 	p.Desc = "set the docker and containerd binary paths in cache"
@@ -54,10 +54,10 @@ func NewAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&os.ConfigureOSModule{Skip: runtime.Cluster.System.SkipConfigureOS},
 		&customscripts.CustomScriptsModule{Phase: "PreInstall", Scripts: runtime.Cluster.System.PreInstall},
 		&registry.RegistryCertsModule{Skip: len(runtime.GetHostsByRole(common.Registry)) == 0},
+		&kubernetes.StatusModule{},
 		//for one master to multi master kube-vip
 		&loadbalancer.KubevipModule{Skip: !runtime.Cluster.ControlPlaneEndpoint.IsInternalLBEnabledVip()},
 		&kubernetes.RestartKubeletModule{},
-		&kubernetes.StatusModule{},
 		&container.InstallContainerModule{},
 		&images.PullModule{Skip: runtime.Arg.SkipPullImages},
 		&etcd.PreCheckModule{Skip: runtime.Cluster.Etcd.Type != kubekeyapiv1alpha2.KubeKey},
@@ -71,13 +71,20 @@ func NewAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&kubernetes.ConfigureKubernetesModule{},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
+		&kubernetes.SaveClusterConfigModule{},
 		&customscripts.CustomScriptsModule{Phase: "PostInstall", Scripts: runtime.Cluster.System.PostInstall},
 	}
 
+	journal, err := newCheckpoint(runtime, "AddNodesPipeline")
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
-		Name:    "AddNodesPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "AddNodesPipeline",
+		Modules:    customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
+		Runtime:    runtime,
+		Checkpoint: journal,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -111,10 +118,16 @@ func NewK3sAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&customscripts.CustomScriptsModule{Phase: "PostInstall", Scripts: runtime.Cluster.System.PostInstall},
 	}
 
+	journal, err := newCheckpoint(runtime, "AddNodesPipeline")
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
-		Name:    "AddNodesPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "AddNodesPipeline",
+		Modules:    customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
+		Runtime:    runtime,
+		Checkpoint: journal,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -147,10 +160,16 @@ func NewK8eAddNodesPipeline(runtime *common.KubeRuntime) error {
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 	}
 
+	journal, err := newCheckpoint(runtime, "AddNodesPipeline")
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
-		Name:    "AddNodesPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "AddNodesPipeline",
+		Modules:    customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
+		Runtime:    runtime,
+		Checkpoint: journal,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -160,15 +179,18 @@ func NewK8eAddNodesPipeline(runtime *common.KubeRuntime) error {
 }
 
 func AddNodes(args common.Argument, downloadCmd string) error {
-	args.DownloadCommand = func(path, url string) string {
-		// this is an extension point for downloading tools, for example users can set the timeout, proxy or retry under
-		// some poor network environment. Or users even can choose another cli, it might be wget.
-		// perhaps we should have a build-in download function instead of totally rely on the external one
-		return fmt.Sprintf(downloadCmd, path, url)
+	if downloadCmd != "" {
+		// the user defined command is an extension point for downloading tools, for example users can choose
+		// another cli, it might be wget. The built-in downloader is used if it is not set.
+		args.DownloadCommand = func(path, url string) string {
+			return fmt.Sprintf(downloadCmd, path, url)
+		}
 	}
 
 	var loaderType string
-	if args.FilePath != "" {
+	if args.FromCluster {
+		loaderType = common.InCluster
+	} else if args.FilePath != "" {
 		loaderType = common.File
 	} else {
 		loaderType = common.AllInOne
@@ -0,0 +1,77 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/addons"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/k3s"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/k8e"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
+)
+
+func SyncAddonsPipeline(runtime *common.KubeRuntime) error {
+	var status module.Module
+	switch runtime.Cluster.Kubernetes.Type {
+	case common.K3s:
+		status = &k3s.StatusModule{}
+	case common.K8e:
+		status = &k8e.StatusModule{}
+	default:
+		status = &kubernetes.StatusModule{}
+	}
+
+	m := []module.Module{
+		&precheck.GreetingsModule{},
+		status,
+		&addons.SyncModule{},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "SyncAddonsPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SyncAddons(args common.Argument) error {
+	var loaderType string
+	if args.FromCluster {
+		loaderType = common.InCluster
+	} else if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	if err := SyncAddonsPipeline(runtime); err != nil {
+		return err
+	}
+	return nil
+}
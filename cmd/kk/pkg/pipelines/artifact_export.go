@@ -21,6 +21,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/addons"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/artifact"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/binaries"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/confirm"
@@ -36,7 +37,10 @@ func NewArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 		&confirm.CheckFileExistModule{FileName: runtime.Arg.Output},
 		&images.CopyImagesToLocalModule{},
 		&binaries.ArtifactBinariesModule{},
+		&addons.ArtifactChartsModule{},
 		&artifact.RepositoryModule{},
+		&artifact.DeltaModule{Skip: runtime.Arg.Base == ""},
+		&artifact.ChecksumModule{},
 		&artifact.ArchiveModule{},
 		&filesystem.ChownOutputModule{},
 		&filesystem.ChownWorkDirModule{},
@@ -60,7 +64,10 @@ func NewK3sArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 		&confirm.CheckFileExistModule{FileName: runtime.Arg.Output},
 		&images.CopyImagesToLocalModule{},
 		&binaries.K3sArtifactBinariesModule{},
+		&addons.ArtifactChartsModule{},
 		&artifact.RepositoryModule{},
+		&artifact.DeltaModule{Skip: runtime.Arg.Base == ""},
+		&artifact.ChecksumModule{},
 		&artifact.ArchiveModule{},
 		&filesystem.ChownOutputModule{},
 		&filesystem.ChownWorkDirModule{},
@@ -84,7 +91,10 @@ func NewK8eArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 		&confirm.CheckFileExistModule{FileName: runtime.Arg.Output},
 		&images.CopyImagesToLocalModule{},
 		&binaries.K8eArtifactBinariesModule{},
+		&addons.ArtifactChartsModule{},
 		&artifact.RepositoryModule{},
+		&artifact.DeltaModule{Skip: runtime.Arg.Base == ""},
+		&artifact.ChecksumModule{},
 		&artifact.ArchiveModule{},
 		&filesystem.ChownOutputModule{},
 		&filesystem.ChownWorkDirModule{},
@@ -104,11 +114,12 @@ func NewK8eArtifactExportPipeline(runtime *common.ArtifactRuntime) error {
 }
 
 func ArtifactExport(args common.ArtifactArgument, downloadCmd string) error {
-	args.DownloadCommand = func(path, url string) string {
-		// this is an extension point for downloading tools, for example users can set the timeout, proxy or retry under
-		// some poor network environment. Or users even can choose another cli, it might be wget.
-		// perhaps we should have a build-in download function instead of totally rely on the external one
-		return fmt.Sprintf(downloadCmd, path, url)
+	if downloadCmd != "" {
+		// the user defined command is an extension point for downloading tools, for example users can choose
+		// another cli, it might be wget. The built-in downloader is used if it is not set.
+		args.DownloadCommand = func(path, url string) string {
+			return fmt.Sprintf(downloadCmd, path, url)
+		}
 	}
 
 	runtime, err := common.NewArtifactRuntime(args)
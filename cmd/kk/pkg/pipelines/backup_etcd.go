@@ -0,0 +1,80 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"github.com/pkg/errors"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/etcd"
+)
+
+func BackupETCDPipeline(runtime *common.KubeRuntime) error {
+	m := []module.Module{
+		&precheck.GreetingsModule{},
+		&etcd.SnapshotModule{},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "BackupETCDPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func BackupETCD(args common.Argument) error {
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	switch runtime.Cluster.Etcd.Type {
+	case kubekeyapiv1alpha2.KubeKey:
+		if len(runtime.GetHostsByRole(common.ETCD)) == 0 {
+			return errors.New("no etcd node is found in the cluster configuration")
+		}
+	case kubekeyapiv1alpha2.Kubeadm:
+		if len(runtime.GetHostsByRole(common.Master)) == 0 {
+			return errors.New("no master node is found in the cluster configuration")
+		}
+	default:
+		return errors.Errorf("only the etcd cluster of kubekey or kubeadm can be backed up, the etcd type is %s", runtime.Cluster.Etcd.Type)
+	}
+	if err := etcd.ValidateBackupTarget(runtime.Cluster.Etcd.BackupTarget); err != nil {
+		return err
+	}
+
+	if err := BackupETCDPipeline(runtime); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/checkpoint"
+)
+
+// newCheckpoint returns the checkpoint journal of the pipeline, which is stored in the work dir.
+func newCheckpoint(runtime *common.KubeRuntime, pipelineName string) (*checkpoint.Journal, error) {
+	hash, err := runtime.ConfigHash()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(runtime.GetWorkDir(), common.CheckpointDir,
+		fmt.Sprintf("%s-%s.json", runtime.ClusterName, pipelineName))
+	return checkpoint.NewJournal(path, pipelineName, hash, runtime.Arg.Resume)
+}
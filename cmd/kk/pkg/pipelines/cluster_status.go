@@ -0,0 +1,73 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/status"
+)
+
+func ClusterStatusPipeline(runtime *common.KubeRuntime, report *status.Report) error {
+	m := []module.Module{
+		&status.ClusterStatusModule{Report: report},
+	}
+
+	p := pipeline.Pipeline{
+		Name:    "ClusterStatusPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClusterStatus prints the status report of the cluster, it is the payload of the result event with the json output.
+// An error is returned if the cluster is unhealthy.
+func ClusterStatus(args common.Argument) error {
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+	report := &status.Report{}
+	if err := ClusterStatusPipeline(runtime, report); err != nil {
+		return err
+	}
+	if events := runtime.GetEvents(); events.Streaming() {
+		events.Result(report)
+	} else if err := status.PrintReport(os.Stdout, report); err != nil {
+		return err
+	}
+	if !report.Healthy {
+		return errors.New("the cluster is unhealthy")
+	}
+	return nil
+}
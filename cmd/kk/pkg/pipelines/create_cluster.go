@@ -90,6 +90,7 @@ func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
 		&kubernetes.SecurityEnhancementModule{Skip: !runtime.Arg.SecurityEnhancement},
 		&kubernetes.SaveKubeConfigModule{},
+		&kubernetes.SaveClusterConfigModule{},
 		&plugins.DeployPluginsModule{},
 		&addons.AddonsModule{},
 		&storage.DeployLocalVolumeModule{Skip: skipLocalStorage},
@@ -98,14 +99,23 @@ func NewCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&customscripts.CustomScriptsModule{Phase: "PostInstall", Scripts: runtime.Cluster.System.PostInstall},
 	}
 
+	journal, err := newCheckpoint(runtime, "CreateClusterPipeline")
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
-		Name:    "CreateClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "CreateClusterPipeline",
+		Modules:    customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
+		Runtime:    runtime,
+		Checkpoint: journal,
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun || runtime.GetEvents().Streaming() {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -172,14 +182,23 @@ func NewK3sCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&customscripts.CustomScriptsModule{Phase: "PostInstall", Scripts: runtime.Cluster.System.PostInstall},
 	}
 
+	journal, err := newCheckpoint(runtime, "K3sCreateClusterPipeline")
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
-		Name:    "K3sCreateClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "K3sCreateClusterPipeline",
+		Modules:    customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
+		Runtime:    runtime,
+		Checkpoint: journal,
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun || runtime.GetEvents().Streaming() {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -246,14 +265,23 @@ func NewK8eCreateClusterPipeline(runtime *common.KubeRuntime) error {
 		&customscripts.CustomScriptsModule{Phase: "PostInstall", Scripts: runtime.Cluster.System.PostInstall},
 	}
 
+	journal, err := newCheckpoint(runtime, "K8eCreateClusterPipeline")
+	if err != nil {
+		return err
+	}
+
 	p := pipeline.Pipeline{
-		Name:    "K8eCreateClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "K8eCreateClusterPipeline",
+		Modules:    customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
+		Runtime:    runtime,
+		Checkpoint: journal,
 	}
 	if err := p.Start(); err != nil {
 		return err
 	}
+	if runtime.Arg.DryRun || runtime.GetEvents().Streaming() {
+		return nil
+	}
 
 	if runtime.Cluster.KubeSphere.Enabled {
 
@@ -279,11 +307,12 @@ Please check the result using the command:
 }
 
 func CreateCluster(args common.Argument, downloadCmd string) error {
-	args.DownloadCommand = func(path, url string) string {
-		// this is an extension point for downloading tools, for example users can set the timeout, proxy or retry under
-		// some poor network environment. Or users even can choose another cli, it might be wget.
-		// perhaps we should have a build-in download function instead of totally rely on the external one
-		return fmt.Sprintf(downloadCmd, path, url)
+	if downloadCmd != "" {
+		// the user defined command is an extension point for downloading tools, for example users can choose
+		// another cli, it might be wget. The built-in downloader is used if it is not set.
+		args.DownloadCommand = func(path, url string) string {
+			return fmt.Sprintf(downloadCmd, path, url)
+		}
 	}
 
 	var loaderType string
@@ -18,6 +18,7 @@ package pipelines
 
 import (
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/customscripts"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
@@ -44,7 +45,7 @@ func NewDeleteClusterPipeline(runtime *common.KubeRuntime) error {
 
 	p := pipeline.Pipeline{
 		Name:    "DeleteClusterPipeline",
-		Modules: m,
+		Modules: customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
 		Runtime: runtime,
 	}
 	if err := p.Start(); err != nil {
@@ -65,7 +66,7 @@ func NewK3sDeleteClusterPipeline(runtime *common.KubeRuntime) error {
 
 	p := pipeline.Pipeline{
 		Name:    "K3sDeleteClusterPipeline",
-		Modules: m,
+		Modules: customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
 		Runtime: runtime,
 	}
 	if err := p.Start(); err != nil {
@@ -85,7 +86,7 @@ func NewK8eDeleteClusterPipeline(runtime *common.KubeRuntime) error {
 
 	p := pipeline.Pipeline{
 		Name:    "K8eDeleteClusterPipeline",
-		Modules: m,
+		Modules: customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
 		Runtime: runtime,
 	}
 	if err := p.Start(); err != nil {
@@ -18,6 +18,7 @@ package pipelines
 
 import (
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/customscripts"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/os"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
@@ -39,7 +40,7 @@ func DeleteNodePipeline(runtime *common.KubeRuntime) error {
 
 	p := pipeline.Pipeline{
 		Name:    "DeleteNodePipeline",
-		Modules: m,
+		Modules: customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
 		Runtime: runtime,
 	}
 	if err := p.Start(); err != nil {
@@ -48,8 +48,12 @@ func MigrateCriPipeline(runtime *common.KubeRuntime) error {
 }
 
 func MigrateCri(args common.Argument, downloadCmd string) error {
-	args.DownloadCommand = func(path, url string) string {
-		return fmt.Sprintf(downloadCmd, path, url)
+	if downloadCmd != "" {
+		// the user defined command is an extension point for downloading tools, for example users can choose
+		// another cli, it might be wget. The built-in downloader is used if it is not set.
+		args.DownloadCommand = func(path, url string) string {
+			return fmt.Sprintf(downloadCmd, path, url)
+		}
 	}
 	var loaderType string
 	if args.FilePath != "" {
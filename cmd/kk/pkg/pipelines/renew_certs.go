@@ -17,16 +17,26 @@
 package pipelines
 
 import (
+	"github.com/pkg/errors"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/pipeline"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/etcd"
 )
 
 func RenewCertsPipeline(runtime *common.KubeRuntime) error {
+	// the etcd certs of kubekey are renewed before the control plane, so that kube-apiserver loads the new etcd
+	// client certs when it is restarted
+	renewETCD := runtime.Arg.RenewETCDCerts && runtime.Cluster.Etcd.Type == kubekeyapiv1alpha2.KubeKey
+
 	m := []module.Module{
 		&precheck.GreetingsModule{},
+		&etcd.PreCheckModule{Skip: !renewETCD},
+		&etcd.RenewCertsModule{Skip: !renewETCD},
 		&certs.RenewCertsModule{},
 		&certs.CheckCertsModule{},
 		&certs.PrintClusterCertsModule{},
@@ -45,7 +55,9 @@ func RenewCertsPipeline(runtime *common.KubeRuntime) error {
 
 func RenewCerts(args common.Argument) error {
 	var loaderType string
-	if args.FilePath != "" {
+	if args.FromCluster {
+		loaderType = common.InCluster
+	} else if args.FilePath != "" {
 		loaderType = common.File
 	} else {
 		loaderType = common.AllInOne
@@ -55,6 +67,9 @@ func RenewCerts(args common.Argument) error {
 	if err != nil {
 		return err
 	}
+	if args.RenewETCDCerts && runtime.Cluster.Etcd.Type == kubekeyapiv1alpha2.External {
+		return errors.New("the certs of the external etcd are not managed by KubeKey")
+	}
 
 	if err := RenewCertsPipeline(runtime); err != nil {
 		return err
@@ -0,0 +1,76 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pipelines
+
+import (
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/module"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/pipeline"
+)
+
+func RotateCAPipeline(runtime *common.KubeRuntime) error {
+	stages := []string{runtime.Arg.RotateCAStage}
+	if runtime.Arg.RotateCAStage == certs.RotateCAStageAll {
+		stages = certs.RotateCAStages
+	}
+
+	m := []module.Module{
+		&precheck.GreetingsModule{},
+	}
+	for _, stage := range stages {
+		m = append(m, &certs.RotateCAModule{Stage: stage})
+	}
+	m = append(m,
+		&certs.CheckCertsModule{},
+		&certs.PrintClusterCertsModule{},
+	)
+
+	p := pipeline.Pipeline{
+		Name:    "RotateCAPipeline",
+		Modules: m,
+		Runtime: runtime,
+	}
+	if err := p.Start(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func RotateCA(args common.Argument) error {
+	if err := certs.ValidateRotateCAStage(args.RotateCAStage); err != nil {
+		return err
+	}
+
+	var loaderType string
+	if args.FilePath != "" {
+		loaderType = common.File
+	} else {
+		loaderType = common.AllInOne
+	}
+
+	runtime, err := common.NewKubeRuntime(loaderType, args)
+	if err != nil {
+		return err
+	}
+
+	if err := RotateCAPipeline(runtime); err != nil {
+		return err
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/artifact"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/confirm"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/customscripts"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
 	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
@@ -54,12 +55,19 @@ func NewUpgradeClusterPipeline(runtime *common.KubeRuntime) error {
 		&kubernetes.ProgressiveUpgradeModule{Step: kubernetes.ToV122},
 		&filesystem.ChownModule{},
 		&certs.AutoRenewCertsModule{Skip: !runtime.Cluster.Kubernetes.EnableAutoRenewCerts()},
+		&kubernetes.SaveClusterConfigModule{},
+	}
+
+	journal, err := newCheckpoint(runtime, "UpgradeClusterPipeline")
+	if err != nil {
+		return err
 	}
 
 	p := pipeline.Pipeline{
-		Name:    "UpgradeClusterPipeline",
-		Modules: m,
-		Runtime: runtime,
+		Name:       "UpgradeClusterPipeline",
+		Modules:    customscripts.AttachHooks(m, runtime.Cluster.System.Hooks),
+		Runtime:    runtime,
+		Checkpoint: journal,
 	}
 	if err := p.Start(); err != nil {
 		return err
@@ -68,15 +76,18 @@ func NewUpgradeClusterPipeline(runtime *common.KubeRuntime) error {
 }
 
 func UpgradeCluster(args common.Argument, downloadCmd string) error {
-	args.DownloadCommand = func(path, url string) string {
-		// this is an extension point for downloading tools, for example users can set the timeout, proxy or retry under
-		// some poor network environment. Or users even can choose another cli, it might be wget.
-		// perhaps we should have a build-in download function instead of totally rely on the external one
-		return fmt.Sprintf(downloadCmd, path, url)
+	if downloadCmd != "" {
+		// the user defined command is an extension point for downloading tools, for example users can choose
+		// another cli, it might be wget. The built-in downloader is used if it is not set.
+		args.DownloadCommand = func(path, url string) string {
+			return fmt.Sprintf(downloadCmd, path, url)
+		}
 	}
 
 	var loaderType string
-	if args.FilePath != "" {
+	if args.FromCluster {
+		loaderType = common.InCluster
+	} else if args.FilePath != "" {
 		loaderType = common.File
 	} else {
 		loaderType = common.AllInOne
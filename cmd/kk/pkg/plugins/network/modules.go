@@ -131,7 +131,10 @@ func deployCalico(d *DeployNetworkPluginModule) []task.Interface {
 			Template: templates.CalicoNew,
 			Dst:      filepath.Join(common.KubeConfigDir, templates.CalicoNew.Name()),
 			Data: util.Data{
-				"KubePodsCIDR":            d.KubeConf.Cluster.Network.KubePodsCIDR,
+				"KubePodsCIDR":            d.KubeConf.Cluster.Network.KubePodsIPv4CIDR(),
+				"KubePodsIPv6CIDR":        d.KubeConf.Cluster.Network.KubePodsIPv6CIDR(),
+				"IPv4Enabled":             d.KubeConf.Cluster.Network.KubePodsIPv4CIDR() != "",
+				"IPv6Enabled":             d.KubeConf.Cluster.Network.KubePodsIPv6CIDR() != "",
 				"CalicoCniImage":          images.GetImage(d.Runtime, d.KubeConf, "calico-cni").ImageName(),
 				"CalicoNodeImage":         images.GetImage(d.Runtime, d.KubeConf, "calico-node").ImageName(),
 				"CalicoFlexvolImage":      images.GetImage(d.Runtime, d.KubeConf, "calico-flexvol").ImageName(),
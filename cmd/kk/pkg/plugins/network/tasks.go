@@ -89,8 +89,16 @@ func (d *DeployCilium) Execute(runtime connector.Runtime) error {
 	cmd := fmt.Sprintf("/usr/local/bin/helm upgrade --install cilium /etc/kubernetes/cilium.tgz --namespace kube-system "+
 		"--set operator.image.override=%s "+
 		"--set operator.replicas=1 "+
-		"--set image.override=%s "+
-		"--set ipam.operator.clusterPoolIPv4PodCIDR=%s", ciliumOperatorImage, ciliumImage, d.KubeConf.Cluster.Network.KubePodsCIDR)
+		"--set image.override=%s", ciliumOperatorImage, ciliumImage)
+
+	if ipv4 := d.KubeConf.Cluster.Network.KubePodsIPv4CIDR(); ipv4 != "" {
+		cmd = fmt.Sprintf("%s --set ipam.operator.clusterPoolIPv4PodCIDR=%s", cmd, ipv4)
+	} else {
+		cmd = fmt.Sprintf("%s --set ipv4.enabled=false", cmd)
+	}
+	if ipv6 := d.KubeConf.Cluster.Network.KubePodsIPv6CIDR(); ipv6 != "" {
+		cmd = fmt.Sprintf("%s --set ipv6.enabled=true --set ipam.operator.clusterPoolIPv6PodCIDR=%s", cmd, ipv6)
+	}
 
 	if d.KubeConf.Cluster.Kubernetes.DisableKubeProxy {
 		cmd = fmt.Sprintf("%s --set kubeProxyReplacement=strict --set k8sServiceHost=%s --set k8sServicePort=%d", cmd, d.KubeConf.Cluster.ControlPlaneEndpoint.Address, d.KubeConf.Cluster.ControlPlaneEndpoint.Port)
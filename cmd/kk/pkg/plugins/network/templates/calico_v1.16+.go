@@ -114,7 +114,13 @@ data:
           "nodename": "__KUBERNETES_NODE_NAME__",
           "mtu": __CNI_MTU__,
           "ipam": {
+{{- if .IPv6Enabled }}
+              "type": "calico-ipam",
+              "assign_ipv4": "{{ .IPv4Enabled }}",
+              "assign_ipv6": "true"
+{{- else }}
               "type": "calico-ipam"
+{{- end }}
           },
           "policy": {
               "type": "k8s"
@@ -4846,10 +4852,22 @@ spec:
               valueFrom:
                 fieldRef:
                   fieldPath: status.hostIP
+{{- if .IPv4Enabled }}
             - name: IP_AUTODETECTION_METHOD
               value: "can-reach=$(NODEIP)"
             - name: IP
               value: "autodetect"
+{{- else }}
+            # The nodes have no IPv4 address in an IPv6 single-stack cluster.
+            - name: IP
+              value: "none"
+            - name: IP6_AUTODETECTION_METHOD
+              value: "can-reach=$(NODEIP)"
+{{- end }}
+{{- if .IPv6Enabled }}
+            - name: IP6
+              value: "autodetect"
+{{- end }}
             # Enable IPIP
             - name: CALICO_IPV4POOL_IPIP
               value: "{{ .IPIPMode }}"
@@ -4885,6 +4903,7 @@ spec:
                   name: calico-config
                   key: veth_mtu
 {{- if .DefaultIPPOOL }}
+{{- if .IPv4Enabled }}
             # The default IPv4 pool to create on startup if none exists. Pod IPs will be
             # chosen from this range. Changing this value after installation will have
             # no effect.
@@ -4892,6 +4911,14 @@ spec:
               value: "{{ .KubePodsCIDR }}"
             - name: CALICO_IPV4POOL_BLOCK_SIZE
               value: "{{ .NodeCidrMaskSize }}"
+{{- end }}
+{{- if .IPv6Enabled }}
+            # The default IPv6 pool of the dual-stack or IPv6 single-stack cluster.
+            - name: CALICO_IPV6POOL_CIDR
+              value: "{{ .KubePodsIPv6CIDR }}"
+            - name: CALICO_IPV6POOL_NAT_OUTGOING
+              value: "true"
+{{- end }}
 {{- else }}
             - name: NO_DEFAULT_POOLS
               value: "true"
@@ -4905,9 +4932,9 @@ spec:
             # Set Felix endpoint to host default action to ACCEPT.
             - name: FELIX_DEFAULTENDPOINTTOHOSTACTION
               value: "ACCEPT"
-            # Disable IPv6 on Kubernetes.
+            # Enable IPv6 on Kubernetes only if the pods have IPv6 addresses.
             - name: FELIX_IPV6SUPPORT
-              value: "false"
+              value: "{{ .IPv6Enabled }}"
             - name: FELIX_HEALTHENABLED
               value: "true"
             - name: FELIX_DEVICEROUTESOURCEADDRESS
@@ -0,0 +1,121 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status
+
+import (
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/bootstrap/precheck"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/task"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
+)
+
+// ClusterStatusModule collects the status of the cluster into the report. The errors of the nodes are ignored, the
+// status which is not collected is reported as unknown.
+type ClusterStatusModule struct {
+	common.KubeModule
+	Report *Report
+}
+
+func (c *ClusterStatusModule) Init() {
+	c.Name = "ClusterStatusModule"
+	c.Desc = "Get the status of the cluster"
+
+	c.PipelineCache.GetOrSet(common.ClusterStatus, kubernetes.NewKubernetesStatus())
+
+	clusterStatus := &task.RemoteTask{
+		Name:        "GetClusterStatus",
+		Desc:        "Get kubernetes cluster status",
+		Hosts:       c.Runtime.GetHostsByRole(common.Master),
+		Prepare:     new(common.OnlyFirstMaster),
+		Action:      &kubernetes.GetClusterStatus{SkipJoinInfo: true},
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	nodesStatus := &task.RemoteTask{
+		Name:        "GetKubernetesNodesStatus",
+		Desc:        "Get kubernetes nodes status",
+		Hosts:       c.Runtime.GetHostsByRole(common.Master),
+		Prepare:     new(common.OnlyFirstMaster),
+		Action:      new(precheck.GetKubernetesNodesStatus),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	apiServerVersion := &task.RemoteTask{
+		Name:        "GetAPIServerVersion",
+		Desc:        "Get the version of kube-apiserver",
+		Hosts:       c.Runtime.GetHostsByRole(common.Master),
+		Action:      new(GetAPIServerVersion),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	nodeServices := &task.RemoteTask{
+		Name:        "GetNodeServices",
+		Desc:        "Get the state of kubelet and container runtime",
+		Hosts:       c.Runtime.GetHostsByRole(common.K8s),
+		Action:      new(GetNodeServices),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	etcdStatus := &task.RemoteTask{
+		Name:        "GetETCDMemberStatus",
+		Desc:        "Get the health and db size of etcd members",
+		Hosts:       etcdHosts(c.Runtime, c.KubeConf.Cluster.Etcd.Type),
+		Action:      new(GetETCDMemberStatus),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	clusterCerts := &task.RemoteTask{
+		Name:        "CheckClusterCerts",
+		Desc:        "Check cluster certs",
+		Hosts:       c.Runtime.GetHostsByRole(common.Master),
+		Action:      new(certs.ListClusterCerts),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	endpoint := &task.RemoteTask{
+		Name:        "CheckControlPlaneEndpoint",
+		Desc:        "Check the control plane endpoint on nodes",
+		Hosts:       c.Runtime.GetHostsByRole(common.K8s),
+		Action:      new(CheckEndpoint),
+		Parallel:    true,
+		IgnoreError: true,
+	}
+
+	report := &task.LocalTask{
+		Name:   "GenerateStatusReport",
+		Desc:   "Generate the status report of the cluster",
+		Action: &GenerateReport{Report: c.Report},
+	}
+
+	c.Tasks = []task.Interface{
+		clusterStatus,
+		nodesStatus,
+		apiServerVersion,
+		nodeServices,
+		etcdStatus,
+		clusterCerts,
+		endpoint,
+		report,
+	}
+}
@@ -0,0 +1,295 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	ServiceActive = "active"
+	NodeReady     = "Ready"
+	Unknown       = "Unknown"
+
+	APIServer = "kube-apiserver"
+	Kubelet   = "kubelet"
+)
+
+// Report is the status of a cluster.
+type Report struct {
+	Healthy      bool                `json:"healthy"`
+	Version      string              `json:"version"`
+	Nodes        []NodeStatus        `json:"nodes"`
+	Etcd         []EtcdMemberStatus  `json:"etcd"`
+	Certificates []CertificateStatus `json:"certificates"`
+	Endpoints    []EndpointStatus    `json:"endpoints"`
+	Versions     []VersionStatus     `json:"versions"`
+}
+
+// NodeStatus is the status of a kubernetes node and its services.
+type NodeStatus struct {
+	Name             string `json:"name"`
+	Roles            string `json:"roles"`
+	Status           string `json:"status"`
+	Kubelet          string `json:"kubelet"`
+	ContainerRuntime string `json:"containerRuntime"`
+	RuntimeService   string `json:"runtimeService"`
+}
+
+// EtcdMemberStatus is the status of an etcd member.
+type EtcdMemberStatus struct {
+	Node     string `json:"node"`
+	Endpoint string `json:"endpoint"`
+	Healthy  bool   `json:"healthy"`
+	Leader   bool   `json:"leader"`
+	Version  string `json:"version"`
+	DBSize   int64  `json:"dbSize"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CertificateStatus is the expiration of a certificate on a master.
+type CertificateStatus struct {
+	Node      string `json:"node"`
+	Name      string `json:"name"`
+	Authority string `json:"authority,omitempty"`
+	Expires   string `json:"expires"`
+	Residual  string `json:"residual"`
+	Expired   bool   `json:"expired"`
+}
+
+// EndpointStatus is the reachability of the control plane endpoint from a node.
+type EndpointStatus struct {
+	Node         string `json:"node"`
+	Endpoint     string `json:"endpoint"`
+	LoadBalancer string `json:"loadBalancer,omitempty"`
+	Reachable    bool   `json:"reachable"`
+	Error        string `json:"error,omitempty"`
+}
+
+// VersionStatus is the version of a component on a node, Skew explains why the version violates the version skew
+// policy.
+type VersionStatus struct {
+	Component string `json:"component"`
+	Node      string `json:"node"`
+	Version   string `json:"version"`
+	Skew      string `json:"skew,omitempty"`
+}
+
+// kubeNode is a node listed by kubectl get node -o wide.
+type kubeNode struct {
+	status  string
+	roles   string
+	version string
+	runtime string
+}
+
+// parseNodes parses the output of kubectl get node -o wide. The OS image may contain spaces, so only the leading
+// columns and the last column (the container runtime) are used.
+func parseNodes(output string) map[string]kubeNode {
+	nodes := make(map[string]kubeNode)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[0] == "NAME" {
+			continue
+		}
+		nodes[fields[0]] = kubeNode{
+			status:  fields[1],
+			roles:   fields[2],
+			version: fields[4],
+			runtime: fields[len(fields)-1],
+		}
+	}
+	return nodes
+}
+
+// parseEtcdStatus sets the version, db size and leadership of the member from the output of
+// etcdctl endpoint status -w json.
+func parseEtcdStatus(output string, member *EtcdMemberStatus) error {
+	var statuses []struct {
+		Endpoint string `json:"Endpoint"`
+		Status   struct {
+			Header struct {
+				MemberID uint64 `json:"member_id"`
+			} `json:"header"`
+			Version string `json:"version"`
+			DBSize  int64  `json:"dbSize"`
+			Leader  uint64 `json:"leader"`
+		} `json:"Status"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &statuses); err != nil {
+		return errors.Wrap(err, "parse the etcd endpoint status failed")
+	}
+	if len(statuses) == 0 {
+		return errors.New("no etcd endpoint status is found")
+	}
+
+	s := statuses[0]
+	member.Endpoint = s.Endpoint
+	member.Version = s.Status.Version
+	member.DBSize = s.Status.DBSize
+	member.Leader = s.Status.Header.MemberID != 0 && s.Status.Header.MemberID == s.Status.Leader
+	return nil
+}
+
+// checkVersionSkew checks the versions against the version skew policy of kubernetes: the kube-apiservers are within
+// one minor version, and a kubelet is not newer than the oldest kube-apiserver and not older than it by more than
+// two minor versions (three since v1.28).
+func checkVersionSkew(apiServers, kubelets map[string]string) []VersionStatus {
+	var oldest *versionutil.Version
+	for _, v := range apiServers {
+		if parsed, err := versionutil.ParseGeneric(v); err == nil && (oldest == nil || parsed.LessThan(oldest)) {
+			oldest = parsed
+		}
+	}
+
+	versions := make([]VersionStatus, 0, len(apiServers)+len(kubelets))
+	for node, v := range apiServers {
+		s := VersionStatus{Component: APIServer, Node: node, Version: v}
+		if parsed, err := versionutil.ParseGeneric(v); err != nil {
+			s.Skew = fmt.Sprintf("unknown version %q", v)
+		} else if minorSkew(parsed, oldest) > 1 {
+			s.Skew = fmt.Sprintf("more than 1 minor version newer than kube-apiserver %s", oldest)
+		}
+		versions = append(versions, s)
+	}
+
+	for node, v := range kubelets {
+		s := VersionStatus{Component: Kubelet, Node: node, Version: v}
+		maxSkew := 2
+		if oldest != nil && oldest.AtLeast(versionutil.MustParseGeneric("v1.28.0")) {
+			maxSkew = 3
+		}
+		if parsed, err := versionutil.ParseGeneric(v); err != nil {
+			s.Skew = fmt.Sprintf("unknown version %q", v)
+		} else if oldest != nil && minorSkew(parsed, oldest) > 0 {
+			s.Skew = fmt.Sprintf("newer than kube-apiserver %s", oldest)
+		} else if oldest != nil && minorSkew(oldest, parsed) > maxSkew {
+			s.Skew = fmt.Sprintf("older than kube-apiserver %s by more than %d minor versions", oldest, maxSkew)
+		}
+		versions = append(versions, s)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Component != versions[j].Component {
+			return versions[i].Component < versions[j].Component
+		}
+		return versions[i].Node < versions[j].Node
+	})
+	return versions
+}
+
+// minorSkew returns how many minor versions a is newer than b.
+func minorSkew(a, b *versionutil.Version) int {
+	if a.Major() != b.Major() {
+		return int(a.Major()-b.Major()) * 100
+	}
+	return int(a.Minor()) - int(b.Minor())
+}
+
+// evaluate sets whether the cluster is healthy.
+func (r *Report) evaluate() {
+	r.Healthy = true
+	for _, n := range r.Nodes {
+		if n.Status != NodeReady || n.Kubelet != ServiceActive || n.RuntimeService != ServiceActive {
+			r.Healthy = false
+		}
+	}
+	for _, m := range r.Etcd {
+		if !m.Healthy {
+			r.Healthy = false
+		}
+	}
+	for _, c := range r.Certificates {
+		if c.Expired {
+			r.Healthy = false
+		}
+	}
+	for _, e := range r.Endpoints {
+		if !e.Reachable {
+			r.Healthy = false
+		}
+	}
+	for _, v := range r.Versions {
+		if v.Skew != "" {
+			r.Healthy = false
+		}
+	}
+}
+
+// PrintReport prints the report as tables.
+func PrintReport(out io.Writer, r *Report) error {
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NODE\tROLES\tSTATUS\tKUBELET\tCONTAINER RUNTIME\tRUNTIME SERVICE")
+	for _, n := range r.Nodes {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", n.Name, n.Roles, n.Status, n.Kubelet, n.ContainerRuntime, n.RuntimeService)
+	}
+
+	if len(r.Etcd) != 0 {
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "ETCD MEMBER\tENDPOINT\tHEALTHY\tLEADER\tVERSION\tDB SIZE\tERROR")
+		for _, m := range r.Etcd {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%s\t%s\t%s\n", m.Node, m.Endpoint, m.Healthy, m.Leader, m.Version,
+				fmt.Sprintf("%.1f MiB", float64(m.DBSize)/1024/1024), oneLine(m.Error))
+		}
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "CERTIFICATE\tNODE\tEXPIRES\tRESIDUAL TIME\tCERTIFICATE AUTHORITY")
+	for _, c := range r.Certificates {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Name, c.Node, c.Expires, c.Residual, c.Authority)
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "ENDPOINT\tNODE\tLOAD BALANCER\tREACHABLE\tERROR")
+	for _, e := range r.Endpoints {
+		lb := e.LoadBalancer
+		if lb == "" {
+			lb = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", e.Endpoint, e.Node, lb, e.Reachable, oneLine(e.Error))
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "COMPONENT\tNODE\tVERSION\tSKEW")
+	for _, v := range r.Versions {
+		skew := v.Skew
+		if skew == "" {
+			skew = "ok"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Component, v.Node, v.Version, skew)
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if r.Healthy {
+		_, _ = fmt.Fprintf(w, "Kubernetes %s is healthy.\n", r.Version)
+	} else {
+		_, _ = fmt.Fprintf(w, "Kubernetes %s is unhealthy.\n", r.Version)
+	}
+	return w.Flush()
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
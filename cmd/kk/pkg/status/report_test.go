@@ -0,0 +1,144 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseNodes(t *testing.T) {
+	output := "NAME    STATUS     ROLES                  AGE   VERSION   INTERNAL-IP   EXTERNAL-IP   OS-IMAGE             KERNEL-VERSION      CONTAINER-RUNTIME\r\n" +
+		"node1   Ready      control-plane,worker   10d   v1.24.3   172.16.0.2    <none>        Ubuntu 20.04.4 LTS   5.4.0-100-generic   containerd://1.6.4\r\n" +
+		"node2   NotReady   worker                 10d   v1.23.9   172.16.0.3    <none>        Ubuntu 20.04.4 LTS   5.4.0-100-generic   containerd://1.6.4\r\n"
+
+	nodes := parseNodes(output)
+	if len(nodes) != 2 {
+		t.Fatalf("parseNodes() returns %d nodes, want 2", len(nodes))
+	}
+	want := kubeNode{status: "NotReady", roles: "worker", version: "v1.23.9", runtime: "containerd://1.6.4"}
+	if got := nodes["node2"]; got != want {
+		t.Errorf("parseNodes()[node2] = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseEtcdStatus(t *testing.T) {
+	output := `[{"Endpoint":"https://172.16.0.2:2379","Status":{"header":{"cluster_id":1,"member_id":12,"revision":100,"raft_term":2},"version":"3.4.13","dbSize":20480,"leader":12,"raftIndex":200,"raftTerm":2}}]`
+
+	member := &EtcdMemberStatus{Node: "node1"}
+	if err := parseEtcdStatus(output, member); err != nil {
+		t.Fatal(err)
+	}
+	want := EtcdMemberStatus{Node: "node1", Endpoint: "https://172.16.0.2:2379", Leader: true, Version: "3.4.13", DBSize: 20480}
+	if *member != want {
+		t.Errorf("parseEtcdStatus() = %+v, want %+v", *member, want)
+	}
+
+	if err := parseEtcdStatus("[]", member); err == nil {
+		t.Error("parseEtcdStatus() of an empty list should fail")
+	}
+}
+
+func TestCheckVersionSkew(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiServers map[string]string
+		kubelets   map[string]string
+		skewed     []string
+	}{
+		{
+			name:       "same version",
+			apiServers: map[string]string{"node1": "v1.24.3"},
+			kubelets:   map[string]string{"node1": "v1.24.3", "node2": "v1.24.3"},
+		},
+		{
+			name:       "older kubelet",
+			apiServers: map[string]string{"node1": "v1.24.3"},
+			kubelets:   map[string]string{"node1": "v1.24.3", "node2": "v1.22.1", "node3": "v1.21.1"},
+			skewed:     []string{"kubelet/node3"},
+		},
+		{
+			name:       "three minor versions since v1.28",
+			apiServers: map[string]string{"node1": "v1.28.2"},
+			kubelets:   map[string]string{"node2": "v1.25.0"},
+		},
+		{
+			name:       "newer kubelet",
+			apiServers: map[string]string{"node1": "v1.23.9", "node2": "v1.24.3"},
+			kubelets:   map[string]string{"node1": "v1.23.9", "node2": "v1.24.3"},
+			skewed:     []string{"kubelet/node2"},
+		},
+		{
+			name:       "kube-apiservers",
+			apiServers: map[string]string{"node1": "v1.22.1", "node2": "v1.24.3"},
+			skewed:     []string{"kube-apiserver/node2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var skewed []string
+			for _, v := range checkVersionSkew(tt.apiServers, tt.kubelets) {
+				if v.Skew != "" {
+					skewed = append(skewed, v.Component+"/"+v.Node)
+				}
+			}
+			if strings.Join(skewed, ",") != strings.Join(tt.skewed, ",") {
+				t.Errorf("checkVersionSkew() skewed = %v, want %v", skewed, tt.skewed)
+			}
+		})
+	}
+}
+
+func TestPrintReport(t *testing.T) {
+	r := &Report{
+		Version: "v1.24.3",
+		Nodes:   []NodeStatus{{Name: "node1", Roles: "control-plane", Status: NodeReady, Kubelet: ServiceActive, ContainerRuntime: "containerd://1.6.4", RuntimeService: ServiceActive}},
+		Etcd:    []EtcdMemberStatus{{Node: "node1", Healthy: true, DBSize: 5242880}},
+		Endpoints: []EndpointStatus{
+			{Node: "node1", Endpoint: "https://lb.kubesphere.local:6443", Reachable: false, Error: "curl: (7)\nFailed to connect"},
+		},
+	}
+	r.evaluate()
+	if r.Healthy {
+		t.Fatal("the cluster with an unreachable endpoint should be unhealthy")
+	}
+
+	var table bytes.Buffer
+	if err := PrintReport(&table, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"RUNTIME SERVICE", "5.0 MiB", "curl: (7) Failed to connect", "Kubernetes v1.24.3 is unhealthy."} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("the table does not contain %q:\n%s", want, table.String())
+		}
+	}
+
+	// the report is the payload of the result event with the json output
+	out, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Healthy || decoded.Nodes[0].Name != "node1" || decoded.Endpoints[0].Reachable {
+		t.Errorf("the decoded report = %+v", decoded)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/certs"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/common"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/connector"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/etcd"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/kubernetes"
+)
+
+const (
+	nodeServices     = "statusNodeServices"
+	apiServerVersion = "statusAPIServerVersion"
+	etcdMemberStatus = "statusETCDMemberStatus"
+	endpointStatus   = "statusEndpointStatus"
+)
+
+type services struct {
+	kubelet string
+	runtime string
+}
+
+// GetNodeServices gets the state of the kubelet and container runtime services.
+type GetNodeServices struct {
+	common.KubeAction
+}
+
+func (g *GetNodeServices) Execute(runtime connector.Runtime) error {
+	kubelet, err := serviceState(runtime, "kubelet")
+	if err != nil {
+		return err
+	}
+	cri, err := serviceState(runtime, runtimeService(g.KubeConf.Cluster.Kubernetes.ContainerManager))
+	if err != nil {
+		return err
+	}
+	runtime.RemoteHost().GetCache().Set(nodeServices, &services{kubelet: kubelet, runtime: cri})
+	return nil
+}
+
+func serviceState(runtime connector.Runtime, name string) (string, error) {
+	// systemctl is-active exits with non-zero if the service is not active, the state is printed anyway
+	state, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("systemctl is-active %s || true", name), false)
+	if err != nil {
+		return "", errors.Wrapf(errors.WithStack(err), "get the state of %s failed", name)
+	}
+	return strings.TrimSpace(state), nil
+}
+
+func runtimeService(containerManager string) string {
+	switch containerManager {
+	case common.Isula:
+		return "isulad"
+	case "":
+		return common.Docker
+	default:
+		return containerManager
+	}
+}
+
+// GetAPIServerVersion gets the version of the kube-apiserver on the master.
+type GetAPIServerVersion struct {
+	common.KubeAction
+}
+
+func (g *GetAPIServerVersion) Execute(runtime connector.Runtime) error {
+	status := kubernetes.NewKubernetesStatus()
+	if err := status.SearchVersion(runtime); err != nil {
+		return err
+	}
+	runtime.RemoteHost().GetCache().Set(apiServerVersion, strings.TrimSpace(status.Version))
+	return nil
+}
+
+// GetETCDMemberStatus gets the health, version and db size of the etcd member. The errors are recorded in the status
+// of the member, so that they are reported.
+type GetETCDMemberStatus struct {
+	common.KubeAction
+}
+
+func (g *GetETCDMemberStatus) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	member := &EtcdMemberStatus{Node: host.GetName()}
+	defer host.GetCache().Set(etcdMemberStatus, member)
+
+	etcdctl, _, err := etcd.Etcdctl(runtime, g.KubeConf.Cluster.Etcd.Type)
+	if err != nil {
+		member.Error = err.Error()
+		return nil
+	}
+	if output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s endpoint health", etcdctl), false); err != nil {
+		member.Error = cmdError(output, err)
+		return nil
+	}
+	member.Healthy = true
+
+	output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("%s endpoint status -w json", etcdctl), false)
+	if err != nil {
+		member.Error = cmdError(output, err)
+		return nil
+	}
+	if err := parseEtcdStatus(output, member); err != nil {
+		member.Error = err.Error()
+	}
+	return nil
+}
+
+// CheckEndpoint checks whether the control plane endpoint is reachable from the node. The domain of the endpoint is
+// resolved to the local haproxy, the kube-vip address or the external load balancer on the node, which is the same
+// way as the kubelet.
+type CheckEndpoint struct {
+	common.KubeAction
+}
+
+func (c *CheckEndpoint) Execute(runtime connector.Runtime) error {
+	host := runtime.RemoteHost()
+	cpe := c.KubeConf.Cluster.ControlPlaneEndpoint
+	status := &EndpointStatus{
+		Node:     host.GetName(),
+		Endpoint: fmt.Sprintf("https://%s:%d", cpe.Domain, cpe.Port),
+	}
+	if cpe.IsInternalLBEnabled() || cpe.IsInternalLBEnabledVip() {
+		status.LoadBalancer = cpe.InternalLoadbalancer
+	}
+
+	output, err := runtime.GetRunner().SudoCmd(fmt.Sprintf("curl -sk --max-time 5 %s/healthz", status.Endpoint), false)
+	switch {
+	case err != nil:
+		status.Error = cmdError(output, err)
+	case strings.TrimSpace(output) != "ok":
+		status.Error = fmt.Sprintf("the health check returns %s", strings.TrimSpace(output))
+	default:
+		status.Reachable = true
+	}
+	host.GetCache().Set(endpointStatus, status)
+	return nil
+}
+
+// cmdError returns the output of the failed command, or the exit error if nothing is printed.
+func cmdError(output string, err error) string {
+	if output = strings.TrimSpace(output); output != "" {
+		return output
+	}
+	return errors.Cause(err).Error()
+}
+
+// GenerateReport generates the report from the status collected on the nodes.
+type GenerateReport struct {
+	common.KubeAction
+	Report *Report
+}
+
+func (g *GenerateReport) Execute(runtime connector.Runtime) error {
+	if exist, ok := g.PipelineCache.GetMustBool(common.ClusterExist); ok && !exist {
+		return errors.New("no kubernetes cluster is found on the masters")
+	}
+	if v, ok := g.PipelineCache.Get(common.ClusterStatus); ok {
+		g.Report.Version = v.(*kubernetes.KubernetesStatus).Version
+	}
+
+	var nodes map[string]kubeNode
+	if v, ok := g.PipelineCache.GetMustString(common.ClusterNodeStatus); ok {
+		nodes = parseNodes(v)
+	}
+
+	kubelets := make(map[string]string)
+	for _, host := range runtime.GetHostsByRole(common.K8s) {
+		node, ok := nodes[host.GetName()]
+		if !ok {
+			node = kubeNode{status: "NotFound"}
+		} else {
+			kubelets[host.GetName()] = node.version
+		}
+		s := NodeStatus{
+			Name:             host.GetName(),
+			Roles:            node.roles,
+			Status:           node.status,
+			Kubelet:          Unknown,
+			ContainerRuntime: node.runtime,
+			RuntimeService:   Unknown,
+		}
+		if v, ok := host.GetCache().Get(nodeServices); ok {
+			s.Kubelet = v.(*services).kubelet
+			s.RuntimeService = v.(*services).runtime
+		}
+		g.Report.Nodes = append(g.Report.Nodes, s)
+
+		if v, ok := host.GetCache().Get(endpointStatus); ok {
+			g.Report.Endpoints = append(g.Report.Endpoints, *v.(*EndpointStatus))
+		} else {
+			g.Report.Endpoints = append(g.Report.Endpoints, EndpointStatus{Node: host.GetName(), Error: "the status is not collected"})
+		}
+	}
+
+	for _, host := range etcdHosts(runtime, g.KubeConf.Cluster.Etcd.Type) {
+		if v, ok := host.GetCache().Get(etcdMemberStatus); ok {
+			g.Report.Etcd = append(g.Report.Etcd, *v.(*EtcdMemberStatus))
+		} else {
+			g.Report.Etcd = append(g.Report.Etcd, EtcdMemberStatus{Node: host.GetName(), Error: "the status is not collected"})
+		}
+	}
+
+	apiServers := make(map[string]string)
+	now := time.Now()
+	for _, host := range runtime.GetHostsByRole(common.Master) {
+		if v, ok := host.GetCache().GetMustString(apiServerVersion); ok {
+			apiServers[host.GetName()] = v
+		}
+		if v, ok := host.GetCache().Get(common.Certificate); ok {
+			for _, c := range v.([]*certs.Certificate) {
+				g.Report.Certificates = append(g.Report.Certificates, CertificateStatus{
+					Node:      c.NodeName,
+					Name:      c.Name,
+					Authority: c.AuthorityName,
+					Expires:   c.Expires,
+					Residual:  c.Residual,
+					Expired:   c.NotAfter.Before(now),
+				})
+			}
+		}
+		if v, ok := host.GetCache().Get(common.CaCertificate); ok {
+			for _, c := range v.([]*certs.CaCertificate) {
+				g.Report.Certificates = append(g.Report.Certificates, CertificateStatus{
+					Node:     c.NodeName,
+					Name:     c.AuthorityName,
+					Expires:  c.Expires,
+					Residual: c.Residual,
+					Expired:  c.NotAfter.Before(now),
+				})
+			}
+		}
+	}
+	g.Report.Versions = checkVersionSkew(apiServers, kubelets)
+
+	g.Report.evaluate()
+	return nil
+}
+
+// etcdHosts returns the hosts of the etcd members, the members of kubeadm run on the masters as static pods, and the
+// external etcd is not checked.
+func etcdHosts(runtime connector.ModuleRuntime, etcdType string) []connector.Host {
+	switch etcdType {
+	case kubekeyapiv1alpha2.KubeKey:
+		return runtime.GetHostsByRole(common.ETCD)
+	case kubekeyapiv1alpha2.Kubeadm:
+		return runtime.GetHostsByRole(common.Master)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,350 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+
+	kubekeyapiv1alpha2 "github.com/kubesphere/kubekey/v3/cmd/kk/apis/kubekey/v1alpha2"
+	"github.com/kubesphere/kubekey/v3/cmd/kk/pkg/core/util"
+	versionk8s "github.com/kubesphere/kubekey/v3/cmd/kk/pkg/version/kubernetes"
+)
+
+var (
+	hostRangeRegex = regexp.MustCompile(`\[(\d+)\:(\d+)\]`)
+
+	// Roles are the roles supported under roleGroups.
+	Roles = []string{kubekeyapiv1alpha2.Etcd, kubekeyapiv1alpha2.Master, kubekeyapiv1alpha2.ControlPlane,
+		kubekeyapiv1alpha2.Worker, kubekeyapiv1alpha2.Registry}
+	// NetworkPlugins are the network plugins deployed by kk, no network plugin is deployed with none.
+	NetworkPlugins = []string{"calico", "flannel", "cilium", "kubeovn", "hybridnet", "none"}
+	// ProxyModes are the modes of kube-proxy.
+	ProxyModes = []string{"ipvs", "iptables"}
+)
+
+// ValidateCluster checks the cluster configuration before the defaults are set, and returns all the errors found
+// instead of the first one. The SSH credentials of the hosts are checked by ValidateCredentials.
+func ValidateCluster(spec *kubekeyapiv1alpha2.ClusterSpec) field.ErrorList {
+	specPath := field.NewPath("spec")
+
+	// the defaults are set on a copy, so the configuration is validated as it will be used by the pipelines.
+	defaulted := *spec
+	defaulted.Etcd = kubekeyapiv1alpha2.SetDefaultEtcdCfg(&defaulted)
+	defaulted.Network = kubekeyapiv1alpha2.SetDefaultNetworkCfg(&defaulted)
+	defaulted.Kubernetes = kubekeyapiv1alpha2.SetDefaultClusterCfg(&defaulted)
+	if defaulted.Kubernetes.ProxyMode == "" {
+		defaulted.Kubernetes.ProxyMode = kubekeyapiv1alpha2.DefaultProxyMode
+	}
+	if defaulted.Kubernetes.NodeCidrMaskSizeIPv6 == 0 {
+		defaulted.Kubernetes.NodeCidrMaskSizeIPv6 = kubekeyapiv1alpha2.DefaultNodeCidrMaskSizeIPv6
+	}
+
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateHosts(spec.Hosts, specPath.Child("hosts"))...)
+	if errs := validateRoleGroups(&defaulted, specPath.Child("roleGroups")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	} else {
+		// the role groups are grouped as the pipelines do, so that an error of the grouping is reported here
+		// instead of when the defaults are set.
+		grouped := defaulted
+		grouped.Hosts = kubekeyapiv1alpha2.SetDefaultHostsCfg(&defaulted)
+		if _, err := grouped.GroupHosts(); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("roleGroups"), field.OmitValueType{}, err.Error()))
+		}
+	}
+	allErrs = append(allErrs, validateNetwork(&defaulted, specPath)...)
+	allErrs = append(allErrs, validateKubernetes(&defaulted, specPath.Child("kubernetes"))...)
+	if err := spec.System.ValidateHooks(); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("system", "hooks"), field.OmitValueType{}, err.Error()))
+	}
+	return allErrs
+}
+
+// ToError returns an error which lists all the errors one per line, it is nil if there is no error.
+func ToError(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, "  "+err.Error())
+	}
+	return errors.Errorf("the cluster configuration is invalid:\n%s", strings.Join(messages, "\n"))
+}
+
+// ClusterWarnings returns the problems of the cluster configuration which do not stop the commands, such as an even
+// number of etcd nodes which tolerates no more failures than one node less. An existing cluster may be in such a
+// state while the nodes are added or deleted one by one.
+func ClusterWarnings(spec *kubekeyapiv1alpha2.ClusterSpec) []string {
+	warnings := make([]string, 0)
+	defaulted := *spec
+	defaulted.Etcd = kubekeyapiv1alpha2.SetDefaultEtcdCfg(&defaulted)
+	if defaulted.Etcd.Type == kubekeyapiv1alpha2.KubeKey {
+		etcd := sets.NewString()
+		for _, entry := range spec.RoleGroups[kubekeyapiv1alpha2.Etcd] {
+			// an invalid range is reported by ValidateCluster
+			names, _ := expandHostRange(entry)
+			etcd.Insert(names...)
+		}
+		if count := etcd.Len(); count != 0 && count%2 == 0 {
+			warnings = append(warnings, field.Invalid(field.NewPath("spec", "roleGroups", kubekeyapiv1alpha2.Etcd), count,
+				"the number of etcd nodes should be odd to tolerate failures").Error())
+		}
+	}
+	return warnings
+}
+
+func validateHosts(hosts []kubekeyapiv1alpha2.HostCfg, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	addresses := sets.NewString()
+	internalAddresses := sets.NewString()
+	for i, host := range hosts {
+		idxPath := fldPath.Index(i)
+		if host.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "the name of the host is required"))
+		} else if names.Has(host.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), host.Name))
+		}
+		names.Insert(host.Name)
+
+		if host.Address == "" && host.InternalAddress == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("address"), "either address or internalAddress is required"))
+		}
+		if host.Address != "" {
+			if addresses.Has(host.Address) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("address"), host.Address))
+			}
+			addresses.Insert(host.Address)
+		}
+		if host.InternalAddress != "" {
+			if internalAddresses.Has(host.InternalAddress) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("internalAddress"), host.InternalAddress))
+			}
+			internalAddresses.Insert(host.InternalAddress)
+		}
+		if host.InternalIPv6Address != "" {
+			if ip := net.ParseIP(host.InternalIPv6Address); ip == nil || ip.To4() != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("internalIPv6Address"), host.InternalIPv6Address, "must be an IPv6 address"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateCredentials checks that every host can be logged in by SSH. It is separated from ValidateCluster, because
+// the local private keys are only needed by the commands which connect to the hosts.
+func ValidateCredentials(spec *kubekeyapiv1alpha2.ClusterSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	hostsPath := field.NewPath("spec", "hosts")
+	for i, host := range spec.Hosts {
+		allErrs = append(allErrs, validateCredentials(host, hostsPath.Index(i))...)
+	}
+	return allErrs
+}
+
+// validateCredentials checks that the host can be logged in by SSH, the private key ~/.ssh/id_rsa is used if
+// neither the password nor the private key is given.
+func validateCredentials(host kubekeyapiv1alpha2.HostCfg, fldPath *field.Path) field.ErrorList {
+	if host.Password != "" || host.PrivateKey != "" {
+		return nil
+	}
+	keyPath := strings.TrimSpace(host.PrivateKeyPath)
+	if keyPath == "" {
+		keyPath = "~/.ssh/id_rsa"
+	}
+	if strings.HasPrefix(keyPath, "~/") {
+		homeDir, _ := util.Home()
+		keyPath = strings.Replace(keyPath, "~/", fmt.Sprintf("%s/", homeDir), 1)
+	}
+	if _, err := os.Stat(keyPath); err == nil {
+		return nil
+	}
+	if host.PrivateKeyPath == "" {
+		return field.ErrorList{field.Required(fldPath.Child("password"),
+			fmt.Sprintf("neither password nor privateKey is given, and the default private key %s does not exist", keyPath))}
+	}
+	return field.ErrorList{field.Invalid(fldPath.Child("privateKeyPath"), host.PrivateKeyPath, "the private key does not exist")}
+}
+
+func validateRoleGroups(spec *kubekeyapiv1alpha2.ClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	hostNames := sets.NewString()
+	for _, host := range spec.Hosts {
+		hostNames.Insert(host.Name)
+	}
+
+	members := make(map[string]sets.String)
+	// the roles are sorted, so the errors are always reported in the same order
+	for _, role := range sets.StringKeySet(spec.RoleGroups).List() {
+		hosts := spec.RoleGroups[role]
+		rolePath := fldPath.Child(role)
+		if !sets.NewString(Roles...).Has(role) {
+			allErrs = append(allErrs, field.NotSupported(rolePath, role, Roles))
+			continue
+		}
+		members[role] = sets.NewString()
+		for i, entry := range hosts {
+			names, err := expandHostRange(entry)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(rolePath.Index(i), entry, err.Error()))
+				continue
+			}
+			for _, name := range names {
+				if !hostNames.Has(name) {
+					allErrs = append(allErrs, field.NotFound(rolePath.Index(i), name))
+					continue
+				}
+				members[role].Insert(name)
+			}
+		}
+	}
+
+	if members[kubekeyapiv1alpha2.Master].Len()+members[kubekeyapiv1alpha2.ControlPlane].Len() == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child(kubekeyapiv1alpha2.ControlPlane), "at least one master/control-plane is required"))
+	}
+	if spec.Etcd.Type == kubekeyapiv1alpha2.KubeKey {
+		etcdPath := fldPath.Child(kubekeyapiv1alpha2.Etcd)
+		if members[kubekeyapiv1alpha2.Etcd].Len() == 0 {
+			allErrs = append(allErrs, field.Required(etcdPath, "at least one etcd node is required when etcd.type is kubekey"))
+		}
+	}
+	if count := members[kubekeyapiv1alpha2.Registry].Len(); count > 1 {
+		allErrs = append(allErrs, field.TooMany(fldPath.Child(kubekeyapiv1alpha2.Registry), count, 1))
+	}
+	return allErrs
+}
+
+// expandHostRange returns the host names of the entry under roleGroups, the entry is a host name or a range
+// such as node[1:3].
+func expandHostRange(entry string) ([]string, error) {
+	if !strings.Contains(entry, "[") || !strings.Contains(entry, "]") || !strings.Contains(entry, ":") {
+		return []string{entry}, nil
+	}
+	suffix := hostRangeRegex.FindStringSubmatch(entry)
+	if suffix == nil {
+		return nil, errors.New("the range of the host names must be in the form of prefix[start:end]")
+	}
+	prefix := strings.Split(entry, suffix[0])[0]
+	start, _ := strconv.Atoi(suffix[1])
+	end, _ := strconv.Atoi(suffix[2])
+	if start > end {
+		return nil, errors.Errorf("the start %d of the range is greater than the end %d", start, end)
+	}
+	names := make([]string, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		names = append(names, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return names, nil
+}
+
+func validateNetwork(spec *kubekeyapiv1alpha2.ClusterSpec, specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := specPath.Child("network")
+	if !sets.NewString(NetworkPlugins...).Has(spec.Network.Plugin) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("plugin"), spec.Network.Plugin, NetworkPlugins))
+	}
+	if spec.Network.Plugin == "calico" && spec.Network.Calico.IPIPMode != "Never" && spec.Network.Calico.VXLANMode != "Never" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("calico", "vxlanMode"), spec.Network.Calico.VXLANMode,
+			"vxlanMode must be Never when ipipMode is enabled"))
+	}
+	if err := spec.ValidateNetwork(); err != nil {
+		return append(allErrs, field.Invalid(fldPath, field.OmitValueType{}, err.Error()))
+	}
+
+	pods, _ := util.ParseCIDRs(spec.Network.KubePodsCIDR)
+	services, _ := util.ParseCIDRs(spec.Network.KubeServiceCIDR)
+	for _, service := range services {
+		for _, pod := range pods {
+			if cidrOverlap(service, pod) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("kubeServiceCIDR"), spec.Network.KubeServiceCIDR,
+					fmt.Sprintf("overlaps with the kubePodsCIDR %s", pod)))
+			}
+		}
+	}
+
+	for i, host := range spec.Hosts {
+		// the address is used as the internal address if the latter is not given
+		addresses := []hostAddress{{"internalAddress", host.InternalAddress}, {"internalIPv6Address", host.InternalIPv6Address}}
+		if host.InternalAddress == "" {
+			addresses[0] = hostAddress{"address", host.Address}
+		}
+		for _, address := range addresses {
+			ip := net.ParseIP(address.value)
+			if ip == nil {
+				continue
+			}
+			for _, cidr := range pods {
+				if cidr.Contains(ip) {
+					allErrs = append(allErrs, field.Invalid(specPath.Child("hosts").Index(i).Child(address.name), address.value,
+						fmt.Sprintf("the host network overlaps with the kubePodsCIDR %s", cidr)))
+				}
+			}
+			for _, cidr := range services {
+				if cidr.Contains(ip) {
+					allErrs = append(allErrs, field.Invalid(specPath.Child("hosts").Index(i).Child(address.name), address.value,
+						fmt.Sprintf("the host network overlaps with the kubeServiceCIDR %s", cidr)))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+type hostAddress struct {
+	name  string
+	value string
+}
+
+func cidrOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func validateKubernetes(spec *kubekeyapiv1alpha2.ClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.Kubernetes.Type == "kubernetes" {
+		versionPath := fldPath.Child("version")
+		if _, err := versionutil.ParseSemantic(spec.Kubernetes.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(versionPath, spec.Kubernetes.Version, err.Error()))
+		} else if !versionk8s.VersionSupport(spec.Kubernetes.Version) {
+			supported := make([]string, 0, len(versionk8s.VersionList))
+			for _, v := range versionk8s.VersionList {
+				supported = append(supported, v.String())
+			}
+			allErrs = append(allErrs, field.NotSupported(versionPath, spec.Kubernetes.Version, supported))
+		}
+	}
+
+	if !sets.NewString(ProxyModes...).Has(spec.Kubernetes.ProxyMode) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("proxyMode"), spec.Kubernetes.ProxyMode, ProxyModes))
+	}
+	if spec.Kubernetes.DisableKubeProxy && spec.Network.Plugin != "cilium" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("disableKubeProxy"), true,
+			fmt.Sprintf("kube-proxy can only be disabled with the network plugin cilium which replaces it, but the plugin is %s", spec.Network.Plugin)))
+	}
+	return allErrs
+}
@@ -37,6 +37,7 @@ import (
 
 	infrav1 "github.com/kubesphere/kubekey/v3/api/v1beta1"
 	"github.com/kubesphere/kubekey/v3/pkg/util/filesystem"
+	"github.com/kubesphere/kubekey/v3/util/hostkey"
 )
 
 // Default values.
@@ -55,6 +56,8 @@ type Client struct {
 	port           *int
 	privateKey     string
 	privateKeyPath string
+	hostKey        string
+	hostKeyFP      string
 	timeout        *time.Duration
 	host           string
 	sshClient      *ssh.Client
@@ -88,6 +91,8 @@ func NewClient(host string, auth infrav1.Auth, log *logr.Logger) Interface {
 		port:           auth.Port,
 		privateKey:     auth.PrivateKey,
 		privateKeyPath: auth.PrivateKeyPath,
+		hostKey:        auth.HostKey,
+		hostKeyFP:      auth.HostKeyFingerprint,
 		timeout:        auth.Timeout,
 		host:           host,
 		fs:             filesystem.NewFileSystem(),
@@ -102,13 +107,13 @@ func (c *Client) Connect() error {
 		return errors.Wrap(err, "The given SSH key could not be parsed")
 	}
 
+	// the machines have no known_hosts file, only the host key pinned by the auth configuration is verified
 	sshConfig := &ssh.ClientConfig{
-		User:    c.user,
-		Timeout: *c.timeout,
-		Auth:    authMethods,
-		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
-			return nil
-		},
+		User:              c.user,
+		Timeout:           *c.timeout,
+		Auth:              authMethods,
+		HostKeyCallback:   hostkey.Callback(c.hostKey, c.hostKeyFP),
+		HostKeyAlgorithms: hostkey.PinnedAlgorithms(c.hostKey),
 	}
 
 	endpoint := net.JoinHostPort(c.host, strconv.Itoa(*c.port))
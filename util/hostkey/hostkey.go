@@ -0,0 +1,103 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package hostkey verifies the ssh host keys pinned by the configuration of a host.
+package hostkey
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// Callback returns the ssh.HostKeyCallback which verifies the pinned host key and fingerprint. Any host key is
+// accepted if neither of them is pinned.
+func Callback(hostKey, fingerprint string) ssh.HostKeyCallback {
+	return func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		return Verify(hostname, key, hostKey, fingerprint)
+	}
+}
+
+// Verify verifies the host key against the pinned key in the authorized_keys format and the SHA256 or MD5
+// fingerprint, both of them are optional.
+func Verify(hostname string, key ssh.PublicKey, hostKey, fingerprint string) error {
+	if hostKey != "" {
+		pinned, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse the host key of %s", hostname)
+		}
+		if !bytes.Equal(pinned.Marshal(), key.Marshal()) {
+			return fmt.Errorf("host key mismatch for %s: got %s, want %s", hostname,
+				ssh.FingerprintSHA256(key), ssh.FingerprintSHA256(pinned))
+		}
+	}
+
+	if fingerprint != "" {
+		var got string
+		if strings.HasPrefix(fingerprint, "SHA256:") {
+			got = ssh.FingerprintSHA256(key)
+		} else {
+			got = ssh.FingerprintLegacyMD5(key)
+			fingerprint = strings.TrimPrefix(fingerprint, "MD5:")
+		}
+		if got != fingerprint {
+			return fmt.Errorf("host key fingerprint mismatch for %s: got %s, want %s", hostname, got, fingerprint)
+		}
+	}
+	return nil
+}
+
+// PinnedAlgorithms returns the host key algorithms of the pinned host key. It is nil if no key is pinned or the key
+// can not be parsed, the default algorithms of the ssh client are used then.
+func PinnedAlgorithms(hostKey string) []string {
+	if hostKey == "" {
+		return nil
+	}
+	pinned, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil
+	}
+	return Algorithms(pinned)
+}
+
+// Algorithms returns the host key algorithms which the server may use to present the keys, so that the server offers
+// one of the keys instead of another type which can not be verified. The RSA signatures with SHA-2 are preferred.
+func Algorithms(keys ...ssh.PublicKey) []string {
+	algorithms := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		var algos []string
+		switch key.Type() {
+		case ssh.KeyAlgoRSA:
+			algos = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		case ssh.CertAlgoRSAv01:
+			algos = []string{ssh.CertAlgoRSASHA512v01, ssh.CertAlgoRSASHA256v01, ssh.CertAlgoRSAv01}
+		default:
+			algos = []string{key.Type()}
+		}
+		for _, a := range algos {
+			if !seen[a] {
+				seen[a] = true
+				algorithms = append(algorithms, a)
+			}
+		}
+	}
+	return algorithms
+}
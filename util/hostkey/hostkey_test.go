@@ -0,0 +1,71 @@
+/*
+ Copyright 2023 The KubeSphere Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package hostkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"net"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestCallback(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := &net.TCPAddr{IP: net.ParseIP("192.168.0.2"), Port: 22}
+
+	if err := Callback("", "")("192.168.0.2:22", remote, key); err != nil {
+		t.Errorf("Callback() unexpected error without a pinned key: %v", err)
+	}
+	if err := Callback("", "SHA256:invalid")("192.168.0.2:22", remote, key); err == nil {
+		t.Error("Callback() expected a fingerprint mismatch error")
+	}
+
+	pinned := string(ssh.MarshalAuthorizedKey(key))
+	if err := Callback(pinned, ssh.FingerprintSHA256(key))("192.168.0.2:22", remote, key); err != nil {
+		t.Errorf("Callback() unexpected error with the pinned key: %v", err)
+	}
+	if got := PinnedAlgorithms(pinned); !reflect.DeepEqual(got, []string{ssh.KeyAlgoED25519}) {
+		t.Errorf("PinnedAlgorithms() = %v", got)
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	if got := Algorithms(key, key); !reflect.DeepEqual(got, want) {
+		t.Errorf("Algorithms() = %v, want %v", got, want)
+	}
+}